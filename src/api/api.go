@@ -1,6 +1,8 @@
 package api
 
 import (
+	"net/http"
+
 	"github.com/go-chi/chi/v5"
 	"github.com/go-chi/chi/v5/middleware"
 	environment "github.com/nocodeleaks/quepasa/environment"
@@ -22,11 +24,6 @@ func Configure(r chi.Router) {
 
 	// setting group
 	r.Group(func(r chi.Router) {
-		timeout := environment.Settings.API.GetAPITimeout()
-
-		// setting timeout for the group
-		r.Use(middleware.Timeout(timeout))
-
 		/* CORS TESTING
 		r.Use(cors.Handler(cors.Options{
 			//AllowedOrigins:   []string{"https://foo.com"}, // Use this to allow specific origin hosts
@@ -42,8 +39,36 @@ func Configure(r chi.Router) {
 
 		// Mount API routes under the configured prefix
 		r.Route("/"+apiPrefix, func(r chi.Router) {
-			r.Group(RegisterAPIControllers)
-			r.Group(RegisterAPIV3Controllers)
+
+			// tracing, picks up trace context of callers
+			r.Use(TelemetryMiddleware)
+
+			// sessions owned by other cluster nodes are served there
+			r.Use(ClusterMiddleware)
+
+			// recorded by the node serving the request
+			r.Use(AuditMiddleware)
+
+			// long lived streaming routes, without timeout
+			r.Group(RegisterAPIStreamControllers)
+
+			r.Group(func(r chi.Router) {
+
+				// setting timeout for the group, reloadable from config file
+				r.Use(TimeoutMiddleware)
+
+				r.Group(RegisterAPIControllers)
+				r.Group(RegisterAPIV3Controllers)
+			})
 		})
 	})
 }
+
+// TimeoutMiddleware applies the current api timeout to each request
+func TimeoutMiddleware(next http.Handler) http.Handler {
+	fn := func(w http.ResponseWriter, r *http.Request) {
+		timeout := environment.Settings.GetAPITimeout()
+		middleware.Timeout(timeout)(next).ServeHTTP(w, r)
+	}
+	return http.HandlerFunc(fn)
+}
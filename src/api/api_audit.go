@@ -0,0 +1,254 @@
+package api
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net"
+	"net/http"
+	"sort"
+	"strings"
+
+	"github.com/go-chi/chi/v5"
+	"github.com/go-chi/chi/v5/middleware"
+	environment "github.com/nocodeleaks/quepasa/environment"
+	library "github.com/nocodeleaks/quepasa/library"
+	models "github.com/nocodeleaks/quepasa/models"
+	webserver "github.com/nocodeleaks/quepasa/webserver"
+)
+
+// request bytes read for summaries, larger json bodies are not summarized
+const AuditBodyLimit = 64 << 10
+
+// response bytes kept for error messages
+const AuditResultLimit = 512
+
+// request fields copied to summaries, message contents and secrets are never recorded
+var AuditSummaryFields = []string{"action", "backup", "chatid", "disabled", "force", "groupid", "id", "key", "messageid", "phone", "role", "trackid", "url", "user", "username"}
+
+// routes changing state through GET
+var AuditGetRoutes = []string{"/command", "/scan", "/paircode"}
+
+// IsAuditedRequest returns true for requests that may change state or send messages
+func IsAuditedRequest(r *http.Request) bool {
+	switch r.Method {
+	case http.MethodGet, http.MethodHead, http.MethodOptions:
+		for _, route := range AuditGetRoutes {
+			if strings.HasSuffix(r.URL.Path, route) {
+				return true
+			}
+		}
+		return false
+	default:
+		return true
+	}
+}
+
+// AuditMiddleware records actor, action, target server, request summary and result of state changing requests
+func AuditMiddleware(next http.Handler) http.Handler {
+	fn := func(w http.ResponseWriter, r *http.Request) {
+		if !models.AuditManager.Enabled() || !IsAuditedRequest(r) {
+			next.ServeHTTP(w, r)
+			return
+		}
+
+		body := PeekJSONBody(r, AuditBodyLimit)
+
+		response := &auditResultBuffer{}
+		ww := middleware.NewWrapResponseWriter(w, r.ProtoMajor)
+		ww.Tee(response)
+		next.ServeHTTP(ww, r)
+
+		// after routing, so url path parameters are available
+		actortype, actor := GetAuditActor(r)
+		entry := &models.QpAuditEntry{
+			ActorType: actortype,
+			Actor:     actor,
+			Action:    GetAuditAction(r),
+			Token:     GetToken(r),
+			Summary:   GetAuditSummary(r, body),
+			Source:    GetAuditSource(r),
+		}
+
+		status := ww.Status()
+		if status == 0 {
+			status = http.StatusOK
+		}
+
+		entry.Success = status < http.StatusBadRequest
+		entry.Result = fmt.Sprintf("%d %s", status, http.StatusText(status))
+		if !entry.Success {
+			if message := response.GetMessage(); len(message) > 0 {
+				entry.Result += ": " + message
+			}
+		}
+
+		models.AuditManager.Record(entry)
+	}
+	return http.HandlerFunc(fn)
+}
+
+// GetAuditActor identifies who is acting: master key, server token (masked) or anonymous
+func GetAuditActor(r *http.Request) (actortype string, actor string) {
+	master := environment.Settings.API.MasterKey
+	if len(master) > 0 && strings.EqualFold(GetMasterKey(r), master) {
+		return models.AuditActorMaster, ""
+	}
+
+	token := GetToken(r)
+	if len(token) > 0 {
+		return models.AuditActorToken, library.MaskToken(token)
+	}
+
+	return models.AuditActorAnonymous, ""
+}
+
+// GetAuditAction returns method and route pattern, without api prefix, tokens on paths are not recorded
+func GetAuditAction(r *http.Request) string {
+	path := r.URL.Path
+	if routeContext := chi.RouteContext(r.Context()); routeContext != nil {
+		if pattern := routeContext.RoutePattern(); len(pattern) > 0 {
+			path = pattern
+		}
+	}
+
+	prefix := "/" + environment.Settings.API.Prefix
+	if len(prefix) > 1 {
+		path = strings.TrimPrefix(path, prefix)
+	}
+
+	return r.Method + " " + path
+}
+
+// GetAuditSource returns the remote address, X-Forwarded-For is honoured only from trusted proxies.
+// Forwarded addresses are read from the last, the first one not trusted is the client.
+func GetAuditSource(r *http.Request) string {
+	address := webserver.GetPeerAddress(r)
+	if host, _, err := net.SplitHostPort(address); err == nil {
+		address = host
+	}
+
+	settings := environment.Settings.Audit
+	if !settings.IsTrustedProxy(address) {
+		return address
+	}
+
+	forwarded := []string{}
+	for _, header := range r.Header.Values("X-Forwarded-For") {
+		forwarded = append(forwarded, strings.Split(header, ",")...)
+	}
+
+	for index := len(forwarded) - 1; index >= 0; index-- {
+		item := strings.TrimSpace(forwarded[index])
+		if len(item) == 0 {
+			continue
+		}
+
+		address = item
+		if !settings.IsTrustedProxy(address) {
+			break
+		}
+	}
+	return address
+}
+
+// PeekJSONBody reads a json body without consuming it, returns nil for other or larger bodies
+func PeekJSONBody(r *http.Request, limit int64) map[string]interface{} {
+	if r.Body == nil || !strings.Contains(r.Header.Get("Content-Type"), "json") {
+		return nil
+	}
+
+	content, err := io.ReadAll(io.LimitReader(r.Body, limit+1))
+	r.Body = struct {
+		io.Reader
+		io.Closer
+	}{io.MultiReader(bytes.NewReader(content), r.Body), r.Body}
+
+	if err != nil || int64(len(content)) > limit {
+		return nil
+	}
+
+	body := map[string]interface{}{}
+	if json.Unmarshal(content, &body) != nil {
+		return nil
+	}
+	return body
+}
+
+// GetAuditSummary collects the whitelisted request fields from body, path, query, form and headers
+func GetAuditSummary(r *http.Request, body map[string]interface{}) string {
+	values := map[string]string{}
+	for _, field := range AuditSummaryFields {
+		if value, ok := body[field]; ok {
+			switch typed := value.(type) {
+			case string, bool, float64:
+				values[field] = fmt.Sprint(typed)
+			}
+			continue
+		}
+
+		if value := GetRequestParameterForAudit(r, field); len(value) > 0 {
+			values[field] = value
+		}
+	}
+
+	items := []string{}
+	for field, value := range values {
+		if field == "url" {
+			value = library.RedactURL(value)
+		}
+		items = append(items, field+"="+value)
+	}
+
+	sort.Strings(items)
+	return strings.Join(items, "; ")
+}
+
+// GetRequestParameterForAudit reads path, query, parsed form or header, never parsing the body
+func GetRequestParameterForAudit(r *http.Request, parameter string) string {
+	if value := chi.URLParam(r, parameter); len(value) > 0 {
+		return value
+	}
+
+	if value := r.URL.Query().Get(parameter); len(value) > 0 {
+		return value
+	}
+
+	if r.Form != nil && r.Form.Has(parameter) {
+		return r.Form.Get(parameter)
+	}
+
+	return r.Header.Get("X-QUEPASA-" + strings.ToUpper(parameter))
+}
+
+// auditResultBuffer keeps the beginning of the response, for error messages
+type auditResultBuffer struct {
+	bytes.Buffer
+}
+
+func (source *auditResultBuffer) Write(p []byte) (int, error) {
+	if remaining := AuditResultLimit - source.Len(); remaining > 0 {
+		if len(p) > remaining {
+			source.Buffer.Write(p[:remaining])
+		} else {
+			source.Buffer.Write(p)
+		}
+	}
+	return len(p), nil
+}
+
+// GetMessage extracts the error message of json responses, "status" of QpResponse or "result" of errors
+func (source *auditResultBuffer) GetMessage() string {
+	response := map[string]interface{}{}
+	if json.Unmarshal(source.Bytes(), &response) != nil {
+		return ""
+	}
+
+	for _, field := range []string{"status", "result"} {
+		if message, ok := response[field].(string); ok && len(message) > 0 {
+			return message
+		}
+	}
+	return ""
+}
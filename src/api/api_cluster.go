@@ -0,0 +1,86 @@
+package api
+
+import (
+	"net/http"
+	"net/http/httputil"
+	"net/url"
+	"strings"
+
+	environment "github.com/nocodeleaks/quepasa/environment"
+	models "github.com/nocodeleaks/quepasa/models"
+	telemetry "github.com/nocodeleaks/quepasa/telemetry"
+	log "github.com/sirupsen/logrus"
+)
+
+// header set on forwarded requests, avoids forwarding loops while leases are moving
+const HeaderClusterForwarded = "X-QUEPASA-FORWARDED"
+
+// GetClusterToken finds the session token before routing, url path parameters are not parsed yet
+func GetClusterToken(r *http.Request) string {
+	token := GetToken(r)
+	if len(token) > 0 {
+		return token
+	}
+
+	// v3 routes: /v3/bot/{token}/...
+	segments := strings.Split(strings.Trim(r.URL.Path, "/"), "/")
+	for i := 0; i < len(segments)-1; i++ {
+		if segments[i] == "bot" {
+			return segments[i+1]
+		}
+	}
+	return ""
+}
+
+// ClusterMiddleware forwards (or redirects) requests for sessions owned by other cluster nodes
+func ClusterMiddleware(next http.Handler) http.Handler {
+	fn := func(w http.ResponseWriter, r *http.Request) {
+		if !models.ClusterManager.Enabled() || len(r.Header.Get(HeaderClusterForwarded)) > 0 {
+			next.ServeHTTP(w, r)
+			return
+		}
+
+		token := GetClusterToken(r)
+		if len(token) == 0 {
+			next.ServeHTTP(w, r)
+			return
+		}
+
+		// unknown or owned sessions are served here
+		server, err := models.GetServerFromToken(token)
+		if err != nil || models.ClusterManager.IsOwner(server.Token) {
+			next.ServeHTTP(w, r)
+			return
+		}
+
+		settings := environment.Settings.Cluster
+		owner, err := models.ClusterManager.GetOwner(server.Token)
+		if err != nil || owner == nil || owner.Id == settings.NodeId || len(owner.Url) == 0 {
+			next.ServeHTTP(w, r)
+			return
+		}
+
+		target, err := url.Parse(owner.Url)
+		if err != nil {
+			log.Errorf("cluster, invalid url for node: %s, %s", owner.Id, err.Error())
+			next.ServeHTTP(w, r)
+			return
+		}
+
+		if settings.Redirect {
+			location := *r.URL
+			location.Scheme = target.Scheme
+			location.Host = target.Host
+			location.Path = strings.TrimSuffix(target.Path, "/") + r.URL.Path
+			http.Redirect(w, r, location.String(), http.StatusTemporaryRedirect)
+			return
+		}
+
+		log.Debugf("cluster, forwarding request for: %s, to node: %s", server.Token, owner.Id)
+		r.Header.Set(HeaderClusterForwarded, settings.NodeId)
+		telemetry.InjectHeaders(r.Context(), r.Header)
+		proxy := httputil.NewSingleHostReverseProxy(target)
+		proxy.ServeHTTP(w, r)
+	}
+	return http.HandlerFunc(fn)
+}
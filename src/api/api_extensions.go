@@ -171,7 +171,7 @@ func GetUser(r *http.Request) (*models.QpUser, error) {
 		return nil, ex
 	}
 
-	user, err := models.WhatsappService.DB.Users.Find(username)
+	user, err := models.UserManager.Find(username)
 	if err != nil {
 		ex := &ApiExceptionBase{Inner: err}
 		ex.Prependf("error for: %s", username)
@@ -204,7 +204,7 @@ func GetUsername(r *http.Request) (string, ApiException) {
 	}
 
 	ex := &ApiExceptionBase{}
-	ex.Prependf("user not found: %s", user)
+	ex.Prepend("user not found")
 	return "", ex
 }
 
@@ -110,7 +110,7 @@ func PasswordUpdate(user string, password string) error {
 		return fmt.Errorf("user not found: %s", user)
 	}
 
-	err = models.WhatsappService.DB.Users.UpdatePassword(user, password)
+	err = models.UserManager.ResetPassword(user, password)
 	if err != nil {
 		return fmt.Errorf("error on database updating password: %s", err.Error())
 	}
@@ -0,0 +1,138 @@
+package api
+
+import (
+	"fmt"
+	"net/http"
+	"strconv"
+	"time"
+
+	library "github.com/nocodeleaks/quepasa/library"
+	models "github.com/nocodeleaks/quepasa/models"
+)
+
+//region CONTROLLER - AUDIT
+
+// AuditController queries the audit log
+//
+//	@Summary		Query audit log
+//	@Description	Returns audit entries, newest first. With master key all entries are visible, with a server token only the entries of that server.
+//	@Description	Dates accept unix seconds or RFC3339, action matches partially, ex: "POST /send".
+//	@Tags			Application
+//	@Produce		json
+//	@Param			actortype	query		string	false	"master, token, user, system or anonymous"
+//	@Param			actor		query		string	false	"Actor, ex: username or masked token"
+//	@Param			action		query		string	false	"Action contains"
+//	@Param			server		query		string	false	"Target server token, master key only"
+//	@Param			success		query		bool	false	"Result filter"
+//	@Param			from		query		string	false	"Since date"
+//	@Param			to			query		string	false	"Until date"
+//	@Param			limit		query		int		false	"Maximum entries, default 100, max 1000"
+//	@Param			offset		query		int		false	"Entries to skip"
+//	@Success		200			{object}	models.QpAuditResponse
+//	@Failure		400			{object}	models.QpResponse
+//	@Security		ApiKeyAuth
+//	@Router			/audit [get]
+func AuditController(w http.ResponseWriter, r *http.Request) {
+
+	// setting default response type as json
+	w.Header().Set("Content-Type", "application/json")
+
+	response := &models.QpAuditResponse{}
+
+	filter, err := GetAuditFilter(r)
+	if err != nil {
+		response.ParseError(err)
+		RespondInterface(w, response)
+		return
+	}
+
+	if IsMatchForMaster(r) {
+		filter.Token = library.GetRequestParameter(r, "server")
+	} else {
+		server, err := GetServer(r)
+		if err != nil {
+			response.ParseError(err)
+			RespondInterfaceCode(w, response, http.StatusUnauthorized)
+			return
+		}
+
+		// token holders only see their own server
+		filter.Token = server.Token
+	}
+
+	entries, err := models.AuditManager.Find(filter)
+	if err != nil {
+		response.ParseError(err)
+		RespondInterface(w, response)
+		return
+	}
+
+	response.Entries = entries
+	response.ParseSuccess(fmt.Sprintf("%d entries", len(entries)))
+	RespondSuccess(w, response)
+}
+
+//endregion
+
+// GetAuditFilter reads the audit query filters from request
+func GetAuditFilter(r *http.Request) (*models.QpAuditFilter, error) {
+	filter := &models.QpAuditFilter{
+		ActorType: library.GetRequestParameter(r, "actortype"),
+		Actor:     library.GetRequestParameter(r, "actor"),
+		Action:    library.GetRequestParameter(r, "action"),
+	}
+
+	if value := library.GetRequestParameter(r, "success"); len(value) > 0 {
+		success, err := strconv.ParseBool(value)
+		if err != nil {
+			return nil, fmt.Errorf("invalid success: %s", value)
+		}
+		filter.Success = &success
+	}
+
+	for _, parameter := range []string{"from", "to"} {
+		value := library.GetRequestParameter(r, parameter)
+		if len(value) == 0 {
+			continue
+		}
+
+		date, err := ParseAuditDate(value)
+		if err != nil {
+			return nil, fmt.Errorf("invalid %s: %s", parameter, value)
+		}
+
+		if parameter == "from" {
+			filter.From = &date
+		} else {
+			filter.To = &date
+		}
+	}
+
+	for _, parameter := range []string{"limit", "offset"} {
+		value := library.GetRequestParameter(r, parameter)
+		if len(value) == 0 {
+			continue
+		}
+
+		number, err := strconv.ParseUint(value, 10, 32)
+		if err != nil {
+			return nil, fmt.Errorf("invalid %s: %s", parameter, value)
+		}
+
+		if parameter == "limit" {
+			filter.Limit = uint(number)
+		} else {
+			filter.Offset = uint(number)
+		}
+	}
+
+	return filter, nil
+}
+
+// ParseAuditDate accepts unix seconds or RFC3339
+func ParseAuditDate(value string) (time.Time, error) {
+	if seconds, err := strconv.ParseInt(value, 10, 64); err == nil {
+		return time.Unix(seconds, 0).UTC(), nil
+	}
+	return time.Parse(time.RFC3339, value)
+}
@@ -0,0 +1,122 @@
+package api
+
+import (
+	"fmt"
+	"net/http"
+	"strconv"
+
+	library "github.com/nocodeleaks/quepasa/library"
+	models "github.com/nocodeleaks/quepasa/models"
+	whatsapp "github.com/nocodeleaks/quepasa/whatsapp"
+)
+
+//region CONTROLLER - BUSINESS
+
+// BusinessProfileController gets a business profile
+//
+//	@Summary		Get business profile
+//	@Description	Gets description, categories, address, email, websites and opening hours of a business account, own account if chatid is not informed
+//	@Tags			Business
+//	@Produce		json
+//	@Param			chatid	query		string	false	"Contact chat id or phone, defaults to own account"
+//	@Success		200		{object}	models.QpBusinessProfileResponse
+//	@Failure		400		{object}	models.QpResponse
+//	@Failure		503		{object}	models.QpResponse
+//	@Security		ApiKeyAuth
+//	@Router			/business/profile [get]
+func BusinessProfileController(w http.ResponseWriter, r *http.Request) {
+
+	// setting default response type as json
+	w.Header().Set("Content-Type", "application/json")
+
+	response := &models.QpBusinessProfileResponse{}
+
+	server, err := GetReadyServer(w, r, &response.QpResponse)
+	if err != nil {
+		return
+	}
+
+	var wid string
+	if chatid := library.GetChatId(r); len(chatid) > 0 {
+		wid, err = whatsapp.FormatEndpoint(chatid)
+		if err != nil {
+			response.ParseError(err)
+			RespondInterface(w, response)
+			return
+		}
+	}
+
+	profile, err := server.GetBusinessManager().GetBusinessProfile(wid)
+	if err != nil {
+		response.ParseError(err)
+		RespondInterface(w, response)
+		return
+	}
+
+	response.Profile = profile
+	response.ParseSuccess("business profile found")
+	RespondSuccess(w, response)
+}
+
+// BusinessCatalogController gets own catalog products and collections
+//
+//	@Summary		Get own catalog
+//	@Description	Gets one page of catalog products of the connected business account, collections are included on first page (without cursor)
+//	@Tags			Business
+//	@Produce		json
+//	@Param			limit	query		int		false	"Products per page and per collection, default 10, max 100"
+//	@Param			cursor	query		string	false	"Cursor returned by previous page"
+//	@Success		200		{object}	models.QpBusinessCatalogResponse
+//	@Failure		400		{object}	models.QpResponse
+//	@Failure		503		{object}	models.QpResponse
+//	@Security		ApiKeyAuth
+//	@Router			/business/catalog [get]
+func BusinessCatalogController(w http.ResponseWriter, r *http.Request) {
+
+	// setting default response type as json
+	w.Header().Set("Content-Type", "application/json")
+
+	response := &models.QpBusinessCatalogResponse{}
+
+	server, err := GetReadyServer(w, r, &response.QpResponse)
+	if err != nil {
+		return
+	}
+
+	limit := 0
+	if value := library.GetRequestParameter(r, "limit"); len(value) > 0 {
+		limit, err = strconv.Atoi(value)
+		if err != nil {
+			response.ParseError(fmt.Errorf("invalid limit parameter: %s", err.Error()))
+			RespondInterface(w, response)
+			return
+		}
+	}
+
+	cursor := library.GetRequestParameter(r, "cursor")
+
+	businessManager := server.GetBusinessManager()
+	catalog, err := businessManager.GetCatalog(limit, cursor)
+	if err != nil {
+		response.ParseError(err)
+		RespondInterface(w, response)
+		return
+	}
+
+	response.Products = catalog.Products
+	response.Cursor = catalog.Cursor
+
+	if len(cursor) == 0 {
+		response.Collections, err = businessManager.GetCollections(limit)
+		if err != nil {
+			response.ParseError(err)
+			RespondInterface(w, response)
+			return
+		}
+	}
+
+	response.ParseSuccess(fmt.Sprintf("%d products and %d collections found", len(response.Products), len(response.Collections)))
+	RespondSuccess(w, response)
+}
+
+//endregion
@@ -0,0 +1,452 @@
+package api
+
+import (
+	"encoding/csv"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"sort"
+	"strings"
+	"time"
+
+	library "github.com/nocodeleaks/quepasa/library"
+	models "github.com/nocodeleaks/quepasa/models"
+)
+
+//region CONTROLLER - CAMPAIGNS
+
+// CampaignsController lists campaigns of the server
+//
+//	@Summary		List campaigns
+//	@Description	Lists bulk campaigns of this server with recipients counters per status, newer first.
+//	@Tags			Campaign
+//	@Produce		json
+//	@Success		200	{object}	models.QpCampaignsResponse
+//	@Failure		400	{object}	models.QpResponse
+//	@Security		ApiKeyAuth
+//	@Router			/campaigns [get]
+func CampaignsController(w http.ResponseWriter, r *http.Request) {
+
+	// setting default response type as json
+	w.Header().Set("Content-Type", "application/json")
+
+	response := &models.QpCampaignsResponse{}
+
+	server, err := GetServer(r)
+	if err != nil {
+		response.ParseError(err)
+		RespondInterface(w, response)
+		return
+	}
+
+	if models.WhatsappService == nil || models.WhatsappService.DB.Campaigns == nil {
+		response.ParseError(fmt.Errorf("campaigns database not ready"))
+		RespondInterface(w, response)
+		return
+	}
+
+	campaigns, err := models.WhatsappService.DB.Campaigns.FindAll(server.Token)
+	if err != nil {
+		response.ParseError(err)
+		RespondInterface(w, response)
+		return
+	}
+
+	for _, campaign := range campaigns {
+		summary, err := models.NewQpCampaignSummary(campaign)
+		if err != nil {
+			response.ParseError(err)
+			RespondInterface(w, response)
+			return
+		}
+		response.Campaigns = append(response.Campaigns, summary)
+	}
+
+	response.Total = len(response.Campaigns)
+	response.ParseSuccess(fmt.Sprintf("%d campaigns found", response.Total))
+	RespondSuccess(w, response)
+}
+
+// CampaignCreateController creates a campaign
+//
+//	@Summary		Create campaign
+//	@Description	Creates a bulk campaign with a message template and optional media (url or base64 content).
+//	@Description	Template placeholders {{name}} are replaced by recipient variables, built in: {{chatid}} and {{phone}}.
+//	@Description	Recipients can be passed as json list (chatid or phone, variables) or as "csv" text with header, other columns are used as variables.
+//	@Description	Messages are sent with "delay" milliseconds between them (default 3000), use "start" to run right after creation.
+//	@Tags			Campaign
+//	@Accept			json
+//	@Produce		json
+//	@Param			request	body		models.QpCampaignCreateRequest	true	"Campaign request"
+//	@Success		200		{object}	models.QpCampaignResponse
+//	@Failure		400		{object}	models.QpResponse
+//	@Security		ApiKeyAuth
+//	@Router			/campaigns [post]
+func CampaignCreateController(w http.ResponseWriter, r *http.Request) {
+
+	// setting default response type as json
+	w.Header().Set("Content-Type", "application/json")
+
+	response := &models.QpCampaignResponse{}
+
+	server, err := GetServer(r)
+	if err != nil {
+		response.ParseError(err)
+		RespondInterface(w, response)
+		return
+	}
+
+	request := &models.QpCampaignCreateRequest{}
+	err = json.NewDecoder(r.Body).Decode(request)
+	if err != nil {
+		response.ParseError(fmt.Errorf("invalid json body: %s", err.Error()))
+		RespondInterface(w, response)
+		return
+	}
+
+	recipients, err := request.GetRecipients()
+	if err != nil {
+		response.ParseError(err)
+		RespondInterface(w, response)
+		return
+	}
+
+	campaign := request.ToCampaign()
+	err = models.CampaignManager.Create(server.Token, campaign, recipients)
+	if err != nil {
+		response.ParseError(err)
+		RespondInterface(w, response)
+		return
+	}
+
+	if request.Start {
+		err = models.CampaignManager.Start(campaign)
+		if err != nil {
+			response.ParseError(err)
+			RespondInterface(w, response)
+			return
+		}
+	}
+
+	RespondCampaign(w, response, campaign, "campaign created")
+}
+
+// CampaignController gets a campaign
+//
+//	@Summary		Get campaign
+//	@Description	Gets a campaign with recipients counters per status.
+//	@Tags			Campaign
+//	@Produce		json
+//	@Param			campaignid	path		string	true	"Campaign id"
+//	@Success		200			{object}	models.QpCampaignResponse
+//	@Failure		400			{object}	models.QpResponse
+//	@Security		ApiKeyAuth
+//	@Router			/campaigns/{campaignid} [get]
+func CampaignController(w http.ResponseWriter, r *http.Request) {
+
+	// setting default response type as json
+	w.Header().Set("Content-Type", "application/json")
+
+	response := &models.QpCampaignResponse{}
+
+	campaign, err := GetCampaign(r)
+	if err != nil {
+		response.ParseError(err)
+		RespondInterface(w, response)
+		return
+	}
+
+	RespondCampaign(w, response, campaign, "campaign found")
+}
+
+// CampaignDeleteController deletes a campaign
+//
+//	@Summary		Delete campaign
+//	@Description	Cancels (if running) and deletes a campaign with all recipients.
+//	@Tags			Campaign
+//	@Produce		json
+//	@Param			campaignid	path		string	true	"Campaign id"
+//	@Success		200			{object}	models.QpResponse
+//	@Failure		400			{object}	models.QpResponse
+//	@Security		ApiKeyAuth
+//	@Router			/campaigns/{campaignid} [delete]
+func CampaignDeleteController(w http.ResponseWriter, r *http.Request) {
+
+	// setting default response type as json
+	w.Header().Set("Content-Type", "application/json")
+
+	response := &models.QpResponse{}
+
+	campaign, err := GetCampaign(r)
+	if err != nil {
+		response.ParseError(err)
+		RespondInterface(w, response)
+		return
+	}
+
+	err = models.CampaignManager.Delete(campaign)
+	if err != nil {
+		response.ParseError(err)
+		RespondInterface(w, response)
+		return
+	}
+
+	response.ParseSuccess("campaign deleted")
+	RespondSuccess(w, response)
+}
+
+// CampaignActionController changes the campaign execution
+//
+//	@Summary		Start, pause, resume or cancel campaign
+//	@Description	start: runs a draft campaign | pause: stops sending, keeping queued recipients | resume: runs a paused campaign | cancel: stops sending for good
+//	@Tags			Campaign
+//	@Produce		json
+//	@Param			campaignid	path		string	true	"Campaign id"
+//	@Param			action		path		string	true	"start | pause | resume | cancel"
+//	@Success		200			{object}	models.QpCampaignResponse
+//	@Failure		400			{object}	models.QpResponse
+//	@Security		ApiKeyAuth
+//	@Router			/campaigns/{campaignid}/{action} [post]
+func CampaignActionController(w http.ResponseWriter, r *http.Request) {
+
+	// setting default response type as json
+	w.Header().Set("Content-Type", "application/json")
+
+	response := &models.QpCampaignResponse{}
+
+	campaign, err := GetCampaign(r)
+	if err != nil {
+		response.ParseError(err)
+		RespondInterface(w, response)
+		return
+	}
+
+	action := strings.ToLower(library.GetRequestParameter(r, "action"))
+	switch action {
+	case "start", "resume":
+		err = models.CampaignManager.Start(campaign)
+	case "pause":
+		err = models.CampaignManager.Pause(campaign)
+	case "cancel":
+		err = models.CampaignManager.Cancel(campaign)
+	default:
+		err = fmt.Errorf("invalid campaign action: %s", action)
+	}
+
+	if err != nil {
+		response.ParseError(err)
+		RespondInterface(w, response)
+		return
+	}
+
+	RespondCampaign(w, response, campaign, "campaign "+campaign.Status)
+}
+
+// CampaignRecipientsController lists campaign recipients
+//
+//	@Summary		List campaign recipients
+//	@Description	Lists campaign recipients with status (queued, sending, sent, delivered, read, failed), message id and error.
+//	@Tags			Campaign
+//	@Produce		json
+//	@Param			campaignid	path		string	true	"Campaign id"
+//	@Param			status		query		string	false	"Status filter"
+//	@Success		200			{object}	models.QpCampaignRecipientsResponse
+//	@Failure		400			{object}	models.QpResponse
+//	@Security		ApiKeyAuth
+//	@Router			/campaigns/{campaignid}/recipients [get]
+func CampaignRecipientsController(w http.ResponseWriter, r *http.Request) {
+
+	// setting default response type as json
+	w.Header().Set("Content-Type", "application/json")
+
+	response := &models.QpCampaignRecipientsResponse{}
+
+	campaign, err := GetCampaign(r)
+	if err != nil {
+		response.ParseError(err)
+		RespondInterface(w, response)
+		return
+	}
+
+	status := strings.ToLower(library.GetRequestParameter(r, "status"))
+	recipients, err := models.CampaignManager.GetRecipients(campaign, status)
+	if err != nil {
+		response.ParseError(err)
+		RespondInterface(w, response)
+		return
+	}
+
+	response.Recipients = recipients
+	response.Total = len(recipients)
+	response.ParseSuccess(fmt.Sprintf("%d recipients found", response.Total))
+	RespondSuccess(w, response)
+}
+
+// CampaignRecipientsUploadController appends recipients to a campaign
+//
+//	@Summary		Upload campaign recipients
+//	@Description	Appends recipients to a draft or paused campaign, already present chats are ignored.
+//	@Description	Body as csv (Content-Type: text/csv) with header, chatid or phone column required, other columns are used as variables.
+//	@Description	Or json list of {chatid, phone, variables}.
+//	@Tags			Campaign
+//	@Accept			json
+//	@Accept			text/csv
+//	@Produce		json
+//	@Param			campaignid	path		string									true	"Campaign id"
+//	@Param			request		body		[]models.QpCampaignRecipientRequest	true	"Recipients"
+//	@Success		200			{object}	models.QpCampaignRecipientsResponse
+//	@Failure		400			{object}	models.QpResponse
+//	@Security		ApiKeyAuth
+//	@Router			/campaigns/{campaignid}/recipients [post]
+func CampaignRecipientsUploadController(w http.ResponseWriter, r *http.Request) {
+
+	// setting default response type as json
+	w.Header().Set("Content-Type", "application/json")
+
+	response := &models.QpCampaignRecipientsResponse{}
+
+	campaign, err := GetCampaign(r)
+	if err != nil {
+		response.ParseError(err)
+		RespondInterface(w, response)
+		return
+	}
+
+	var recipients []*models.QpCampaignRecipient
+	if strings.Contains(strings.ToLower(r.Header.Get("Content-Type")), "csv") {
+		recipients, err = models.ParseCampaignRecipientsCSV(r.Body)
+	} else {
+		var items []models.QpCampaignRecipientRequest
+		err = json.NewDecoder(r.Body).Decode(&items)
+		if err != nil {
+			err = fmt.Errorf("invalid json body: %s", err.Error())
+		} else {
+			recipients, err = models.ParseCampaignRecipients(items)
+		}
+	}
+
+	if err != nil {
+		response.ParseError(err)
+		RespondInterface(w, response)
+		return
+	}
+
+	affected, err := models.CampaignManager.AddRecipients(campaign, recipients)
+	if err != nil {
+		response.ParseError(err)
+		RespondInterface(w, response)
+		return
+	}
+
+	response.Total = len(recipients)
+	response.Affected = affected
+	response.ParseSuccess(fmt.Sprintf("%d recipients appended", affected))
+	RespondSuccess(w, response)
+}
+
+// CampaignExportController exports campaign results
+//
+//	@Summary		Export campaign results
+//	@Description	Exports all recipients with status, message id, error and variables, as csv (default) or json.
+//	@Tags			Campaign
+//	@Produce		text/csv
+//	@Produce		json
+//	@Param			campaignid	path		string	true	"Campaign id"
+//	@Param			format		query		string	false	"csv | json"
+//	@Success		200			{string}	string	"campaign results"
+//	@Failure		400			{object}	models.QpResponse
+//	@Security		ApiKeyAuth
+//	@Router			/campaigns/{campaignid}/export [get]
+func CampaignExportController(w http.ResponseWriter, r *http.Request) {
+	response := &models.QpResponse{}
+
+	campaign, err := GetCampaign(r)
+	if err != nil {
+		response.ParseError(err)
+		RespondInterface(w, response)
+		return
+	}
+
+	recipients, err := models.CampaignManager.GetRecipients(campaign, "")
+	if err != nil {
+		response.ParseError(err)
+		RespondInterface(w, response)
+		return
+	}
+
+	filename := "campaign-" + campaign.Id
+	if strings.EqualFold(library.GetRequestParameter(r, "format"), "json") {
+		w.Header().Set("Content-Type", "application/json")
+		w.Header().Set("Content-Disposition", fmt.Sprintf("attachment; filename=\"%s.json\"", filename))
+		json.NewEncoder(w).Encode(recipients)
+		return
+	}
+
+	w.Header().Set("Content-Type", "text/csv")
+	w.Header().Set("Content-Disposition", fmt.Sprintf("attachment; filename=\"%s.csv\"", filename))
+	WriteCampaignRecipientsCSV(w, recipients)
+}
+
+//endregion
+
+//#region CAMPAIGN HELPERS
+
+// GetCampaign finds the campaign from path, owned by the server token
+func GetCampaign(r *http.Request) (*models.QpCampaign, error) {
+	server, err := GetServer(r)
+	if err != nil {
+		return nil, err
+	}
+
+	id := library.GetRequestParameter(r, "campaignid")
+	if len(id) == 0 {
+		return nil, fmt.Errorf("missing campaign id")
+	}
+
+	return models.CampaignManager.Find(server.Token, id)
+}
+
+// RespondCampaign responds the campaign with updated counters
+func RespondCampaign(w http.ResponseWriter, response *models.QpCampaignResponse, campaign *models.QpCampaign, message string) {
+	summary, err := models.NewQpCampaignSummary(campaign)
+	if err != nil {
+		response.ParseError(err)
+		RespondInterface(w, response)
+		return
+	}
+
+	response.Campaign = summary
+	response.ParseSuccess(message)
+	RespondSuccess(w, response)
+}
+
+// WriteCampaignRecipientsCSV writes recipients with one column per variable
+func WriteCampaignRecipientsCSV(w http.ResponseWriter, recipients []*models.QpCampaignRecipient) {
+	keys := map[string]bool{}
+	for _, recipient := range recipients {
+		for key := range recipient.Variables {
+			keys[key] = true
+		}
+	}
+
+	variables := make([]string, 0, len(keys))
+	for key := range keys {
+		variables = append(variables, key)
+	}
+	sort.Strings(variables)
+
+	writer := csv.NewWriter(w)
+	writer.Write(append([]string{"chatid", "status", "messageid", "error", "timestamp"}, variables...))
+
+	for _, recipient := range recipients {
+		record := []string{recipient.ChatId, recipient.Status, recipient.MessageId, recipient.Error, recipient.Timestamp.Format(time.RFC3339)}
+		for _, key := range variables {
+			record = append(record, recipient.Variables[key])
+		}
+		writer.Write(record)
+	}
+
+	writer.Flush()
+}
+
+//#endregion
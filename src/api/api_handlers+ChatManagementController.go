@@ -0,0 +1,246 @@
+package api
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"slices"
+	"time"
+
+	models "github.com/nocodeleaks/quepasa/models"
+	whatsapp "github.com/nocodeleaks/quepasa/whatsapp"
+)
+
+// ChatPinRequest defines the parameters for pinning/unpinning a chat
+type ChatPinRequest struct {
+	ChatId string `json:"chatid"` // Required: Chat to pin/unpin
+	Pin    bool   `json:"pin"`    // Required: true to pin, false to unpin
+}
+
+// ChatMuteRequest defines the parameters for muting/unmuting a chat
+type ChatMuteRequest struct {
+	ChatId   string `json:"chatid"`             // Required: Chat to mute/unmute
+	Mute     bool   `json:"mute"`               // Required: true to mute, false to unmute
+	Duration int64  `json:"duration,omitempty"` // Optional: mute duration in seconds, 0 mutes forever
+}
+
+// ChatClearRequest defines the parameters for clearing a chat
+type ChatClearRequest struct {
+	ChatId string `json:"chatid"` // Required: Chat to clear
+}
+
+// ChatDisappearingRequest defines the parameters for the disappearing messages timer
+type ChatDisappearingRequest struct {
+	ChatId string `json:"chatid"` // Required: Chat to update
+	Timer  uint32 `json:"timer"`  // Required: timer in seconds, 0 disables
+}
+
+// disappearing timers accepted by whatsapp: off, 24 hours, 7 days and 90 days
+var ChatDisappearingTimers = []uint32{0, 86400, 604800, 7776000}
+
+// decodeChatRequest decodes the json body and formats the chat id, responding on error
+func decodeChatRequest(w http.ResponseWriter, r *http.Request, response *models.QpResponse, request any, chatId *string) error {
+	err := json.NewDecoder(r.Body).Decode(request)
+	if err != nil {
+		err = fmt.Errorf("invalid request: %v", err)
+	} else if len(*chatId) == 0 {
+		err = fmt.Errorf("chatid is required")
+	} else {
+		*chatId, err = whatsapp.FormatEndpoint(*chatId)
+		if err != nil {
+			err = fmt.Errorf("invalid chatid: %v", err)
+		}
+	}
+
+	if err != nil {
+		response.ParseError(err)
+		RespondInterface(w, response)
+	}
+	return err
+}
+
+// PinChatController handles API requests for pinning or unpinning a chat
+//
+//	@Summary		Pin or unpin chat
+//	@Description	Pins or unpins a WhatsApp chat, synchronized with all linked devices
+//	@Tags			Chat
+//	@Accept			json
+//	@Produce		json
+//	@Param			request	body		ChatPinRequest	true	"Chat pin request"
+//	@Success		200		{object}	models.QpResponse
+//	@Failure		400		{object}	models.QpResponse
+//	@Failure		503		{object}	models.QpResponse
+//	@Security		ApiKeyAuth
+//	@Router			/chat/pin [post]
+func PinChatController(w http.ResponseWriter, r *http.Request) {
+	// Setting default response type as json
+	w.Header().Set("Content-Type", "application/json")
+
+	response := &models.QpResponse{}
+
+	server, err := GetReadyServer(w, r, response)
+	if err != nil {
+		return
+	}
+
+	request := &ChatPinRequest{}
+	if decodeChatRequest(w, r, response, request, &request.ChatId) != nil {
+		return
+	}
+
+	err = server.GetChatManager().PinChat(request.ChatId, request.Pin)
+	if err != nil {
+		response.ParseError(fmt.Errorf("failed to pin chat: %s", err.Error()))
+		RespondInterface(w, response)
+		return
+	}
+
+	action := "pinned"
+	if !request.Pin {
+		action = "unpinned"
+	}
+
+	response.ParseSuccess(fmt.Sprintf("chat %s %s successfully", request.ChatId, action))
+	RespondSuccess(w, response)
+}
+
+// MuteChatController handles API requests for muting or unmuting a chat
+//
+//	@Summary		Mute or unmute chat
+//	@Description	Mutes a WhatsApp chat for a duration in seconds (0 mutes forever), or unmutes it
+//	@Tags			Chat
+//	@Accept			json
+//	@Produce		json
+//	@Param			request	body		ChatMuteRequest	true	"Chat mute request"
+//	@Success		200		{object}	models.QpResponse
+//	@Failure		400		{object}	models.QpResponse
+//	@Failure		503		{object}	models.QpResponse
+//	@Security		ApiKeyAuth
+//	@Router			/chat/mute [post]
+func MuteChatController(w http.ResponseWriter, r *http.Request) {
+	// Setting default response type as json
+	w.Header().Set("Content-Type", "application/json")
+
+	response := &models.QpResponse{}
+
+	server, err := GetReadyServer(w, r, response)
+	if err != nil {
+		return
+	}
+
+	request := &ChatMuteRequest{}
+	if decodeChatRequest(w, r, response, request, &request.ChatId) != nil {
+		return
+	}
+
+	if request.Duration < 0 {
+		response.ParseError(fmt.Errorf("duration can not be negative"))
+		RespondInterface(w, response)
+		return
+	}
+
+	duration := time.Duration(request.Duration) * time.Second
+	err = server.GetChatManager().MuteChat(request.ChatId, request.Mute, duration)
+	if err != nil {
+		response.ParseError(fmt.Errorf("failed to mute chat: %s", err.Error()))
+		RespondInterface(w, response)
+		return
+	}
+
+	action := "muted"
+	if !request.Mute {
+		action = "unmuted"
+	} else if duration > 0 {
+		action += " for " + duration.String()
+	}
+
+	response.ParseSuccess(fmt.Sprintf("chat %s %s successfully", request.ChatId, action))
+	RespondSuccess(w, response)
+}
+
+// ClearChatController handles API requests for clearing all messages of a chat
+//
+//	@Summary		Clear chat
+//	@Description	Clears all messages of a WhatsApp chat for the connected account, synchronized with all linked devices
+//	@Tags			Chat
+//	@Accept			json
+//	@Produce		json
+//	@Param			request	body		ChatClearRequest	true	"Chat clear request"
+//	@Success		200		{object}	models.QpResponse
+//	@Failure		400		{object}	models.QpResponse
+//	@Failure		503		{object}	models.QpResponse
+//	@Security		ApiKeyAuth
+//	@Router			/chat/clear [post]
+func ClearChatController(w http.ResponseWriter, r *http.Request) {
+	// Setting default response type as json
+	w.Header().Set("Content-Type", "application/json")
+
+	response := &models.QpResponse{}
+
+	server, err := GetReadyServer(w, r, response)
+	if err != nil {
+		return
+	}
+
+	request := &ChatClearRequest{}
+	if decodeChatRequest(w, r, response, request, &request.ChatId) != nil {
+		return
+	}
+
+	err = server.GetChatManager().ClearChat(request.ChatId)
+	if err != nil {
+		response.ParseError(fmt.Errorf("failed to clear chat: %s", err.Error()))
+		RespondInterface(w, response)
+		return
+	}
+
+	response.ParseSuccess(fmt.Sprintf("chat %s cleared successfully", request.ChatId))
+	RespondSuccess(w, response)
+}
+
+// DisappearingChatController handles API requests for setting the disappearing messages timer
+//
+//	@Summary		Set disappearing messages timer
+//	@Description	Sets the disappearing messages timer of a WhatsApp chat, in seconds: 0 (off), 86400 (24 hours), 604800 (7 days) or 7776000 (90 days)
+//	@Tags			Chat
+//	@Accept			json
+//	@Produce		json
+//	@Param			request	body		ChatDisappearingRequest	true	"Chat disappearing request"
+//	@Success		200		{object}	models.QpResponse
+//	@Failure		400		{object}	models.QpResponse
+//	@Failure		503		{object}	models.QpResponse
+//	@Security		ApiKeyAuth
+//	@Router			/chat/disappearing [post]
+func DisappearingChatController(w http.ResponseWriter, r *http.Request) {
+	// Setting default response type as json
+	w.Header().Set("Content-Type", "application/json")
+
+	response := &models.QpResponse{}
+
+	server, err := GetReadyServer(w, r, response)
+	if err != nil {
+		return
+	}
+
+	request := &ChatDisappearingRequest{}
+	if decodeChatRequest(w, r, response, request, &request.ChatId) != nil {
+		return
+	}
+
+	if !slices.Contains(ChatDisappearingTimers, request.Timer) {
+		response.ParseError(fmt.Errorf("invalid timer, use one of: %v", ChatDisappearingTimers))
+		RespondInterface(w, response)
+		return
+	}
+
+	timer := time.Duration(request.Timer) * time.Second
+	err = server.GetChatManager().SetDisappearingTimer(request.ChatId, timer)
+	if err != nil {
+		response.ParseError(err)
+		RespondInterface(w, response)
+		return
+	}
+
+	response.ParseSuccess(fmt.Sprintf("chat %s disappearing timer set to %s", request.ChatId, timer.String()))
+	RespondSuccess(w, response)
+}
@@ -4,6 +4,7 @@ import (
 	"encoding/json"
 	"fmt"
 	"net/http"
+	"time"
 
 	models "github.com/nocodeleaks/quepasa/models"
 	whatsapp "github.com/nocodeleaks/quepasa/whatsapp"
@@ -95,6 +96,10 @@ func MarkChatAsReadController(w http.ResponseWriter, r *http.Request) {
 
 	logentry.Infof("marked chat as read: %s", request.ChatId)
 
+	if server.Handler != nil {
+		server.Handler.SetChatRead(request.ChatId, time.Now())
+	}
+
 	// Create successful response
 	response.Success = true
 	response.ParseSuccess(fmt.Sprintf("chat %s marked as read", request.ChatId))
@@ -181,6 +186,10 @@ func MarkChatAsUnreadController(w http.ResponseWriter, r *http.Request) {
 
 	logentry.Infof("marked chat as unread: %s", request.ChatId)
 
+	if server.Handler != nil {
+		server.Handler.SetChatUnread(request.ChatId)
+	}
+
 	// Create successful response
 	response.Success = true
 	response.ParseSuccess(fmt.Sprintf("chat %s marked as unread", request.ChatId))
@@ -0,0 +1,113 @@
+package api
+
+import (
+	"fmt"
+	"net/http"
+	"strconv"
+
+	library "github.com/nocodeleaks/quepasa/library"
+	models "github.com/nocodeleaks/quepasa/models"
+	whatsapp "github.com/nocodeleaks/quepasa/whatsapp"
+)
+
+//region CONTROLLER - CHATS
+
+// ChatsController lists chats with cached messages
+//
+//	@Summary		List chats
+//	@Description	Lists chats from the message cache with title, last message, unread count and archived/pinned/muted state.
+//	@Description	Ordered by last message, newer first.
+//	@Tags			Chat
+//	@Produce		json
+//	@Success		200	{object}	models.QpChatsResponse
+//	@Failure		400	{object}	models.QpResponse
+//	@Security		ApiKeyAuth
+//	@Router			/chats [get]
+func ChatsController(w http.ResponseWriter, r *http.Request) {
+
+	// setting default response type as json
+	w.Header().Set("Content-Type", "application/json")
+
+	response := &models.QpChatsResponse{}
+
+	server, err := GetServer(r)
+	if err != nil {
+		response.ParseError(err)
+		RespondInterface(w, response)
+		return
+	}
+
+	if server.Handler == nil {
+		response.ParseError(fmt.Errorf("messages handler not ready"))
+		RespondInterface(w, response)
+		return
+	}
+
+	response.Chats = server.GetChats()
+	response.Total = len(response.Chats)
+	response.ParseSuccess(fmt.Sprintf("%d chats found", response.Total))
+	RespondSuccess(w, response)
+}
+
+// ChatMessagesController pages the cached messages of a chat
+//
+//	@Summary		Chat messages
+//	@Description	Cached messages of a chat, newer first.
+//	@Description	Use "before" with the returned before cursor for older messages, or "after" for newer ones.
+//	@Tags			Chat
+//	@Produce		json
+//	@Param			chatid	path		string	true	"Chat id"
+//	@Param			before	query		string	false	"Message id cursor, returns older messages"
+//	@Param			after	query		string	false	"Message id cursor, returns newer messages"
+//	@Param			limit	query		int		false	"Page size (default 50, max 500)"
+//	@Success		200		{object}	models.QpChatMessagesResponse
+//	@Failure		400		{object}	models.QpResponse
+//	@Security		ApiKeyAuth
+//	@Router			/chats/{chatid}/messages [get]
+func ChatMessagesController(w http.ResponseWriter, r *http.Request) {
+
+	// setting default response type as json
+	w.Header().Set("Content-Type", "application/json")
+
+	response := &models.QpChatMessagesResponse{}
+
+	server, err := GetServer(r)
+	if err != nil {
+		response.ParseError(err)
+		RespondInterface(w, response)
+		return
+	}
+
+	chatid, err := whatsapp.FormatEndpoint(library.GetChatId(r))
+	if err != nil {
+		response.ParseError(err)
+		RespondInterface(w, response)
+		return
+	}
+
+	limit := 0
+	if value := library.GetRequestParameter(r, "limit"); len(value) > 0 {
+		limit, err = strconv.Atoi(value)
+		if err != nil {
+			response.ParseError(fmt.Errorf("invalid limit parameter: %s", err.Error()))
+			RespondInterface(w, response)
+			return
+		}
+	}
+
+	before := library.GetRequestParameter(r, "before")
+	after := library.GetRequestParameter(r, "after")
+
+	result, err := server.GetChatMessages(chatid, before, after, limit)
+	if err != nil {
+		response.ParseError(err)
+		RespondInterface(w, response)
+		return
+	}
+
+	response = result
+	response.ParseSuccess(fmt.Sprintf("%d messages found", len(response.Messages)))
+	RespondSuccess(w, response)
+}
+
+//endregion
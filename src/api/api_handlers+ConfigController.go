@@ -0,0 +1,58 @@
+package api
+
+import (
+	"net/http"
+
+	environment "github.com/nocodeleaks/quepasa/environment"
+	library "github.com/nocodeleaks/quepasa/library"
+	models "github.com/nocodeleaks/quepasa/models"
+)
+
+//region CONTROLLER - CONFIG
+
+// ConfigController shows the effective configuration
+//
+//	@Summary		Effective configuration
+//	@Description	Returns the settings in use, from environment and config file, requires master key.
+//	@Description	Secrets are hidden, url passwords and server tokens masked. Reloadable lists the settings applied without restart.
+//	@Tags			Application
+//	@Produce		json
+//	@Success		200	{object}	models.QpConfigResponse
+//	@Failure		400	{object}	models.QpResponse
+//	@Security		ApiKeyAuth
+//	@Router			/config [get]
+func ConfigController(w http.ResponseWriter, r *http.Request) {
+
+	// setting default response type as json
+	w.Header().Set("Content-Type", "application/json")
+
+	response := &models.QpConfigResponse{}
+
+	err := ValidateMasterKey(r)
+	if err != nil {
+		response.ParseError(err)
+		RespondInterfaceCode(w, response, http.StatusUnauthorized)
+		return
+	}
+
+	response.File = environment.Settings.Config.File
+	response.Loaded = environment.ConfigFile.GetLoaded()
+	response.Settings = environment.GetSettings().GetRedacted()
+
+	for _, setting := range environment.ConfigReloadable {
+		response.Reloadable = append(response.Reloadable, setting.Path)
+	}
+
+	servers := environment.ConfigFile.GetServers()
+	if len(servers) > 0 {
+		response.Servers = map[string]environment.ConfigServer{}
+		for token, server := range servers {
+			response.Servers[library.MaskToken(token)] = server.GetRedacted()
+		}
+	}
+
+	response.ParseSuccess("effective configuration")
+	RespondSuccess(w, response)
+}
+
+//endregion
@@ -0,0 +1,241 @@
+package api
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"strings"
+	"time"
+
+	websocket "github.com/gorilla/websocket"
+	library "github.com/nocodeleaks/quepasa/library"
+	models "github.com/nocodeleaks/quepasa/models"
+	whatsapp "github.com/nocodeleaks/quepasa/whatsapp"
+)
+
+// interval between heartbeats on idle streams
+const EventStreamHeartbeat = 25 * time.Second
+
+// time allowed to write an event to a websocket peer
+const EventStreamWriteWait = 10 * time.Second
+
+var eventStreamUpgrader = websocket.Upgrader{
+	ReadBufferSize:  1024,
+	WriteBufferSize: 1024,
+	CheckOrigin:     func(r *http.Request) bool { return true }, // authenticated by token
+}
+
+// EventsController streams server events as Server-Sent Events
+//
+//	@Summary		Stream events (SSE)
+//	@Description	Streams real time events (message, receipt, connection, call, group) as Server-Sent Events.
+//	@Description	Optional filters: "types" (comma separated) and "chatid".
+//	@Description	Resumes from the message cache using the "Last-Event-ID" header or "lasteventid" parameter.
+//	@Description	Heartbeats are sent as comments while idle.
+//	@Tags			Events
+//	@Produce		text/event-stream
+//	@Param			types		query	string	false	"Event types, comma separated (message,receipt,connection,call,group)"
+//	@Param			chatid		query	string	false	"Chat filter"
+//	@Param			lasteventid	query	string	false	"Resume after this message id"
+//	@Success		200	{string}	string	"event stream"
+//	@Failure		404	{object}	models.QpResponse
+//	@Security		ApiKeyAuth
+//	@Router			/events [get]
+func EventsController(w http.ResponseWriter, r *http.Request) {
+	server, err := GetServerRespondOnError(w, r)
+	if err != nil {
+		return
+	}
+
+	flusher, ok := w.(http.Flusher)
+	if !ok {
+		RespondServerError(server, w, fmt.Errorf("streaming not supported"))
+		return
+	}
+
+	subscriber, backlog, err := GetEventStreamSubscriber(r, server)
+	if err != nil {
+		RespondBadRequest(w, err)
+		return
+	}
+	defer models.EventStreamHub.Unsubscribe(subscriber)
+
+	// long lived, not cut by the web server write timeout
+	err = http.NewResponseController(w).SetWriteDeadline(time.Time{})
+	if err != nil {
+		RespondServerError(server, w, fmt.Errorf("streaming without write deadline not supported: %s", err.Error()))
+		return
+	}
+
+	w.Header().Set("Content-Type", "text/event-stream")
+	w.Header().Set("Cache-Control", "no-cache")
+	w.Header().Set("Connection", "keep-alive")
+	w.Header().Set("X-Accel-Buffering", "no") // disable proxy buffering (nginx)
+	w.WriteHeader(http.StatusOK)
+
+	// retry interval for browser reconnection
+	fmt.Fprintf(w, "retry: %d\n\n", 3000)
+
+	for _, event := range backlog {
+		WriteServerSentEvent(w, event)
+	}
+	flusher.Flush()
+
+	heartbeat := time.NewTicker(EventStreamHeartbeat)
+	defer heartbeat.Stop()
+
+	for {
+		select {
+		case <-r.Context().Done():
+			return
+		case event := <-subscriber.Events:
+			WriteServerSentEvent(w, event)
+			flusher.Flush()
+		case <-heartbeat.C:
+			fmt.Fprintf(w, ": heartbeat %d\n\n", time.Now().Unix())
+			flusher.Flush()
+		}
+	}
+}
+
+// WebSocketController streams server events as json over a plain websocket
+//
+//	@Summary		Stream events (WebSocket)
+//	@Description	Streams real time events as json objects {id, type, payload} over a raw WebSocket.
+//	@Description	Same filters and resume parameters of /events, heartbeats are sent as {"type":"heartbeat"} and ping frames.
+//	@Tags			Events
+//	@Param			types		query	string	false	"Event types, comma separated (message,receipt,connection,call,group)"
+//	@Param			chatid		query	string	false	"Chat filter"
+//	@Param			lasteventid	query	string	false	"Resume after this message id"
+//	@Success		101	{string}	string	"switching protocols"
+//	@Failure		404	{object}	models.QpResponse
+//	@Security		ApiKeyAuth
+//	@Router			/ws [get]
+func WebSocketController(w http.ResponseWriter, r *http.Request) {
+	server, err := GetServerRespondOnError(w, r)
+	if err != nil {
+		return
+	}
+
+	subscriber, backlog, err := GetEventStreamSubscriber(r, server)
+	if err != nil {
+		RespondBadRequest(w, err)
+		return
+	}
+	defer models.EventStreamHub.Unsubscribe(subscriber)
+
+	conn, err := eventStreamUpgrader.Upgrade(w, r, nil)
+	if err != nil {
+		logentry := server.GetLogger()
+		logentry.Errorf("(websocket): upgrade error: %s", err.Error())
+		return
+	}
+	defer conn.Close()
+
+	// reading pump, only used to detect closed connections and handle pongs
+	closed := make(chan struct{})
+	go func() {
+		defer close(closed)
+		conn.SetReadLimit(512)
+		conn.SetReadDeadline(time.Now().Add(EventStreamHeartbeat * 2))
+		conn.SetPongHandler(func(string) error {
+			conn.SetReadDeadline(time.Now().Add(EventStreamHeartbeat * 2))
+			return nil
+		})
+		for {
+			if _, _, err := conn.ReadMessage(); err != nil {
+				return
+			}
+		}
+	}()
+
+	write := func(v interface{}) error {
+		conn.SetWriteDeadline(time.Now().Add(EventStreamWriteWait))
+		return conn.WriteJSON(v)
+	}
+
+	for _, event := range backlog {
+		if err := write(event); err != nil {
+			return
+		}
+	}
+
+	heartbeat := time.NewTicker(EventStreamHeartbeat)
+	defer heartbeat.Stop()
+
+	for {
+		select {
+		case <-closed:
+			return
+		case event := <-subscriber.Events:
+			if err := write(event); err != nil {
+				return
+			}
+		case <-heartbeat.C:
+			if err := write(map[string]interface{}{"type": "heartbeat", "timestamp": time.Now().Unix()}); err != nil {
+				return
+			}
+			if err := conn.WriteControl(websocket.PingMessage, nil, time.Now().Add(EventStreamWriteWait)); err != nil {
+				return
+			}
+		}
+	}
+}
+
+//#region EVENT STREAM HELPERS
+
+// GetEventStreamSubscriber parses filters, subscribes to server events and collects cached events to resume from
+func GetEventStreamSubscriber(r *http.Request, server *models.QpWhatsappServer) (subscriber *models.QpEventStreamSubscriber, backlog []*models.QpEventStreamEvent, err error) {
+	var types []string
+	for _, item := range strings.Split(library.GetRequestParameter(r, "types"), ",") {
+		item = strings.TrimSpace(item)
+		if len(item) > 0 {
+			types = append(types, strings.ToLower(item))
+		}
+	}
+
+	chatid := library.GetChatId(r)
+	if len(chatid) > 0 {
+		chatid, err = whatsapp.FormatEndpoint(chatid)
+		if err != nil {
+			return
+		}
+	}
+
+	// subscribe before reading cache, avoiding lost events between
+	subscriber = models.EventStreamHub.Subscribe(server.Token, types, chatid)
+
+	lastEventId := r.Header.Get("Last-Event-ID")
+	if len(lastEventId) == 0 {
+		lastEventId = library.GetRequestParameter(r, "lasteventid")
+	}
+
+	if len(lastEventId) > 0 {
+		messages, cacheErr := models.GetMessagesAfter(server, lastEventId)
+		if cacheErr != nil {
+			logentry := server.GetLogger()
+			logentry.Warnf("event stream resume not available: %s", cacheErr.Error())
+		}
+
+		for _, msg := range messages {
+			event := models.NewQpEventStreamEvent(msg, "cache")
+			if subscriber.Match(event) {
+				backlog = append(backlog, event)
+			}
+		}
+	}
+
+	return
+}
+
+// WriteServerSentEvent writes a single event in text/event-stream format
+func WriteServerSentEvent(w http.ResponseWriter, event *models.QpEventStreamEvent) {
+	data, err := json.Marshal(event.Payload)
+	if err != nil {
+		return
+	}
+
+	fmt.Fprintf(w, "id: %s\nevent: %s\ndata: %s\n\n", event.Id, event.Type, data)
+}
+
+//#endregion
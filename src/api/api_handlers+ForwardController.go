@@ -0,0 +1,90 @@
+package api
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+
+	models "github.com/nocodeleaks/quepasa/models"
+)
+
+//region CONTROLLER - FORWARD
+
+// ForwardController forwards a cached message to one or more chats
+//
+//	@Summary		Forward message
+//	@Description	Forwards a cached message by id to one or more chats, marked as forwarded and reusing the original media (no download or upload).
+//	@Description	Returns one result per target chat, the request succeeds if at least one forward succeeds.
+//	@Tags			Message
+//	@Accept			json
+//	@Produce		json
+//	@Param			request	body		models.QpForwardRequest	true	"Forward request"
+//	@Success		200		{object}	models.QpForwardResponse
+//	@Failure		400		{object}	models.QpForwardResponse
+//	@Failure		503		{object}	models.QpResponse
+//	@Security		ApiKeyAuth
+//	@Router			/forward [post]
+func ForwardController(w http.ResponseWriter, r *http.Request) {
+
+	// setting default response type as json
+	w.Header().Set("Content-Type", "application/json")
+
+	response := &models.QpForwardResponse{}
+
+	server, err := GetReadyServer(w, r, &response.QpResponse)
+	if err != nil {
+		return
+	}
+
+	request := &models.QpForwardRequest{}
+	err = json.NewDecoder(r.Body).Decode(request)
+	if err != nil {
+		response.ParseError(fmt.Errorf("invalid json body: %s", err.Error()))
+		RespondInterface(w, response)
+		return
+	}
+
+	if len(request.MessageId) == 0 {
+		response.ParseError(fmt.Errorf("empty message id"))
+		RespondInterface(w, response)
+		return
+	}
+
+	chatids := request.GetChatIds()
+	if len(chatids) == 0 {
+		response.ParseError(fmt.Errorf("no target chat, use chatid or chatids"))
+		RespondInterface(w, response)
+		return
+	}
+
+	if len(chatids) > models.QpForwardMaxChats {
+		response.ParseError(fmt.Errorf("too many target chats, maximum: %d", models.QpForwardMaxChats))
+		RespondInterface(w, response)
+		return
+	}
+
+	response.Results, err = server.Forward(request.MessageId, chatids, request.TrackId)
+	if err != nil {
+		response.ParseError(err)
+		RespondInterface(w, response)
+		return
+	}
+
+	forwarded := 0
+	for _, result := range response.Results {
+		if len(result.Error) == 0 {
+			forwarded++
+		}
+	}
+
+	if forwarded == 0 {
+		response.ParseError(fmt.Errorf("forward failed for all %d chats", len(chatids)))
+		RespondInterface(w, response)
+		return
+	}
+
+	response.ParseSuccess(fmt.Sprintf("forwarded to %d of %d chats", forwarded, len(chatids)))
+	RespondSuccess(w, response)
+}
+
+//endregion
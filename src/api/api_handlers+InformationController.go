@@ -124,7 +124,7 @@ func InformationPatchRequest(w http.ResponseWriter, r *http.Request) {
 		if len(jsonUsername) > 0 {
 
 			// searching user
-			_, err := models.WhatsappService.DB.Users.Find(jsonUsername)
+			_, err := models.UserManager.Find(jsonUsername)
 			if err != nil {
 				jsonError := fmt.Errorf("user not found: %v", err.Error())
 				response.ParseError(jsonError)
@@ -177,6 +177,15 @@ func InformationPatchRequest(w http.ResponseWriter, r *http.Request) {
 		}
 	}
 
+	if request.Transcription != nil {
+		option := *request.Transcription
+
+		if server.Transcription != option {
+			server.Transcription = option
+			update += fmt.Sprintf("transcription to: {%s}; ", option)
+		}
+	}
+
 	//#endregion
 
 	if len(update) > 0 {
@@ -0,0 +1,49 @@
+package api
+
+import (
+	"fmt"
+	"net/http"
+
+	library "github.com/nocodeleaks/quepasa/library"
+	models "github.com/nocodeleaks/quepasa/models"
+	whatsapp "github.com/nocodeleaks/quepasa/whatsapp"
+)
+
+// LiveLocationController gets the latest positions of live location sharings on a chat
+//
+//	@Summary		Get live locations
+//	@Description	Latest position of each active live location sharing on a chat, one per participant.
+//	@Description	Sharings without updates for more than 8 hours are considered ended.
+//	@Tags			Location
+//	@Produce		json
+//	@Param			chatid	path		string	true	"Chat id"
+//	@Success		200		{object}	models.QpLiveLocationResponse
+//	@Failure		400		{object}	models.QpResponse
+//	@Security		ApiKeyAuth
+//	@Router			/location/live/{chatid} [get]
+func LiveLocationController(w http.ResponseWriter, r *http.Request) {
+
+	// setting default response type as json
+	w.Header().Set("Content-Type", "application/json")
+
+	response := &models.QpLiveLocationResponse{}
+
+	server, err := GetServer(r)
+	if err != nil {
+		response.ParseError(err)
+		RespondInterface(w, response)
+		return
+	}
+
+	chatid, err := whatsapp.FormatEndpoint(library.GetChatId(r))
+	if err != nil {
+		response.ParseError(err)
+		RespondInterface(w, response)
+		return
+	}
+
+	response.ChatId = chatid
+	response.Locations = server.GetLiveLocations(chatid)
+	response.ParseSuccess(fmt.Sprintf("%d live locations found", len(response.Locations)))
+	RespondSuccess(w, response)
+}
@@ -178,6 +178,124 @@ func EditMessageController(w http.ResponseWriter, r *http.Request) {
 	RespondSuccess(w, response)
 }
 
+// StarMessageRequest defines the parameters for starring/unstarring a message
+type StarMessageRequest struct {
+	MessageId string `json:"messageid"` // Required: Message to star/unstar
+	Star      bool   `json:"star"`      // Required: true to star, false to unstar
+}
+
+// StarMessageController stars or unstars a message
+//
+//	@Summary		Star or unstar message
+//	@Description	Stars or unstars a cached message by its ID, synchronized with all linked devices
+//	@Tags			Message
+//	@Accept			json
+//	@Produce		json
+//	@Param			request	body		StarMessageRequest	true	"Message star request"
+//	@Success		200		{object}	models.QpResponse
+//	@Failure		400		{object}	models.QpResponse
+//	@Security		ApiKeyAuth
+//	@Router			/message/star [post]
+func StarMessageController(w http.ResponseWriter, r *http.Request) {
+
+	// setting default response type as json
+	w.Header().Set("Content-Type", "application/json")
+
+	response := &models.QpResponse{}
+
+	request := &StarMessageRequest{}
+	err := json.NewDecoder(r.Body).Decode(request)
+	if err != nil {
+		response.ParseError(fmt.Errorf("invalid json body: %s", err.Error()))
+		RespondInterface(w, response)
+		return
+	}
+
+	if len(request.MessageId) == 0 {
+		response.ParseError(fmt.Errorf("empty message id"))
+		RespondInterface(w, response)
+		return
+	}
+
+	server, err := GetServer(r)
+	if err != nil {
+		response.ParseError(err)
+		RespondInterface(w, response)
+		return
+	}
+
+	err = server.Star(request.MessageId, request.Star)
+	if err != nil {
+		response.ParseError(err)
+		RespondInterface(w, response)
+		return
+	}
+
+	if request.Star {
+		response.ParseSuccess("message starred successfully")
+	} else {
+		response.ParseSuccess("message unstarred successfully")
+	}
+	RespondSuccess(w, response)
+}
+
+// DeleteForMeRequest defines the parameters for deleting a message only for the connected account
+type DeleteForMeRequest struct {
+	MessageId   string `json:"messageid"`             // Required: Message to delete
+	DeleteMedia bool   `json:"deletemedia,omitempty"` // Optional: also delete downloaded media on linked devices
+}
+
+// DeleteForMeController deletes a message only for the connected account
+//
+//	@Summary		Delete message for me
+//	@Description	Deletes a cached message by its ID only for the connected account, other participants still see it
+//	@Tags			Message
+//	@Accept			json
+//	@Produce		json
+//	@Param			request	body		DeleteForMeRequest	true	"Message delete for me request"
+//	@Success		200		{object}	models.QpResponse
+//	@Failure		400		{object}	models.QpResponse
+//	@Security		ApiKeyAuth
+//	@Router			/message/deleteforme [post]
+func DeleteForMeController(w http.ResponseWriter, r *http.Request) {
+
+	// setting default response type as json
+	w.Header().Set("Content-Type", "application/json")
+
+	response := &models.QpResponse{}
+
+	request := &DeleteForMeRequest{}
+	err := json.NewDecoder(r.Body).Decode(request)
+	if err != nil {
+		response.ParseError(fmt.Errorf("invalid json body: %s", err.Error()))
+		RespondInterface(w, response)
+		return
+	}
+
+	if len(request.MessageId) == 0 {
+		response.ParseError(fmt.Errorf("empty message id"))
+		RespondInterface(w, response)
+		return
+	}
+
+	server, err := GetServer(r)
+	if err != nil {
+		response.ParseError(err)
+		RespondInterface(w, response)
+		return
+	}
+
+	err = server.DeleteForMe(request.MessageId, request.DeleteMedia)
+	if err != nil {
+		response.ParseError(err)
+		RespondInterface(w, response)
+		return
+	}
+
+	response.ParseSuccess("message deleted for me successfully")
+	RespondSuccess(w, response)
+}
+
 //endregion
 
 // MarkReadController marks one or more messages as read on the WhatsApp connection
@@ -0,0 +1,158 @@
+package api
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"strconv"
+
+	library "github.com/nocodeleaks/quepasa/library"
+	models "github.com/nocodeleaks/quepasa/models"
+)
+
+//region CONTROLLER - MESSAGE SEARCH
+
+// MessageSearchController searches cached messages using the full text index
+//
+//	@Summary		Search messages
+//	@Description	Full text search over cached messages, all query terms must match.
+//	@Description	Filters can be passed as query parameters (GET) or json body (POST).
+//	@Description	Ordered by relevance when a query is set, otherwise by time (newer first). Use the returned cursor for the next page, relevance scores are kept from the first page.
+//	@Tags			Message
+//	@Accept			json
+//	@Produce		json
+//	@Param			query			query		string	false	"Text to search"
+//	@Param			chatid			query		string	false	"Chat filter"
+//	@Param			participant		query		string	false	"Group participant filter"
+//	@Param			type			query		string	false	"Message type (text, image, audio, video, document, ...)"
+//	@Param			after			query		int		false	"Unix timestamp, messages after"
+//	@Param			before			query		int		false	"Unix timestamp, messages before"
+//	@Param			fromme			query		bool	false	"Sent by this whatsapp"
+//	@Param			hasattachment	query		bool	false	"Messages with attachment"
+//	@Param			order			query		string	false	"relevance | time"
+//	@Param			limit			query		int		false	"Page size (default 50, max 500)"
+//	@Param			cursor			query		string	false	"Cursor from previous page"
+//	@Param			request			body		models.QpMessageSearchRequest	false	"Search request"
+//	@Success		200				{object}	models.QpMessageSearchResponse
+//	@Failure		400				{object}	models.QpResponse
+//	@Security		ApiKeyAuth
+//	@Router			/messages/search [get]
+//	@Router			/messages/search [post]
+func MessageSearchController(w http.ResponseWriter, r *http.Request) {
+
+	// setting default response type as json
+	w.Header().Set("Content-Type", "application/json")
+
+	response := &models.QpMessageSearchResponse{}
+
+	server, err := GetServer(r)
+	if err != nil {
+		response.ParseError(err)
+		RespondInterface(w, response)
+		return
+	}
+
+	request := &models.QpMessageSearchRequest{}
+	if r.ContentLength > 0 && r.Method == http.MethodPost {
+		err = json.NewDecoder(r.Body).Decode(request)
+		if err != nil {
+			response.ParseError(fmt.Errorf("invalid json body: %s", err.Error()))
+			RespondInterface(w, response)
+			return
+		}
+	}
+
+	err = GetMessageSearchParameters(r, request)
+	if err != nil {
+		response.ParseError(err)
+		RespondInterface(w, response)
+		return
+	}
+
+	if server.Handler == nil {
+		response.ParseError(fmt.Errorf("messages handler not ready"))
+		RespondInterface(w, response)
+		return
+	}
+
+	result, err := request.Search(&server.Handler.QpWhatsappMessages)
+	if err != nil {
+		response.ParseError(err)
+		RespondInterface(w, response)
+		return
+	}
+
+	response.Total = result.Total
+	response.Cursor = result.Cursor
+	response.Messages = result.Messages
+	response.ParseSuccess(fmt.Sprintf("%d messages found", result.Total))
+	RespondSuccess(w, response)
+}
+
+// GetMessageSearchParameters fills unset request fields from PATH => QUERY => HEADER
+func GetMessageSearchParameters(r *http.Request, request *models.QpMessageSearchRequest) (err error) {
+	setString := func(target *string, name string) {
+		if len(*target) == 0 {
+			*target = library.GetRequestParameter(r, name)
+		}
+	}
+
+	setString(&request.Query, "query")
+	setString(&request.ChatId, "chatid")
+	setString(&request.Participant, "participant")
+	setString(&request.Type, "type")
+	setString(&request.Order, "order")
+	setString(&request.Cursor, "cursor")
+
+	setInt64 := func(target *int64, name string) error {
+		value := library.GetRequestParameter(r, name)
+		if *target != 0 || len(value) == 0 {
+			return nil
+		}
+
+		parsed, err := strconv.ParseInt(value, 10, 64)
+		if err != nil {
+			return fmt.Errorf("invalid %s parameter: %s", name, err.Error())
+		}
+		*target = parsed
+		return nil
+	}
+
+	if err = setInt64(&request.After, "after"); err != nil {
+		return
+	}
+
+	if err = setInt64(&request.Before, "before"); err != nil {
+		return
+	}
+
+	var limit int64
+	if err = setInt64(&limit, "limit"); err != nil {
+		return
+	}
+	if request.Limit == 0 {
+		request.Limit = int(limit)
+	}
+
+	setBool := func(target **bool, name string) error {
+		value := library.GetRequestParameter(r, name)
+		if *target != nil || len(value) == 0 {
+			return nil
+		}
+
+		parsed, err := strconv.ParseBool(value)
+		if err != nil {
+			return fmt.Errorf("invalid %s parameter: %s", name, err.Error())
+		}
+		*target = &parsed
+		return nil
+	}
+
+	if err = setBool(&request.FromMe, "fromme"); err != nil {
+		return
+	}
+
+	return setBool(&request.HasAttachment, "hasattachment")
+}
+
+//endregion
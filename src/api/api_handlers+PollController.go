@@ -0,0 +1,53 @@
+package api
+
+import (
+	"fmt"
+	"net/http"
+
+	library "github.com/nocodeleaks/quepasa/library"
+	models "github.com/nocodeleaks/quepasa/models"
+)
+
+// PollResultsController gets aggregated results of a poll
+//
+//	@Summary		Get poll results
+//	@Description	Gets a poll sent or received with the tally of each option, counting only the latest vote of each voter
+//	@Tags			Poll
+//	@Produce		json
+//	@Param			messageid	path		string	true	"Poll message id"
+//	@Success		200			{object}	models.QpPollResultsResponse
+//	@Failure		400			{object}	models.QpResponse
+//	@Security		ApiKeyAuth
+//	@Router			/poll/{messageid}/results [get]
+func PollResultsController(w http.ResponseWriter, r *http.Request) {
+
+	// setting default response type as json
+	w.Header().Set("Content-Type", "application/json")
+
+	response := &models.QpPollResultsResponse{}
+
+	server, err := GetServer(r)
+	if err != nil {
+		response.ParseError(err)
+		RespondInterface(w, response)
+		return
+	}
+
+	id := library.GetRequestParameter(r, "messageid")
+	if len(id) == 0 {
+		response.ParseError(fmt.Errorf("missing poll message id"))
+		RespondInterface(w, response)
+		return
+	}
+
+	results, err := models.PollManager.GetResults(server.Token, id)
+	if err != nil {
+		response.ParseError(err)
+		RespondInterface(w, response)
+		return
+	}
+
+	response.Poll = results
+	response.ParseSuccess(fmt.Sprintf("poll found with %d voters", results.Voters))
+	RespondSuccess(w, response)
+}
@@ -0,0 +1,217 @@
+package api
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"strings"
+
+	models "github.com/nocodeleaks/quepasa/models"
+)
+
+//region CONTROLLER - PROFILE
+
+// ProfileController gets own profile
+//
+//	@Summary		Get own profile
+//	@Description	Gets push name, about text and profile picture info of the connected account
+//	@Tags			Profile
+//	@Produce		json
+//	@Success		200	{object}	models.QpProfileResponse
+//	@Failure		400	{object}	models.QpResponse
+//	@Failure		503	{object}	models.QpResponse
+//	@Security		ApiKeyAuth
+//	@Router			/profile [get]
+func ProfileController(w http.ResponseWriter, r *http.Request) {
+
+	// setting default response type as json
+	w.Header().Set("Content-Type", "application/json")
+
+	response := &models.QpProfileResponse{}
+
+	server, err := GetReadyServer(w, r, &response.QpResponse)
+	if err != nil {
+		return
+	}
+
+	profile, err := server.GetProfileManager().GetProfile()
+	if err != nil {
+		response.ParseError(err)
+		RespondInterface(w, response)
+		return
+	}
+
+	response.Profile = profile
+	response.ParseSuccess("profile found")
+	RespondSuccess(w, response)
+}
+
+// ProfilePatchController updates own push name and/or about text
+//
+//	@Summary		Update own profile
+//	@Description	Updates push name (display name) and/or about text of the connected account, only passed fields are updated
+//	@Tags			Profile
+//	@Accept			json
+//	@Produce		json
+//	@Param			request	body		models.QpProfilePatchRequest	true	"Profile update request"
+//	@Success		200		{object}	models.QpProfileResponse
+//	@Failure		400		{object}	models.QpResponse
+//	@Failure		503		{object}	models.QpResponse
+//	@Security		ApiKeyAuth
+//	@Router			/profile [patch]
+func ProfilePatchController(w http.ResponseWriter, r *http.Request) {
+
+	// setting default response type as json
+	w.Header().Set("Content-Type", "application/json")
+
+	response := &models.QpProfileResponse{}
+
+	server, err := GetReadyServer(w, r, &response.QpResponse)
+	if err != nil {
+		return
+	}
+
+	request := &models.QpProfilePatchRequest{}
+	err = json.NewDecoder(r.Body).Decode(request)
+	if err != nil {
+		response.ParseError(fmt.Errorf("invalid json body: %s", err.Error()))
+		RespondInterface(w, response)
+		return
+	}
+
+	if request.PushName == nil && request.About == nil {
+		response.ParseError(fmt.Errorf("nothing to update, use pushname and/or about"))
+		RespondInterface(w, response)
+		return
+	}
+
+	profileManager := server.GetProfileManager()
+	if request.PushName != nil {
+		name := strings.TrimSpace(*request.PushName)
+		if len(name) == 0 {
+			response.ParseError(fmt.Errorf("pushname can not be empty"))
+			RespondInterface(w, response)
+			return
+		}
+
+		err = profileManager.SetPushName(name)
+		if err != nil {
+			response.ParseError(err)
+			RespondInterface(w, response)
+			return
+		}
+	}
+
+	if request.About != nil {
+		err = profileManager.SetAbout(*request.About)
+		if err != nil {
+			response.ParseError(err)
+			RespondInterface(w, response)
+			return
+		}
+	}
+
+	response.Profile, err = profileManager.GetProfile()
+	if err != nil {
+		response.ParseError(err)
+		RespondInterface(w, response)
+		return
+	}
+
+	response.ParseSuccess("profile updated")
+	RespondSuccess(w, response)
+}
+
+// ProfilePictureController updates own profile picture
+//
+//	@Summary		Update own profile picture
+//	@Description	Updates the profile picture of the connected account, from json (url or base64 content) or raw image body (Content-Type: image/*).
+//	@Description	Jpeg, png and gif up to 10MB and 25 megapixels are accepted, the image is cropped to the center square and resized to 640x640.
+//	@Tags			Profile
+//	@Accept			json
+//	@Accept			image/jpeg
+//	@Accept			image/png
+//	@Produce		json
+//	@Param			request	body		models.QpProfilePictureRequest	false	"Profile picture request"
+//	@Success		200		{object}	models.QpResponse
+//	@Failure		400		{object}	models.QpResponse
+//	@Failure		503		{object}	models.QpResponse
+//	@Security		ApiKeyAuth
+//	@Router			/profile/picture [put]
+func ProfilePictureController(w http.ResponseWriter, r *http.Request) {
+
+	// setting default response type as json
+	w.Header().Set("Content-Type", "application/json")
+
+	response := &models.QpResponse{}
+
+	server, err := GetReadyServer(w, r, response)
+	if err != nil {
+		return
+	}
+
+	var content []byte
+	if strings.HasPrefix(strings.ToLower(r.Header.Get("Content-Type")), "image/") {
+		content, err = models.ReadProfilePicture(r.Body)
+	} else {
+		request := &models.QpProfilePictureRequest{}
+		err = json.NewDecoder(r.Body).Decode(request)
+		if err != nil {
+			err = fmt.Errorf("invalid json body: %s", err.Error())
+		} else {
+			content, err = request.GetContent()
+		}
+	}
+
+	if err != nil {
+		response.ParseError(err)
+		RespondInterface(w, response)
+		return
+	}
+
+	pictureId, err := server.GetProfileManager().SetProfilePicture(content)
+	if err != nil {
+		response.ParseError(err)
+		RespondInterface(w, response)
+		return
+	}
+
+	response.ParseSuccess(fmt.Sprintf("profile picture updated, id: %s", pictureId))
+	RespondSuccess(w, response)
+}
+
+// ProfilePictureDeleteController removes own profile picture
+//
+//	@Summary		Remove own profile picture
+//	@Description	Removes the profile picture of the connected account
+//	@Tags			Profile
+//	@Produce		json
+//	@Success		200	{object}	models.QpResponse
+//	@Failure		400	{object}	models.QpResponse
+//	@Failure		503	{object}	models.QpResponse
+//	@Security		ApiKeyAuth
+//	@Router			/profile/picture [delete]
+func ProfilePictureDeleteController(w http.ResponseWriter, r *http.Request) {
+
+	// setting default response type as json
+	w.Header().Set("Content-Type", "application/json")
+
+	response := &models.QpResponse{}
+
+	server, err := GetReadyServer(w, r, response)
+	if err != nil {
+		return
+	}
+
+	err = server.GetProfileManager().RemoveProfilePicture()
+	if err != nil {
+		response.ParseError(err)
+		RespondInterface(w, response)
+		return
+	}
+
+	response.ParseSuccess("profile picture removed")
+	RespondSuccess(w, response)
+}
+
+//endregion
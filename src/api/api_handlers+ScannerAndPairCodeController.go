@@ -91,6 +91,14 @@ func ValidateUsername(r *http.Request) (username string, ex ApiException) {
 		return
 	}
 
+	// viewers can not pair new devices
+	user, uerr := GetUser(r)
+	if uerr != nil || !user.CanWrite() {
+		ex = &BadRequestException{}
+		ex.Prepend(fmt.Sprintf("%s: %s", models.ErrUserForbidden.Error(), username))
+		return
+	}
+
 	return
 }
 
@@ -25,6 +25,7 @@ import (
 //	@Description	- Polls (field "poll") — send the poll JSON in the "poll" field
 //	@Description	- Location (field "location") — send location with latitude/longitude in the "location" object
 //	@Description	- Contact (field "contact") — send contact with phone/name in the "contact" object
+//	@Description	- Interactive (field "interactive") — send reply/url/call/copy buttons or a list, attachment is used as header media
 //	@Description
 //	@Description	Main fields:
 //	@Description	- chatId: chat identifier (can be WID, LID or number with suffix @s.whatsapp.net)
@@ -35,6 +36,7 @@ import (
 //	@Description	- poll: JSON object with the poll (question, options, selections)
 //	@Description	- location: JSON object with location data (latitude, longitude, name, address, url)
 //	@Description	- contact: JSON object with contact data (phone, name, vcard)
+//	@Description	- interactive: JSON object with header, subtitle, footer and buttons or list
 //	@Description
 //	@Description	Location object fields:
 //	@Description	- latitude (float64, required): Location latitude in degrees (e.g.: -23.550520)
@@ -131,31 +133,41 @@ func SendAny(w http.ResponseWriter, r *http.Request) {
 }
 
 func SendAnyWithServer(w http.ResponseWriter, r *http.Request, server *models.QpWhatsappServer) {
-	response := &models.QpSendResponse{}
+	request, err := GetSendAnyRequest(r)
+	if err != nil {
+		MessageSendErrors.Inc()
+
+		response := &models.QpSendResponse{}
+		response.ParseError(err)
+		RespondInterface(w, response)
+		return
+	}
+
+	SendAnyRequestWithServer(w, r, request, server)
+}
 
-	// Declare a new request struct.
-	request := &models.QpSendAnyRequest{}
+// GetSendAnyRequest decodes the json body (if any) and ensures a valid chatid
+func GetSendAnyRequest(r *http.Request) (request *models.QpSendAnyRequest, err error) {
+	request = &models.QpSendAnyRequest{}
 
 	if r.ContentLength > 0 && r.Method == http.MethodPost {
-		// Try to decode the request body into the struct. If there is an error,
-		// respond to the client with the error message and a 400 status code.
-		err := json.NewDecoder(r.Body).Decode(&request)
+		// Try to decode the request body into the struct
+		err = json.NewDecoder(r.Body).Decode(&request)
 		if err != nil {
-			jsonErr := fmt.Errorf("invalid json body: %s", err.Error())
-			response.ParseError(jsonErr)
-			RespondInterface(w, response)
+			err = fmt.Errorf("invalid json body: %s", err.Error())
 			return
 		}
 	}
 
 	// Getting ChatId parameter
-	err := request.EnsureValidChatId(r)
-	if err != nil {
-		MessageSendErrors.Inc()
-		response.ParseError(err)
-		RespondInterface(w, response)
-		return
-	}
+	err = request.EnsureValidChatId(r)
+	return
+}
+
+// SendAnyRequestWithServer sends an already decoded request, with valid chatid
+func SendAnyRequestWithServer(w http.ResponseWriter, r *http.Request, request *models.QpSendAnyRequest, server *models.QpWhatsappServer) {
+	response := &models.QpSendResponse{}
+	var err error
 
 	if len(request.Url) == 0 && r.URL.Query().Has("url") {
 		request.Url = r.URL.Query().Get("url")
@@ -221,7 +233,7 @@ func SendRequest(w http.ResponseWriter, r *http.Request, request *models.QpSendR
 		}
 	}
 
-	if request.Poll == nil && request.Location == nil && request.Contact == nil && att.Attach == nil && len(request.Text) == 0 {
+	if request.Poll == nil && request.Location == nil && request.Contact == nil && request.Interactive == nil && att.Attach == nil && len(request.Text) == 0 {
 		MessageSendErrors.Inc()
 		err = fmt.Errorf("text not found, do not send empty messages")
 		response.ParseError(err)
@@ -234,6 +246,7 @@ func SendRequest(w http.ResponseWriter, r *http.Request, request *models.QpSendR
 		request.TrackId = GetTrackId(r)
 	}
 
+	request.TraceContext = r.Context()
 	response.Debug = append(response.Debug, att.Debug...)
 	Send(server, response, request, w, att.Attach)
 }
@@ -328,6 +341,7 @@ func SendWithMessageType(server *models.QpWhatsappServer, response *models.QpSen
 			}
 
 			// Try to send directly to LID first
+			IdempotencySending(request.TraceContext)
 			sendResponse, err := server.SendMessage(waMsg)
 			if err == nil {
 				// Success sending to LID directly
@@ -356,6 +370,7 @@ func SendWithMessageType(server *models.QpWhatsappServer, response *models.QpSen
 		logentry.Debugf("converted LID %s to phone-based chat ID: %s (from phone %s)", originalChatId, waMsg.Chat.Id, phone)
 	}
 
+	IdempotencySending(request.TraceContext)
 	sendResponse, err := server.SendMessage(waMsg)
 	if err != nil {
 		MessageSendErrors.Inc()
@@ -161,6 +161,7 @@ func SendDocumentRequest(w http.ResponseWriter, r *http.Request, request *models
 		request.TrackId = GetTrackId(r)
 	}
 
+	request.TraceContext = r.Context()
 	response.Debug = append(response.Debug, att.Debug...)
 	SendDocumentToServer(server, response, request, w, att.Attach)
 }
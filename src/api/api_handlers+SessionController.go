@@ -0,0 +1,135 @@
+package api
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"strconv"
+
+	library "github.com/nocodeleaks/quepasa/library"
+	models "github.com/nocodeleaks/quepasa/models"
+)
+
+// maximum session archive size accepted on import
+const SessionArchiveMaxSize = 64 << 20
+
+// header carrying the archive passphrase
+const SessionPassphraseHeader = "X-QUEPASA-PASSPHRASE"
+
+// GetSessionPassphrase reads the passphrase from header or json body, never from query, as urls are logged
+func GetSessionPassphrase(r *http.Request) string {
+	if passphrase := r.Header.Get(SessionPassphraseHeader); len(passphrase) > 0 {
+		return passphrase
+	}
+
+	passphrase, _ := PeekJSONBody(r, AuditBodyLimit)["passphrase"].(string)
+	return passphrase
+}
+
+// SessionExportController exports the session as an encrypted archive
+//
+//	@Summary		Export session
+//	@Description	Exports device keys, server record and dispatching entries as an archive encrypted with the passphrase, requires master key.
+//	@Description	By default it is a handoff: the session is stopped, its device removed and marked unverified here, so it never runs on two instances at once.
+//	@Description	With backup=true the session keeps running here, and the import requires force.
+//	@Tags			Session
+//	@Produce		application/octet-stream
+//	@Param			X-QUEPASA-PASSPHRASE	header		string	false	"Archive passphrase, at least 8 characters, or passphrase on json body"
+//	@Param			backup					query		bool	false	"Keep the session running here"
+//	@Success		200						{file}		file	"session archive"
+//	@Failure		400						{object}	models.QpResponse
+//	@Security		ApiKeyAuth
+//	@Router			/session/export [post]
+func SessionExportController(w http.ResponseWriter, r *http.Request) {
+	response := &models.QpResponse{}
+
+	err := ValidateMasterKey(r)
+	if err != nil {
+		response.ParseError(err)
+		RespondInterfaceCode(w, response, http.StatusUnauthorized)
+		return
+	}
+
+	server, err := GetServer(r)
+	if err != nil {
+		response.ParseError(err)
+		RespondInterfaceCode(w, response, http.StatusNotFound)
+		return
+	}
+
+	backup, _ := strconv.ParseBool(library.GetRequestParameter(r, "backup"))
+	passphrase := GetSessionPassphrase(r)
+
+	data, err := models.ExportSession(r.Context(), server, passphrase, backup)
+	if err != nil {
+		if len(data) == 0 {
+			response.ParseError(err)
+			RespondInterface(w, response)
+			return
+		}
+
+		// device already removed, the archive is the only copy
+		server.GetLogger().Warn(err.Error())
+	}
+
+	w.Header().Set("Content-Type", "application/octet-stream")
+	w.Header().Set("Content-Disposition", fmt.Sprintf("attachment; filename=\"%s.qpsession\"", library.GetPhoneByWId(server.Wid)))
+	w.WriteHeader(http.StatusOK)
+	w.Write(data)
+}
+
+// SessionImportController imports a session archive and starts it
+//
+//	@Summary		Import session
+//	@Description	Imports an archive generated by session export, on body, and starts the session, requires master key.
+//	@Description	Backup archives, handoff archives already imported, or sessions already active here, are refused with 409 unless force=true.
+//	@Tags			Session
+//	@Accept			application/octet-stream
+//	@Produce		json
+//	@Param			X-QUEPASA-PASSPHRASE	header		string	true	"Archive passphrase"
+//	@Param			force					query		bool	false	"Import even if the session may be running elsewhere"
+//	@Param			user					query		string	false	"Owner of the imported session, defaults to the archive one if it exists here"
+//	@Success		200						{object}	models.QpSessionImportResponse
+//	@Failure		400						{object}	models.QpResponse
+//	@Failure		409						{object}	models.QpResponse
+//	@Security		ApiKeyAuth
+//	@Router			/session/import [post]
+func SessionImportController(w http.ResponseWriter, r *http.Request) {
+	response := &models.QpSessionImportResponse{}
+
+	err := ValidateMasterKey(r)
+	if err != nil {
+		response.ParseError(err)
+		RespondInterfaceCode(w, response, http.StatusUnauthorized)
+		return
+	}
+
+	data, err := io.ReadAll(http.MaxBytesReader(w, r.Body, SessionArchiveMaxSize))
+	if err != nil {
+		response.ParseError(err)
+		RespondInterface(w, response)
+		return
+	}
+
+	// body holds the archive, so header only
+	force, _ := strconv.ParseBool(library.GetRequestParameter(r, "force"))
+	passphrase := r.Header.Get(SessionPassphraseHeader)
+	user := library.GetRequestParameter(r, "user")
+
+	server, err := models.ImportSession(r.Context(), data, passphrase, user, force)
+	if err != nil {
+		response.ParseError(err)
+		if errors.Is(err, models.ErrSessionConflict) {
+			RespondInterfaceCode(w, response, http.StatusConflict)
+		} else {
+			RespondInterface(w, response)
+		}
+		return
+	}
+
+	response.Token = server.Token
+	response.Wid = server.Wid
+	response.ParseSuccess("session imported")
+	RespondSuccess(w, response)
+}
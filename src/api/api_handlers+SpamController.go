@@ -3,6 +3,7 @@ package api
 import (
 	"net/http"
 
+	"github.com/go-chi/chi/v5/middleware"
 	models "github.com/nocodeleaks/quepasa/models"
 )
 
@@ -13,7 +14,10 @@ import (
 // Returns 423 STATUS if no server available
 //
 //	@Summary		Send spam messages
-//	@Description	Send messages using any available server (spam/broadcast functionality)
+//	@Description	Send messages using the pool of ready and verified servers (spam/broadcast functionality).
+//	@Description	Sender is chosen by SENDERPOOL_STRATEGY (roundrobin | lru), respecting SENDERPOOL_DAILY_QUOTA.
+//	@Description	With SENDERPOOL_STICKY the same recipient always gets the same sender, while it is available.
+//	@Description	The chosen sender is returned on message "wid".
 //	@Tags			Application
 //	@Accept			json
 //	@Produce		json
@@ -23,15 +27,40 @@ import (
 //	@Security		ApiKeyAuth
 //	@Router			/spam [post]
 func Spam(w http.ResponseWriter, r *http.Request) {
-	server, err := GetServerFromMaster(r)
+	response := &models.QpSendResponse{}
+
+	err := ValidateMasterKey(r)
+	if err != nil {
+		MessageSendErrors.Inc()
+		response.ParseError(err)
+		RespondInterfaceCode(w, response, http.StatusLocked)
+		return
+	}
+
+	// recipient is required before choosing the sender
+	request, err := GetSendAnyRequest(r)
 	if err != nil {
 		MessageSendErrors.Inc()
+		response.ParseError(err)
+		RespondInterface(w, response)
+		return
+	}
 
-		response := &models.QpSendResponse{}
+	server, err := models.SenderPool.Select(request.ChatId)
+	if err != nil {
+		MessageSendErrors.Inc()
 		response.ParseError(err)
 		RespondInterfaceCode(w, response, http.StatusLocked)
 		return
 	}
 
-	SendAnyWithServer(w, r, server)
+	// only successful sends count on daily quota
+	sent := false
+	defer func() {
+		models.SenderPool.Release(server, sent)
+	}()
+
+	ww := middleware.NewWrapResponseWriter(w, r.ProtoMajor)
+	SendAnyRequestWithServer(ww, r, request, server)
+	sent = ww.Status() >= http.StatusOK && ww.Status() < http.StatusMultipleChoices
 }
@@ -50,7 +50,7 @@ func UserController(w http.ResponseWriter, r *http.Request) {
 	}
 
 	// searching user
-	request, err = models.WhatsappService.DB.Users.Find(request.Username)
+	request, err = models.UserManager.Find(request.Username)
 	if err != nil {
 		jsonError := fmt.Errorf("user not found: %v", err.Error())
 		response.ParseError(jsonError)
@@ -0,0 +1,195 @@
+package api
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+
+	"github.com/nbutton23/zxcvbn-go"
+	environment "github.com/nocodeleaks/quepasa/environment"
+	library "github.com/nocodeleaks/quepasa/library"
+	models "github.com/nocodeleaks/quepasa/models"
+)
+
+//region CONTROLLER - USERS
+
+// UsersController manages user accounts, roles and status
+//
+//	@Summary		Manage users
+//	@Description	List, create, update or delete users, requires master key.
+//	@Description	Roles are admin, operator and viewer. PATCH changes only the informed fields, unlock clears failed logins.
+//	@Description	The last enabled admin can not be demoted, disabled or deleted, users owning servers can not be deleted.
+//	@Description	External users are created for single sign-on, without password, and linked on their first login.
+//	@Tags			Application
+//	@Accept			json
+//	@Produce		json
+//	@Param			request	body		models.QpUserRequest	false	"User request (for POST/PATCH/DELETE)"
+//	@Success		200		{object}	models.QpUsersResponse
+//	@Failure		400		{object}	models.QpResponse
+//	@Security		ApiKeyAuth
+//	@Router			/users [get]
+//	@Router			/users [post]
+//	@Router			/users [patch]
+//	@Router			/users [delete]
+func UsersController(w http.ResponseWriter, r *http.Request) {
+
+	// setting default response type as json
+	w.Header().Set("Content-Type", "application/json")
+
+	response := &models.QpUsersResponse{}
+
+	err := ValidateMasterKey(r)
+	if err != nil {
+		response.ParseError(err)
+		RespondInterfaceCode(w, response, http.StatusUnauthorized)
+		return
+	}
+
+	if r.Method == http.MethodGet {
+		users, err := models.UserManager.List()
+		if err != nil {
+			response.ParseError(err)
+			RespondInterface(w, response)
+			return
+		}
+
+		response.Users = users
+		response.ParseSuccess(fmt.Sprintf("%d user(s)", len(users)))
+		RespondSuccess(w, response)
+		return
+	}
+
+	// reading body to avoid converting to json if empty
+	body, err := io.ReadAll(r.Body)
+	if err != nil {
+		response.ParseError(err)
+		RespondInterface(w, response)
+		return
+	}
+
+	request := &models.QpUserRequest{}
+	if len(body) > 0 {
+		err = json.Unmarshal(body, request)
+		if err != nil {
+			jsonError := fmt.Errorf("error converting body to json: %v", err.Error())
+			response.ParseError(jsonError)
+			RespondInterface(w, response)
+			return
+		}
+	}
+
+	// username also accepted from query or header, useful for delete
+	if len(request.Username) == 0 {
+		request.Username = library.GetRequestParameter(r, "username")
+	}
+
+	if len(request.Username) == 0 {
+		response.ParseError(errors.New("missing username"))
+		RespondInterface(w, response)
+		return
+	}
+
+	switch r.Method {
+	case http.MethodPost:
+		if !library.IsValidEMail(request.Username) {
+			response.ParseError(fmt.Errorf("email is invalid: %s", request.Username))
+			RespondInterface(w, response)
+			return
+		}
+
+		if request.External {
+			response.User, err = models.UserManager.CreateExternal(request.Username, request.Role, environment.Settings.OIDC.Issuer, "")
+		} else {
+			err = ValidatePasswordStrength(request.Password)
+			if err == nil {
+				response.User, err = models.UserManager.Create(request.Username, request.Password, request.Role)
+			}
+		}
+		if err != nil {
+			response.ParseError(err)
+			RespondInterface(w, response)
+			return
+		}
+
+		response.ParseSuccess("user created")
+	case http.MethodPatch:
+		response.User, err = UsersUpdate(request)
+		if err != nil {
+			response.ParseError(err)
+			RespondInterface(w, response)
+			return
+		}
+
+		response.ParseSuccess("user updated")
+	case http.MethodDelete:
+		err = models.UserManager.Delete(request.Username)
+		if err != nil {
+			response.ParseError(err)
+			RespondInterface(w, response)
+			return
+		}
+
+		response.ParseSuccess("user deleted")
+	}
+
+	RespondSuccess(w, response)
+}
+
+//endregion
+
+// UsersUpdate applies the informed fields of the request, in order: password, role, status, unlock
+func UsersUpdate(request *models.QpUserRequest) (user *models.QpUser, err error) {
+	if len(request.Password) > 0 {
+		err = ValidatePasswordStrength(request.Password)
+		if err != nil {
+			return
+		}
+
+		err = models.UserManager.ResetPassword(request.Username, request.Password)
+		if err != nil {
+			return
+		}
+	}
+
+	if len(request.Role) > 0 {
+		user, err = models.UserManager.SetRole(request.Username, request.Role)
+		if err != nil {
+			return
+		}
+	}
+
+	if request.Disabled != nil {
+		user, err = models.UserManager.SetDisabled(request.Username, *request.Disabled)
+		if err != nil {
+			return
+		}
+	}
+
+	if request.Unlock {
+		user, err = models.UserManager.Unlock(request.Username)
+		if err != nil {
+			return
+		}
+	}
+
+	if user == nil {
+		user, err = models.WhatsappService.DB.Users.Find(request.Username)
+	}
+
+	return
+}
+
+func ValidatePasswordStrength(password string) error {
+	if len(password) == 0 {
+		return errors.New("missing password")
+	}
+
+	res := zxcvbn.PasswordStrength(password, nil)
+	if res.Score < 1 {
+		return errors.New("password is too weak")
+	}
+
+	return nil
+}
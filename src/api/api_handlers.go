@@ -12,6 +12,22 @@ import (
 
 const CurrentAPIVersion string = "v4"
 
+// Streaming routes, registered outside of api timeout middleware
+func RegisterAPIStreamControllers(r chi.Router) {
+	aliases := []string{"/current", "", "/" + CurrentAPIVersion}
+	for _, endpoint := range aliases {
+
+		// EVENTS STREAMING ***********************
+		// ----------------------------------------
+
+		r.Get(endpoint+"/events", EventsController)
+		r.Get(endpoint+"/ws", WebSocketController)
+
+		// ----------------------------------------
+		// EVENTS STREAMING ***********************
+	}
+}
+
 func RegisterAPIControllers(r chi.Router) {
 
 	// Basic health check route without authentication
@@ -25,6 +41,15 @@ func RegisterAPIControllers(r chi.Router) {
 
 		r.Post(endpoint+"/account", AccountController)
 
+		r.Get(endpoint+"/users", UsersController)
+		r.Post(endpoint+"/users", UsersController)
+		r.Patch(endpoint+"/users", UsersController)
+		r.Delete(endpoint+"/users", UsersController)
+
+		r.Get(endpoint+"/audit", AuditController)
+
+		r.Get(endpoint+"/config", ConfigController)
+
 		// CONTROL METHODS ************************
 		// ----------------------------------------
 		r.Get(endpoint+"/info", GetInformationController)
@@ -51,34 +76,49 @@ func RegisterAPIControllers(r chi.Router) {
 		// Mark message as read
 		r.Post(endpoint+"/read", MarkReadController)
 
+		// Star or delete message only for me
+		r.Post(endpoint+"/message/star", StarMessageController)
+		r.Post(endpoint+"/message/deleteforme", DeleteForMeController)
+
+		// Forward cached message to other chats
+		r.Post(endpoint+"/forward", ForwardController)
+
 		// used to send alert msgs via url, triggers on monitor systems like zabbix
 		r.Get(endpoint+"/send", SendAny)
 
-		r.Post(endpoint+"/send", SendAny)
-		r.Post(endpoint+"/send/{chatid}", SendAny)
-		/*r.Post(endpoint+"/sendlinkpreview", SendWithLinkPreviewHandler)*/
+		// retried sends with the same idempotency key get the original response
+		r.Group(func(r chi.Router) {
+			r.Use(IdempotencyMiddleware)
 
-		// obsolete, marked for remove (2024/10/22)
-		r.Post(endpoint+"/sendtext", SendAny)
-		r.Post(endpoint+"/sendtext/{chatid}", SendAny)
+			r.Post(endpoint+"/send", SendAny)
+			r.Post(endpoint+"/send/{chatid}", SendAny)
+			/*r.Post(endpoint+"/sendlinkpreview", SendWithLinkPreviewHandler)*/
 
-		// SENDING MSG ATTACH ---------------------
+			// obsolete, marked for remove (2024/10/22)
+			r.Post(endpoint+"/sendtext", SendAny)
+			r.Post(endpoint+"/sendtext/{chatid}", SendAny)
 
-		r.Post(endpoint+"/senddocument", SendDocument)
-		r.Post(endpoint+"/senddocument/{chatid}", SendDocument)
+			// SENDING MSG ATTACH ---------------------
 
-		r.Post(endpoint+"/sendurl", SendAny)
-		r.Post(endpoint+"/sendbinary/{chatid}/{filename}/{text}", SendDocumentFromBinary)
-		r.Post(endpoint+"/sendbinary/{chatid}/{filename}", SendDocumentFromBinary)
-		r.Post(endpoint+"/sendbinary/{chatid}", SendDocumentFromBinary)
-		r.Post(endpoint+"/sendbinary", SendDocumentFromBinary)
-		r.Post(endpoint+"/sendencoded", SendAny)
+			r.Post(endpoint+"/senddocument", SendDocument)
+			r.Post(endpoint+"/senddocument/{chatid}", SendDocument)
+
+			r.Post(endpoint+"/sendurl", SendAny)
+			r.Post(endpoint+"/sendbinary/{chatid}/{filename}/{text}", SendDocumentFromBinary)
+			r.Post(endpoint+"/sendbinary/{chatid}/{filename}", SendDocumentFromBinary)
+			r.Post(endpoint+"/sendbinary/{chatid}", SendDocumentFromBinary)
+			r.Post(endpoint+"/sendbinary", SendDocumentFromBinary)
+			r.Post(endpoint+"/sendencoded", SendAny)
+		})
 
 		// ----------------------------------------
 		// SENDING MSG ----------------------------
 
 		r.Get(endpoint+"/receive", ReceiveAPIHandler)
 
+		r.Get(endpoint+"/messages/search", MessageSearchController)
+		r.Post(endpoint+"/messages/search", MessageSearchController)
+
 		r.Get(endpoint+"/download/{messageid}", DownloadController)
 		r.Get(endpoint+"/download", DownloadController)
 
@@ -205,6 +245,16 @@ func RegisterAPIControllers(r chi.Router) {
 		// ----------------------------------------
 		// CHAT ARCHIVE CONTROLLER **************
 
+		// CHAT MANAGEMENT CONTROLLER ***********
+		// ----------------------------------------
+		r.Post(endpoint+"/chat/pin", PinChatController)
+		r.Post(endpoint+"/chat/mute", MuteChatController)
+		r.Post(endpoint+"/chat/clear", ClearChatController)
+		r.Post(endpoint+"/chat/disappearing", DisappearingChatController)
+
+		// ----------------------------------------
+		// CHAT MANAGEMENT CONTROLLER ***********
+
 		// MESSAGE EDITING CONTROLLER ***********
 		// ----------------------------------------
 		r.Put(endpoint+"/edit", EditMessageController)
@@ -212,6 +262,68 @@ func RegisterAPIControllers(r chi.Router) {
 		// ----------------------------------------
 		// MESSAGE EDITING CONTROLLER ***********
 
+		// CHATS CONTROLLER ***********************
+		// ----------------------------------------
+		r.Get(endpoint+"/chats", ChatsController)
+		r.Get(endpoint+"/chats/{chatid}/messages", ChatMessagesController)
+
+		// ----------------------------------------
+		// CHATS CONTROLLER ***********************
+
+		// CAMPAIGNS CONTROLLER *******************
+		// ----------------------------------------
+		r.Get(endpoint+"/campaigns", CampaignsController)
+		r.Post(endpoint+"/campaigns", CampaignCreateController)
+		r.Get(endpoint+"/campaigns/{campaignid}", CampaignController)
+		r.Delete(endpoint+"/campaigns/{campaignid}", CampaignDeleteController)
+		r.Get(endpoint+"/campaigns/{campaignid}/recipients", CampaignRecipientsController)
+		r.Post(endpoint+"/campaigns/{campaignid}/recipients", CampaignRecipientsUploadController)
+		r.Get(endpoint+"/campaigns/{campaignid}/export", CampaignExportController)
+		r.Post(endpoint+"/campaigns/{campaignid}/{action}", CampaignActionController)
+
+		// ----------------------------------------
+		// CAMPAIGNS CONTROLLER *******************
+
+		// POLL CONTROLLER ************************
+		// ----------------------------------------
+		r.Get(endpoint+"/poll/{messageid}/results", PollResultsController)
+
+		// ----------------------------------------
+		// POLL CONTROLLER ************************
+
+		// SESSION CONTROLLER *********************
+		// ----------------------------------------
+		r.Post(endpoint+"/session/export", SessionExportController)
+		r.Post(endpoint+"/session/import", SessionImportController)
+
+		// ----------------------------------------
+		// SESSION CONTROLLER *********************
+
+		// LOCATION CONTROLLER ********************
+		// ----------------------------------------
+		r.Get(endpoint+"/location/live/{chatid}", LiveLocationController)
+
+		// ----------------------------------------
+		// LOCATION CONTROLLER ********************
+
+		// PROFILE CONTROLLER *********************
+		// ----------------------------------------
+		r.Get(endpoint+"/profile", ProfileController)
+		r.Patch(endpoint+"/profile", ProfilePatchController)
+		r.Put(endpoint+"/profile/picture", ProfilePictureController)
+		r.Delete(endpoint+"/profile/picture", ProfilePictureDeleteController)
+
+		// ----------------------------------------
+		// PROFILE CONTROLLER *********************
+
+		// BUSINESS CONTROLLER ********************
+		// ----------------------------------------
+		r.Get(endpoint+"/business/profile", BusinessProfileController)
+		r.Get(endpoint+"/business/catalog", BusinessCatalogController)
+
+		// ----------------------------------------
+		// BUSINESS CONTROLLER ********************
+
 	}
 }
 
@@ -284,6 +396,12 @@ func CommandController(w http.ResponseWriter, r *http.Request) {
 			message := "calls toggled: " + server.Calls.String()
 			response.ParseSuccess(message)
 		}
+	case "transcription":
+		err := models.ToggleTranscription(server)
+		if err == nil {
+			message := "transcription toggled: " + server.Transcription.String()
+			response.ParseSuccess(message)
+		}
 	default:
 		err = fmt.Errorf("invalid action: {%s}, try {start,stop,restart,status,groups}", action)
 	}
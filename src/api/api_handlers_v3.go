@@ -23,24 +23,29 @@ func RegisterAPIV3Controllers(r chi.Router) {
 	// used to send alert msgs via url, triggers on monitor systems like zabbix
 	r.Get(ControllerPrefixV3+"/send", SendAny)
 
-	r.Post(ControllerPrefixV3+"/send", SendAny)
-	r.Post(ControllerPrefixV3+"/send/{chatid}", SendAny)
+	// retried sends with the same idempotency key get the original response
+	r.Group(func(r chi.Router) {
+		r.Use(IdempotencyMiddleware)
 
-	// obsolete, marked for remove (2024/10/22)
-	r.Post(ControllerPrefixV3+"/sendtext", SendAny)
-	r.Post(ControllerPrefixV3+"/sendtext/{chatid}", SendAny)
+		r.Post(ControllerPrefixV3+"/send", SendAny)
+		r.Post(ControllerPrefixV3+"/send/{chatid}", SendAny)
 
-	// SENDING MSG ATTACH ---------------------
+		// obsolete, marked for remove (2024/10/22)
+		r.Post(ControllerPrefixV3+"/sendtext", SendAny)
+		r.Post(ControllerPrefixV3+"/sendtext/{chatid}", SendAny)
 
-	// deprecated, discard/remove on next version
-	r.Post(ControllerPrefixV3+"/senddocument", SendDocument)
+		// SENDING MSG ATTACH ---------------------
 
-	r.Post(ControllerPrefixV3+"/sendurl", SendAny)
-	r.Post(ControllerPrefixV3+"/sendbinary/{chatid}/{filename}/{text}", SendDocumentFromBinary)
-	r.Post(ControllerPrefixV3+"/sendbinary/{chatid}/{filename}", SendDocumentFromBinary)
-	r.Post(ControllerPrefixV3+"/sendbinary/{chatid}", SendDocumentFromBinary)
-	r.Post(ControllerPrefixV3+"/sendbinary", SendDocumentFromBinary)
-	r.Post(ControllerPrefixV3+"/sendencoded", SendAny)
+		// deprecated, discard/remove on next version
+		r.Post(ControllerPrefixV3+"/senddocument", SendDocument)
+
+		r.Post(ControllerPrefixV3+"/sendurl", SendAny)
+		r.Post(ControllerPrefixV3+"/sendbinary/{chatid}/{filename}/{text}", SendDocumentFromBinary)
+		r.Post(ControllerPrefixV3+"/sendbinary/{chatid}/{filename}", SendDocumentFromBinary)
+		r.Post(ControllerPrefixV3+"/sendbinary/{chatid}", SendDocumentFromBinary)
+		r.Post(ControllerPrefixV3+"/sendbinary", SendDocumentFromBinary)
+		r.Post(ControllerPrefixV3+"/sendencoded", SendAny)
+	})
 
 	// ----------------------------------------
 	// SENDING MSG ----------------------------
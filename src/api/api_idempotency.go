@@ -0,0 +1,245 @@
+package api
+
+import (
+	"bytes"
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"io"
+	"net/http"
+	"sync"
+	"time"
+
+	"github.com/go-chi/chi/v5/middleware"
+	environment "github.com/nocodeleaks/quepasa/environment"
+	library "github.com/nocodeleaks/quepasa/library"
+)
+
+// header carrying client keys, "idempotencykey" on parameters or json body also works
+const IdempotencyHeader = "Idempotency-Key"
+
+// header set on responses replayed from a previous attempt
+const IdempotencyReplayedHeader = "Idempotent-Replayed"
+
+// maximum key length
+const IdempotencyKeyLimit = 255
+
+// request bytes read looking for the key on json bodies, use the header for larger ones
+const IdempotencyBodyLimit = 64 << 10
+
+// response bytes kept, larger responses are kept as unknown
+const IdempotencyResponseLimit = 64 << 10
+
+// interval between purges of expired responses
+const IdempotencyPurgeInterval = time.Minute
+
+type idempotencyContextKey struct{}
+
+// idempotencyEntry is an attempt in progress or its stored result
+type idempotencyEntry struct {
+	done        chan struct{}
+	fingerprint string // hash of method, path and body of the first attempt
+	sending     bool   // reached whatsapp, written only by the request of the attempt
+	stored      bool
+	unknown     bool // outcome unknown, panics or responses too large to keep
+	status      int
+	header      http.Header
+	body        []byte
+	expires     time.Time
+}
+
+// IdempotencyStore keeps send results by server token and key, in memory, thread safe
+type IdempotencyStore struct {
+	mutex   sync.Mutex
+	entries map[string]*idempotencyEntry
+	purged  time.Time
+}
+
+var Idempotency = &IdempotencyStore{entries: map[string]*idempotencyEntry{}}
+
+// Begin returns the entry for the key and true if the caller owns the attempt,
+// otherwise the entry of the attempt in progress, or already stored
+func (source *IdempotencyStore) Begin(key string, fingerprint string) (*idempotencyEntry, bool) {
+	source.mutex.Lock()
+	defer source.mutex.Unlock()
+
+	now := time.Now()
+	if now.Sub(source.purged) > IdempotencyPurgeInterval {
+		for item, entry := range source.entries {
+			if entry.stored && now.After(entry.expires) {
+				delete(source.entries, item)
+			}
+		}
+		source.purged = now
+	}
+
+	if entry, ok := source.entries[key]; ok {
+		if !entry.stored || now.Before(entry.expires) {
+			return entry, false
+		}
+	}
+
+	entry := &idempotencyEntry{done: make(chan struct{}), fingerprint: fingerprint}
+	source.entries[key] = entry
+	return entry, true
+}
+
+// Finish keeps the result for the window, status 0 means the attempt did not complete.
+// The key is released only for failures before sending (validation, server not ready), so a retry sends again.
+func (source *IdempotencyStore) Finish(key string, entry *idempotencyEntry, status int, header http.Header, body []byte, window time.Duration) {
+	source.mutex.Lock()
+	defer source.mutex.Unlock()
+
+	success := status >= http.StatusOK && status < http.StatusMultipleChoices
+	if !success && !entry.sending {
+		delete(source.entries, key)
+	} else {
+		entry.stored = true
+		entry.expires = time.Now().Add(window)
+		if status == 0 || len(body) > IdempotencyResponseLimit {
+			entry.unknown = true
+		} else {
+			entry.status = status
+			entry.header = header
+			entry.body = body
+		}
+	}
+
+	close(entry.done)
+}
+
+// IdempotencySending marks the attempt of the request as reaching whatsapp, its result is kept even on errors
+func IdempotencySending(ctx context.Context) {
+	if ctx == nil {
+		return
+	}
+
+	if entry, ok := ctx.Value(idempotencyContextKey{}).(*idempotencyEntry); ok {
+		entry.sending = true
+	}
+}
+
+// GetIdempotencyFingerprint hashes method, path and body, restoring the body for the handlers
+func GetIdempotencyFingerprint(r *http.Request) (string, error) {
+	hash := sha256.New()
+	io.WriteString(hash, r.Method+" "+r.URL.RequestURI()+"\n")
+
+	if r.Body != nil {
+		body, err := io.ReadAll(r.Body)
+		r.Body.Close()
+		if err != nil {
+			return "", err
+		}
+
+		r.Body = io.NopCloser(bytes.NewReader(body))
+		hash.Write(body)
+	}
+
+	return hex.EncodeToString(hash.Sum(nil)), nil
+}
+
+// GetIdempotencyKey reads the key from header, parameters or json body
+func GetIdempotencyKey(r *http.Request) string {
+	if key := r.Header.Get(IdempotencyHeader); len(key) > 0 {
+		return key
+	}
+
+	if key := library.GetRequestParameter(r, "idempotencykey"); len(key) > 0 {
+		return key
+	}
+
+	if key, ok := PeekJSONBody(r, IdempotencyBodyLimit)["idempotencykey"].(string); ok {
+		return key
+	}
+
+	return ""
+}
+
+// IdempotencyMiddleware answers retried sends with the original response, instead of a second send.
+// Concurrent duplicates wait on the first attempt, attempts failed before sending release the key.
+func IdempotencyMiddleware(next http.Handler) http.Handler {
+	fn := func(w http.ResponseWriter, r *http.Request) {
+		window := environment.Settings.API.GetIdempotencyWindow()
+		key := GetIdempotencyKey(r)
+		if window == 0 || len(key) == 0 {
+			next.ServeHTTP(w, r)
+			return
+		}
+
+		if len(key) > IdempotencyKeyLimit {
+			RespondErrorCode(w, fmt.Errorf("idempotency key too long, maximum: %d", IdempotencyKeyLimit), http.StatusBadRequest)
+			return
+		}
+
+		fingerprint, err := GetIdempotencyFingerprint(r)
+		if err != nil {
+			RespondErrorCode(w, fmt.Errorf("error on reading request body: %s", err.Error()), http.StatusBadRequest)
+			return
+		}
+
+		// keys of different servers never collide
+		scope := GetToken(r) + ":" + key
+
+		var entry *idempotencyEntry
+		for {
+			var owner bool
+			entry, owner = Idempotency.Begin(scope, fingerprint)
+			if owner {
+				break
+			}
+
+			if entry.fingerprint != fingerprint {
+				RespondErrorCode(w, fmt.Errorf("idempotency key already used for a different request"), http.StatusUnprocessableEntity)
+				return
+			}
+
+			select {
+			case <-entry.done:
+				if entry.stored {
+					MessageSendReplays.Inc()
+					RespondIdempotent(w, entry)
+					return
+				}
+				// first attempt failed before sending, trying again
+			case <-r.Context().Done():
+				RespondErrorCode(w, fmt.Errorf("idempotency key in use by another request, retry later"), http.StatusConflict)
+				return
+			}
+		}
+
+		response := &bytes.Buffer{}
+		ww := middleware.NewWrapResponseWriter(w, r.ProtoMajor)
+		ww.Tee(response)
+
+		status := 0
+		defer func() {
+			// on panics too, so waiting duplicates are released
+			Idempotency.Finish(scope, entry, status, ww.Header().Clone(), response.Bytes(), window)
+		}()
+
+		next.ServeHTTP(ww, r.WithContext(context.WithValue(r.Context(), idempotencyContextKey{}, entry)))
+
+		status = ww.Status()
+		if status == 0 {
+			status = http.StatusOK
+		}
+	}
+	return http.HandlerFunc(fn)
+}
+
+// RespondIdempotent writes a stored response again, or a conflict if the outcome is unknown
+func RespondIdempotent(w http.ResponseWriter, entry *idempotencyEntry) {
+	w.Header().Set(IdempotencyReplayedHeader, "true")
+	if entry.unknown {
+		RespondErrorCode(w, fmt.Errorf("previous attempt with this idempotency key may have been sent, outcome unknown"), http.StatusConflict)
+		return
+	}
+
+	for name, values := range entry.header {
+		w.Header()[name] = values
+	}
+
+	w.WriteHeader(entry.status)
+	w.Write(entry.body)
+}
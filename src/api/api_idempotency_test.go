@@ -0,0 +1,143 @@
+package api
+
+import (
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	environment "github.com/nocodeleaks/quepasa/environment"
+)
+
+func newIdempotentRequest(key string, body string) *http.Request {
+	r := httptest.NewRequest(http.MethodPost, "/send", strings.NewReader(body))
+	r.Header.Set("Content-Type", "application/json")
+	r.Header.Set("X-QUEPASA-TOKEN", "test-token")
+	r.Header.Set(IdempotencyHeader, key)
+	return r
+}
+
+func TestIdempotencyConcurrentDuplicatesWait(t *testing.T) {
+	environment.Settings.API.IdempotencyWindow = 60
+
+	var calls int32
+	entered := make(chan struct{})
+	release := make(chan struct{})
+	handler := IdempotencyMiddleware(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if atomic.AddInt32(&calls, 1) == 1 {
+			close(entered)
+		}
+		<-release
+
+		IdempotencySending(r.Context())
+		w.WriteHeader(http.StatusOK)
+		io.WriteString(w, `{"success":true}`)
+	}))
+
+	body := `{"chatid":"5511999999999@s.whatsapp.net","text":"hello"}`
+	first := httptest.NewRecorder()
+	go func() {
+		handler.ServeHTTP(first, newIdempotentRequest("concurrent", body))
+	}()
+	<-entered
+
+	duplicates := make([]*httptest.ResponseRecorder, 5)
+	wg := sync.WaitGroup{}
+	for index := range duplicates {
+		duplicates[index] = httptest.NewRecorder()
+		wg.Add(1)
+		go func(w *httptest.ResponseRecorder) {
+			defer wg.Done()
+			handler.ServeHTTP(w, newIdempotentRequest("concurrent", body))
+		}(duplicates[index])
+	}
+
+	// duplicates must be waiting on the first attempt
+	time.Sleep(50 * time.Millisecond)
+	close(release)
+	wg.Wait()
+
+	if calls != 1 {
+		t.Fatalf("expected a single send, got: %d", calls)
+	}
+
+	for _, w := range duplicates {
+		if w.Code != http.StatusOK || w.Body.String() != `{"success":true}` {
+			t.Errorf("unexpected replay: %d, %s", w.Code, w.Body.String())
+		}
+		if w.Header().Get(IdempotencyReplayedHeader) != "true" {
+			t.Errorf("replay header not set")
+		}
+	}
+
+	different := httptest.NewRecorder()
+	handler.ServeHTTP(different, newIdempotentRequest("concurrent", `{"chatid":"5511999999999@s.whatsapp.net","text":"bye"}`))
+	if different.Code != http.StatusUnprocessableEntity {
+		t.Errorf("expected 422 for a different body, got: %d", different.Code)
+	}
+}
+
+func TestIdempotencyFailures(t *testing.T) {
+	environment.Settings.API.IdempotencyWindow = 60
+
+	var calls int32
+	handler := IdempotencyMiddleware(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		atomic.AddInt32(&calls, 1)
+		if r.URL.Query().Get("send") == "true" {
+			IdempotencySending(r.Context())
+			w.WriteHeader(http.StatusInternalServerError)
+			return
+		}
+		w.WriteHeader(http.StatusBadRequest)
+	}))
+
+	// validation failures release the key
+	for range 2 {
+		handler.ServeHTTP(httptest.NewRecorder(), newIdempotentRequest("invalid", "{}"))
+	}
+	if calls != 2 {
+		t.Fatalf("expected validation failures to be retried, got: %d calls", calls)
+	}
+
+	// failures after sending are kept
+	calls = 0
+	for range 2 {
+		r := httptest.NewRequest(http.MethodPost, "/send?send=true", strings.NewReader("{}"))
+		r.Header.Set("X-QUEPASA-TOKEN", "test-token")
+		r.Header.Set(IdempotencyHeader, "failed")
+
+		w := httptest.NewRecorder()
+		handler.ServeHTTP(w, r)
+		if w.Code != http.StatusInternalServerError {
+			t.Errorf("expected the stored status, got: %d", w.Code)
+		}
+	}
+	if calls != 1 {
+		t.Fatalf("expected a failure after sending to be kept, got: %d calls", calls)
+	}
+}
+
+func TestIdempotencyExpiry(t *testing.T) {
+	store := &IdempotencyStore{entries: map[string]*idempotencyEntry{}}
+
+	entry, owner := store.Begin("token:expiry", "fingerprint")
+	if !owner {
+		t.Fatal("expected the first attempt to own the key")
+	}
+
+	entry.sending = true
+	store.Finish("token:expiry", entry, http.StatusOK, http.Header{}, []byte("{}"), 20*time.Millisecond)
+
+	if _, owner = store.Begin("token:expiry", "fingerprint"); owner {
+		t.Fatal("expected the stored response within the window")
+	}
+
+	time.Sleep(30 * time.Millisecond)
+	if _, owner = store.Begin("token:expiry", "fingerprint"); !owner {
+		t.Fatal("expected the key to be free after the window")
+	}
+}
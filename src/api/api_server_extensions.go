@@ -8,6 +8,7 @@ import (
 
 	environment "github.com/nocodeleaks/quepasa/environment"
 	models "github.com/nocodeleaks/quepasa/models"
+	whatsapp "github.com/nocodeleaks/quepasa/whatsapp"
 )
 
 /*
@@ -22,6 +23,26 @@ func GetServer(r *http.Request) (server *models.QpWhatsappServer, err error) {
 	return models.GetServerFromToken(token)
 }
 
+// <summary>Find a whatsapp server by token and checks for ready state, responding on error</summary>
+func GetReadyServer(w http.ResponseWriter, r *http.Request, response *models.QpResponse) (server *models.QpWhatsappServer, err error) {
+	server, err = GetServer(r)
+	if err != nil {
+		response.ParseError(err)
+		RespondInterface(w, response)
+		return
+	}
+
+	status := server.GetStatus()
+	if status != whatsapp.Ready {
+		err = &ApiServerNotReadyException{Wid: server.GetWId(), Status: status}
+		response.ParseError(err)
+		RespondInterfaceCode(w, response, http.StatusServiceUnavailable)
+		return
+	}
+
+	return
+}
+
 // <summary>Find a whatsapp server by token passed on Url Path parameters</summary>
 func GetServerRespondOnError(w http.ResponseWriter, r *http.Request) (server *models.QpWhatsappServer, err error) {
 	token := GetToken(r)
@@ -33,17 +54,27 @@ func GetServerRespondOnError(w http.ResponseWriter, r *http.Request) (server *mo
 }
 
 func GetServerFromMaster(r *http.Request) (server *models.QpWhatsappServer, err error) {
+	err = ValidateMasterKey(r)
+	if err != nil {
+		return
+	}
+
+	return models.GetServerFirstAvailable()
+}
+
+// <summary>Returns an error if master key is not configured or not matched</summary>
+func ValidateMasterKey(r *http.Request) error {
 	system := environment.Settings.API.MasterKey
 	if len(system) == 0 {
-		return nil, errors.New("server is not allowed to use this method")
+		return errors.New("server is not allowed to use this method")
 	}
 
 	request := GetMasterKey(r)
 	if !strings.EqualFold(system, request) {
-		return nil, errors.New("dont even try to trick me, first strike")
+		return errors.New("dont even try to trick me, first strike")
 	}
 
-	return models.GetServerFirstAvailable()
+	return nil
 }
 
 // <summary>Checks if was passed a valid master key</summary>
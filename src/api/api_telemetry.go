@@ -0,0 +1,47 @@
+package api
+
+import (
+	"net/http"
+
+	"github.com/go-chi/chi/v5"
+	"github.com/go-chi/chi/v5/middleware"
+	telemetry "github.com/nocodeleaks/quepasa/telemetry"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/codes"
+	"go.opentelemetry.io/otel/trace"
+)
+
+// TelemetryMiddleware picks up trace context (traceparent) of incoming requests and starts a server span,
+// named by route pattern after routing, so tokens on url paths are not recorded
+func TelemetryMiddleware(next http.Handler) http.Handler {
+	fn := func(w http.ResponseWriter, r *http.Request) {
+		ctx := telemetry.ExtractHeaders(r.Context(), r.Header)
+		ctx, span := telemetry.Tracer().Start(ctx, "HTTP "+r.Method,
+			trace.WithSpanKind(trace.SpanKindServer),
+			trace.WithAttributes(attribute.String("http.request.method", r.Method)),
+		)
+		defer span.End()
+
+		// keeps flusher and hijacker, used by event streams and websockets
+		ww := middleware.NewWrapResponseWriter(w, r.ProtoMajor)
+		next.ServeHTTP(ww, r.WithContext(ctx))
+
+		if routeContext := chi.RouteContext(r.Context()); routeContext != nil {
+			if pattern := routeContext.RoutePattern(); len(pattern) > 0 {
+				span.SetName(r.Method + " " + pattern)
+				span.SetAttributes(attribute.String("http.route", pattern))
+			}
+		}
+
+		status := ww.Status()
+		if status == 0 {
+			status = http.StatusOK
+		}
+
+		span.SetAttributes(attribute.Int("http.response.status_code", status))
+		if status >= http.StatusInternalServerError {
+			span.SetStatus(codes.Error, http.StatusText(status))
+		}
+	}
+	return http.HandlerFunc(fn)
+}
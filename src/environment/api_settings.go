@@ -10,6 +10,8 @@ const (
 	ENV_WEBHOOK_TIMEOUT = "WEBHOOK_TIMEOUT" // timeout in milliseconds for webhook requests
 	ENV_API_PREFIX      = "API_PREFIX"      // API routes prefix
 	ENV_API_TIMEOUT     = "API_TIMEOUT"     // API request timeout in milliseconds
+
+	ENV_API_IDEMPOTENCY_WINDOW = "API_IDEMPOTENCY_WINDOW" // seconds to keep send responses by idempotency key, 0 disables (default: 3600)
 )
 
 // APISettings holds all API configuration loaded from environment
@@ -20,6 +22,8 @@ type APISettings struct {
 	WebhookTimeout  uint32 `json:"webhook_timeout"` // webhook timeout in milliseconds
 	Prefix          string `json:"prefix"`
 	Timeout         uint32 `json:"timeout"` // API request timeout in milliseconds
+
+	IdempotencyWindow uint32 `json:"idempotency_window"` // seconds to keep send responses by idempotency key
 }
 
 // NewAPISettings creates a new API settings by loading all values from environment
@@ -31,10 +35,17 @@ func NewAPISettings() APISettings {
 		WebhookTimeout:  getEnvOrDefaultUint32(ENV_WEBHOOK_TIMEOUT, 10000),
 		Prefix:          getEnvOrDefaultString(ENV_API_PREFIX, ""),
 		Timeout:         getEnvOrDefaultUint32(ENV_API_TIMEOUT, 30000),
+
+		IdempotencyWindow: getEnvOrDefaultUint32(ENV_API_IDEMPOTENCY_WINDOW, 3600),
 	}
 }
 
 // GetAPITimeout returns the API timeout as time.Duration
-func (settings APISettings) GetAPITimeout() time.Duration {
+func (settings *APISettings) GetAPITimeout() time.Duration {
 	return time.Duration(settings.Timeout) * time.Millisecond
 }
+
+// GetIdempotencyWindow returns how long send responses are kept by idempotency key as time.Duration, 0 disables
+func (settings *APISettings) GetIdempotencyWindow() time.Duration {
+	return time.Duration(settings.IdempotencyWindow) * time.Second
+}
@@ -0,0 +1,86 @@
+package environment
+
+import (
+	"fmt"
+	"net"
+	"strings"
+	"time"
+)
+
+// Audit environment variable names
+const (
+	ENV_AUDIT                 = "AUDIT"                 // records administrative and sending actions on the audit table (default: true)
+	ENV_AUDIT_RETENTION       = "AUDIT_RETENTION"       // days to keep audit entries, 0 keeps forever (default: 90)
+	ENV_AUDIT_DISPATCH        = "AUDIT_DISPATCH"        // also streams entries of a server to its webhooks and rabbitmq, as system messages (default: false)
+	ENV_AUDIT_TRUSTED_PROXIES = "AUDIT_TRUSTED_PROXIES" // comma separated addresses or cidrs of reverse proxies, X-Forwarded-For is only honoured from them (default: empty)
+)
+
+// AuditSettings holds the audit log configuration loaded from environment
+type AuditSettings struct {
+	Enabled        bool   `json:"enabled"`
+	Retention      uint32 `json:"retention"`
+	Dispatch       bool   `json:"dispatch"`
+	TrustedProxies string `json:"trusted_proxies"`
+}
+
+// NewAuditSettings creates a new audit settings by loading all values from environment
+func NewAuditSettings() AuditSettings {
+	return AuditSettings{
+		Enabled:        getEnvOrDefaultBool(ENV_AUDIT, true),
+		Retention:      getEnvOrDefaultUint32(ENV_AUDIT_RETENTION, 90),
+		Dispatch:       getEnvOrDefaultBool(ENV_AUDIT_DISPATCH, false),
+		TrustedProxies: getEnvOrDefaultString(ENV_AUDIT_TRUSTED_PROXIES, ""),
+	}
+}
+
+// GetRetention returns how long entries are kept as time.Duration, 0 keeps forever
+func (config AuditSettings) GetRetention() time.Duration {
+	return time.Duration(config.Retention) * 24 * time.Hour
+}
+
+// GetTrustedProxies parses the trusted proxies, single addresses as networks of one host
+func (config AuditSettings) GetTrustedProxies() (networks []*net.IPNet, err error) {
+	for _, item := range strings.Split(config.TrustedProxies, ",") {
+		item = strings.TrimSpace(item)
+		if len(item) == 0 {
+			continue
+		}
+
+		if !strings.Contains(item, "/") {
+			ip := net.ParseIP(item)
+			if ip == nil {
+				return nil, fmt.Errorf("invalid address: %s", item)
+			}
+
+			networks = append(networks, &net.IPNet{IP: ip, Mask: net.CIDRMask(len(ip)*8, len(ip)*8)})
+			continue
+		}
+
+		_, network, err := net.ParseCIDR(item)
+		if err != nil {
+			return nil, err
+		}
+		networks = append(networks, network)
+	}
+	return
+}
+
+// IsTrustedProxy returns true if the address belongs to a trusted proxy, invalid settings trust none
+func (config AuditSettings) IsTrustedProxy(address string) bool {
+	ip := net.ParseIP(address)
+	if ip == nil {
+		return false
+	}
+
+	networks, err := config.GetTrustedProxies()
+	if err != nil {
+		return false
+	}
+
+	for _, network := range networks {
+		if network.Contains(ip) {
+			return true
+		}
+	}
+	return false
+}
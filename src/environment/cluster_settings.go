@@ -0,0 +1,50 @@
+package environment
+
+import (
+	"os"
+	"time"
+)
+
+// Cluster environment variable names
+const (
+	ENV_CLUSTER          = "CLUSTER"          // enables cluster mode, sessions are distributed between nodes by leases
+	ENV_CLUSTER_NODE_ID  = "CLUSTER_NODE_ID"  // unique node id (default: hostname)
+	ENV_CLUSTER_NODE_URL = "CLUSTER_NODE_URL" // base url used by other nodes to reach this node (ex: http://10.0.0.1:31000)
+	ENV_CLUSTER_LEASE    = "CLUSTER_LEASE"    // lease duration in seconds, renewed at a third of it (default: 30)
+	ENV_CLUSTER_REDIRECT = "CLUSTER_REDIRECT" // redirect requests to the owner node instead of forwarding (default: false)
+)
+
+// ClusterSettings holds the cluster mode configuration loaded from environment
+type ClusterSettings struct {
+	Enabled  bool   `json:"enabled"`
+	NodeId   string `json:"node_id"`
+	NodeUrl  string `json:"node_url"`
+	Lease    uint32 `json:"lease"`
+	Redirect bool   `json:"redirect"`
+}
+
+// NewClusterSettings creates a new cluster settings by loading all values from environment
+func NewClusterSettings() ClusterSettings {
+	hostname, _ := os.Hostname()
+	if len(hostname) == 0 {
+		hostname = "quepasa"
+	}
+
+	lease := getEnvOrDefaultUint32(ENV_CLUSTER_LEASE, 30)
+	if lease < 3 {
+		lease = 3
+	}
+
+	return ClusterSettings{
+		Enabled:  getEnvOrDefaultBool(ENV_CLUSTER, false),
+		NodeId:   getEnvOrDefaultString(ENV_CLUSTER_NODE_ID, hostname),
+		NodeUrl:  getEnvOrDefaultString(ENV_CLUSTER_NODE_URL, ""),
+		Lease:    lease,
+		Redirect: getEnvOrDefaultBool(ENV_CLUSTER_REDIRECT, false),
+	}
+}
+
+// GetLeaseDuration returns the lease duration as time.Duration
+func (config ClusterSettings) GetLeaseDuration() time.Duration {
+	return time.Duration(config.Lease) * time.Second
+}
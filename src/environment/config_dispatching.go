@@ -0,0 +1,60 @@
+package environment
+
+import (
+	"fmt"
+	"net/url"
+
+	whatsapp "github.com/nocodeleaks/quepasa/whatsapp"
+)
+
+// dispatching types accepted on the configuration file, webhook is the default
+const (
+	ConfigDispatchingWebhook  = "webhook"
+	ConfigDispatchingRabbitMQ = "rabbitmq"
+)
+
+// ConfigDispatching holds a webhook or rabbitmq declared for a server, added or updated on load
+type ConfigDispatching struct {
+	whatsapp.WhatsappOptions
+
+	ConnectionString string      `json:"connection_string"`
+	Type             string      `json:"type,omitempty"`
+	ForwardInternal  bool        `json:"forwardinternal,omitempty"`
+	TrackId          string      `json:"trackid,omitempty"`
+	Extra            interface{} `json:"extra,omitempty"`
+}
+
+// GetType returns the dispatching type, webhook if not set
+func (source ConfigDispatching) GetType() string {
+	if len(source.Type) == 0 {
+		return ConfigDispatchingWebhook
+	}
+	return source.Type
+}
+
+// Validate checks type and destination
+func (source ConfigDispatching) Validate() error {
+	if len(source.ConnectionString) == 0 {
+		return fmt.Errorf("missing connection_string")
+	}
+
+	parsed, err := url.Parse(source.ConnectionString)
+	if err != nil {
+		return fmt.Errorf("invalid connection_string: %w", err)
+	}
+
+	switch source.GetType() {
+	case ConfigDispatchingWebhook:
+		if parsed.Scheme != "http" && parsed.Scheme != "https" {
+			return fmt.Errorf("webhook connection_string must be an http or https url")
+		}
+	case ConfigDispatchingRabbitMQ:
+		if parsed.Scheme != "amqp" && parsed.Scheme != "amqps" {
+			return fmt.Errorf("rabbitmq connection_string must be an amqp or amqps url")
+		}
+	default:
+		return fmt.Errorf("invalid type: %s", source.Type)
+	}
+
+	return nil
+}
@@ -0,0 +1,252 @@
+package environment
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"net/url"
+	"os"
+	"reflect"
+	"strings"
+
+	library "github.com/nocodeleaks/quepasa/library"
+	logrus "github.com/sirupsen/logrus"
+	yaml "gopkg.in/yaml.v3"
+)
+
+// configuration file section holding the per server overrides, keyed by token
+const ConfigServersSection = "servers"
+
+// configuration file section that only comes from environment
+const ConfigSection = "config"
+
+// secret settings, never shown
+var ConfigSecrets = []string{"api.signing_secret", "api.master_key", "database.password", "oidc.client_secret", "transcription.token"}
+
+// secrets never marshalled (json "-"), read from the file by their own setters
+var ConfigFileSecrets = map[string]func(settings *EnvironmentSettings, value string){
+	"oidc.client_secret":  func(settings *EnvironmentSettings, value string) { settings.OIDC.ClientSecret = value },
+	"transcription.token": func(settings *EnvironmentSettings, value string) { settings.Transcription.Token = value },
+}
+
+// settings holding urls, only their passwords are hidden
+var ConfigURLs = []string{"rabbitmq.connection_string", "telemetry.endpoint", "transcription.url", "watchdog.webhook"}
+
+// LoadConfigFile reads the yaml file over settings loaded from environment and validates the result.
+// Sections follow the json names of each category, ex: "general: {log_level: debug}".
+func LoadConfigFile(path string, settings *EnvironmentSettings) (servers map[string]ConfigServer, err error) {
+	content, err := os.ReadFile(path)
+	if err != nil {
+		return
+	}
+
+	document := map[string]interface{}{}
+	err = yaml.Unmarshal(content, &document)
+	if err != nil {
+		return nil, fmt.Errorf("invalid yaml: %w", err)
+	}
+
+	err = settings.loadConfigSecrets(document)
+	if err != nil {
+		return nil, err
+	}
+
+	servers = map[string]ConfigServer{}
+	for section, value := range document {
+		if section == ConfigServersSection {
+			err = decodeConfigSection(value, &servers)
+		} else if target := settings.getSection(section); target != nil && section != ConfigSection {
+			err = decodeConfigSection(value, target)
+		} else {
+			err = fmt.Errorf("unknown section")
+		}
+
+		if err != nil {
+			return nil, fmt.Errorf("section %s: %w", section, err)
+		}
+	}
+
+	for token, server := range servers {
+		if len(token) == 0 {
+			return nil, fmt.Errorf("section %s: empty token", ConfigServersSection)
+		}
+
+		if err = server.Validate(); err != nil {
+			return nil, fmt.Errorf("section %s, server %s: %w", ConfigServersSection, token, err)
+		}
+	}
+
+	err = settings.Validate()
+	return
+}
+
+// loadConfigSecrets applies and removes the secrets from the document, before decoding sections
+func (source *EnvironmentSettings) loadConfigSecrets(document map[string]interface{}) error {
+	for path, setter := range ConfigFileSecrets {
+		section, field := splitConfigPath(path)
+		values, ok := document[section].(map[string]interface{})
+		if !ok {
+			continue
+		}
+
+		value, ok := values[field]
+		if !ok {
+			continue
+		}
+
+		text, ok := value.(string)
+		if !ok {
+			return fmt.Errorf("section %s: %s must be a string", section, field)
+		}
+
+		setter(source, text)
+		delete(values, field)
+	}
+	return nil
+}
+
+// decodeConfigSection overlays a yaml section on target, rejecting unknown fields and wrong types
+func decodeConfigSection(value interface{}, target interface{}) error {
+	content, err := json.Marshal(value)
+	if err != nil {
+		return err
+	}
+
+	decoder := json.NewDecoder(bytes.NewReader(content))
+	decoder.DisallowUnknownFields()
+	return decoder.Decode(target)
+}
+
+// getSection returns a pointer to the category with the given json name, nil if not found
+func (source *EnvironmentSettings) getSection(name string) interface{} {
+	value := reflect.ValueOf(source).Elem()
+	for index := 0; index < value.NumField(); index++ {
+		tag := strings.Split(value.Type().Field(index).Tag.Get("json"), ",")[0]
+		if tag == name {
+			return value.Field(index).Addr().Interface()
+		}
+	}
+	return nil
+}
+
+// Validate checks values that would otherwise fail later, at use
+func (source EnvironmentSettings) Validate() error {
+	if level := source.General.LogLevel; len(level) > 0 {
+		if _, err := logrus.ParseLevel(level); err != nil {
+			return fmt.Errorf("general.log_level: %w", err)
+		}
+	}
+
+	switch source.WhatsApp.Presence {
+	case "", "available", "unavailable":
+	default:
+		return fmt.Errorf("whatsapp.presence: must be available or unavailable")
+	}
+
+	if source.API.Timeout == 0 {
+		return fmt.Errorf("api.timeout: must be greater than 0")
+	}
+
+	if source.API.WebhookTimeout == 0 {
+		return fmt.Errorf("api.webhook_timeout: must be greater than 0")
+	}
+
+	if source.Cluster.Lease < 3 {
+		return fmt.Errorf("cluster.lease: must be at least 3 seconds")
+	}
+
+	if _, err := source.Audit.GetTrustedProxies(); err != nil {
+		return fmt.Errorf("audit.trusted_proxies: %w", err)
+	}
+
+	urls := map[string]string{
+		"cluster.node_url":  source.Cluster.NodeUrl,
+		"oidc.issuer":       source.OIDC.Issuer,
+		"oidc.redirect_url": source.OIDC.RedirectURL,
+	}
+
+	for path, value := range urls {
+		if err := validateConfigURL(value); err != nil {
+			return fmt.Errorf("%s: %w", path, err)
+		}
+	}
+
+	return nil
+}
+
+// validateConfigURL accepts empty or absolute http(s) urls
+func validateConfigURL(value string) error {
+	if len(value) == 0 {
+		return nil
+	}
+
+	parsed, err := url.Parse(value)
+	if err != nil {
+		return err
+	}
+
+	if (parsed.Scheme != "http" && parsed.Scheme != "https") || len(parsed.Host) == 0 {
+		return fmt.Errorf("must be an absolute http or https url")
+	}
+	return nil
+}
+
+// GetFlatten returns every setting as text, keyed by "section.field"
+func (source EnvironmentSettings) GetFlatten() map[string]string {
+	flatten := map[string]string{}
+	for section, value := range source.toMap() {
+		flattenConfig(section, value, flatten)
+	}
+	return flatten
+}
+
+func flattenConfig(path string, value interface{}, flatten map[string]string) {
+	if children, ok := value.(map[string]interface{}); ok {
+		for key, child := range children {
+			flattenConfig(path+"."+key, child, flatten)
+		}
+		return
+	}
+
+	content, _ := json.Marshal(value)
+	flatten[path] = string(content)
+}
+
+// GetRedacted returns the settings by section, secrets hidden and url passwords masked
+func (source EnvironmentSettings) GetRedacted() map[string]interface{} {
+	document := source.toMap()
+	for _, path := range ConfigSecrets {
+		section, field := splitConfigPath(path)
+		if values, ok := document[section].(map[string]interface{}); ok {
+			if text, ok := values[field].(string); ok && len(text) > 0 {
+				values[field] = "***"
+			}
+		}
+	}
+
+	for _, path := range ConfigURLs {
+		section, field := splitConfigPath(path)
+		if values, ok := document[section].(map[string]interface{}); ok {
+			if text, ok := values[field].(string); ok {
+				values[field] = library.RedactURL(text)
+			}
+		}
+	}
+
+	return document
+}
+
+// toMap converts settings to generic maps, keyed by json names
+func (source EnvironmentSettings) toMap() map[string]interface{} {
+	document := map[string]interface{}{}
+	content, err := json.Marshal(source)
+	if err == nil {
+		json.Unmarshal(content, &document)
+	}
+	return document
+}
+
+func splitConfigPath(path string) (section string, field string) {
+	section, field, _ = strings.Cut(path, ".")
+	return
+}
@@ -0,0 +1,270 @@
+package environment
+
+import (
+	"fmt"
+	"os"
+	"os/signal"
+	"sort"
+	"strings"
+	"sync"
+	"syscall"
+	"time"
+
+	logrus "github.com/sirupsen/logrus"
+)
+
+// ConfigReloadableSetting is a setting applied without restart when the configuration file changes
+type ConfigReloadableSetting struct {
+	Path  string
+	Apply func(current *EnvironmentSettings, next *EnvironmentSettings)
+}
+
+// safe settings, read on each use, other changes on the file only warn for a restart
+var ConfigReloadable = []ConfigReloadableSetting{
+	{"general.log_level", func(current, next *EnvironmentSettings) { current.General.LogLevel = next.General.LogLevel }},
+	{"general.cache_length", func(current, next *EnvironmentSettings) { current.General.CacheLength = next.General.CacheLength }},
+	{"general.cache_days", func(current, next *EnvironmentSettings) { current.General.CacheDays = next.General.CacheDays }},
+	{"api.timeout", func(current, next *EnvironmentSettings) { current.API.Timeout = next.API.Timeout }},
+	{"api.webhook_timeout", func(current, next *EnvironmentSettings) { current.API.WebhookTimeout = next.API.WebhookTimeout }},
+	{"whatsapp.presence", func(current, next *EnvironmentSettings) { current.WhatsApp.Presence = next.WhatsApp.Presence }},
+}
+
+// GetLogLevel returns the reloadable general.log_level, default if empty or invalid
+func (source *EnvironmentSettings) GetLogLevel(defaultLevel logrus.Level) logrus.Level {
+	settingsMutex.RLock()
+	defer settingsMutex.RUnlock()
+
+	level, err := logrus.ParseLevel(source.General.LogLevel)
+	if err != nil {
+		return defaultLevel
+	}
+	return level
+}
+
+// GetCacheLength returns the reloadable general.cache_length, 0 means no limit
+func (source *EnvironmentSettings) GetCacheLength() uint64 {
+	settingsMutex.RLock()
+	defer settingsMutex.RUnlock()
+	return source.General.CacheLength
+}
+
+// GetCacheDays returns the reloadable general.cache_days, 0 means no limit
+func (source *EnvironmentSettings) GetCacheDays() uint64 {
+	settingsMutex.RLock()
+	defer settingsMutex.RUnlock()
+	return uint64(source.General.CacheDays)
+}
+
+// GetAPITimeout returns the reloadable api.timeout as time.Duration
+func (source *EnvironmentSettings) GetAPITimeout() time.Duration {
+	settingsMutex.RLock()
+	defer settingsMutex.RUnlock()
+	return source.API.GetAPITimeout()
+}
+
+// GetWebhookTimeout returns the reloadable api.webhook_timeout as time.Duration
+func (source *EnvironmentSettings) GetWebhookTimeout() time.Duration {
+	settingsMutex.RLock()
+	defer settingsMutex.RUnlock()
+	return time.Duration(source.API.WebhookTimeout) * time.Millisecond
+}
+
+// GetPresence returns the reloadable whatsapp.presence, lowercase
+func (source *EnvironmentSettings) GetPresence() string {
+	settingsMutex.RLock()
+	defer settingsMutex.RUnlock()
+	return strings.ToLower(source.WhatsApp.Presence)
+}
+
+// ConfigFileManager loads the configuration file and hot reloads its safe settings, on SIGHUP or file change
+type ConfigFileManager struct {
+	mutex     sync.Mutex
+	servers   map[string]ConfigServer
+	modified  time.Time
+	loaded    *time.Time
+	listeners []func(changed []string)
+	stop      chan struct{}
+}
+
+var ConfigFile = &ConfigFileManager{}
+
+// Load reads the configuration file over settings, used on start
+func (source *ConfigFileManager) Load(settings *EnvironmentSettings) error {
+	path := settings.Config.File
+	servers, err := LoadConfigFile(path, settings)
+	if err != nil {
+		return fmt.Errorf("config file (%s): %w", path, err)
+	}
+
+	source.mutex.Lock()
+	defer source.mutex.Unlock()
+
+	source.update(path, servers)
+	return nil
+}
+
+func (source *ConfigFileManager) update(path string, servers map[string]ConfigServer) {
+	if info, err := os.Stat(path); err == nil {
+		source.modified = info.ModTime()
+	}
+
+	now := time.Now().UTC()
+	source.loaded = &now
+	source.servers = servers
+}
+
+// GetServers returns the per server overrides of the last loaded file
+func (source *ConfigFileManager) GetServers() map[string]ConfigServer {
+	source.mutex.Lock()
+	defer source.mutex.Unlock()
+
+	servers := make(map[string]ConfigServer, len(source.servers))
+	for token, server := range source.servers {
+		servers[token] = server
+	}
+	return servers
+}
+
+// GetLoaded returns when the file was last loaded, nil if never
+func (source *ConfigFileManager) GetLoaded() *time.Time {
+	source.mutex.Lock()
+	defer source.mutex.Unlock()
+	return source.loaded
+}
+
+// OnReload registers a callback for each successful reload, receiving the changed reloadable settings
+func (source *ConfigFileManager) OnReload(callback func(changed []string)) {
+	source.mutex.Lock()
+	defer source.mutex.Unlock()
+	source.listeners = append(source.listeners, callback)
+}
+
+// Reload reads the file again, keeping current settings if invalid, and applies the safe settings
+func (source *ConfigFileManager) Reload() (changed []string, err error) {
+	if !Settings.Config.Enabled() {
+		return nil, fmt.Errorf("config file not set, see %s", ENV_CONFIG_FILE)
+	}
+
+	source.mutex.Lock()
+
+	next := NewEnvironmentSettings()
+	path := next.Config.File
+	servers, err := LoadConfigFile(path, &next)
+	if err != nil {
+		source.mutex.Unlock()
+		return nil, fmt.Errorf("config file (%s): %w", path, err)
+	}
+
+	// reloads are the only writers, reading without the settings lock is safe here
+	current, updated := Settings.GetFlatten(), next.GetFlatten()
+	reloadable := map[string]bool{}
+	settingsMutex.Lock()
+	for _, setting := range ConfigReloadable {
+		reloadable[setting.Path] = true
+		if current[setting.Path] != updated[setting.Path] {
+			setting.Apply(&Settings, &next)
+			changed = append(changed, setting.Path)
+		}
+	}
+	settingsMutex.Unlock()
+
+	pending := []string{}
+	for key, value := range updated {
+		if !reloadable[key] && current[key] != value {
+			pending = append(pending, key)
+		}
+	}
+
+	if len(pending) > 0 {
+		sort.Strings(pending)
+		logrus.Warnf("config file, changes applied only after restart: %v", pending)
+	}
+
+	source.update(path, servers)
+	listeners := append([]func(changed []string){}, source.listeners...)
+	source.mutex.Unlock()
+
+	logrus.Infof("config file reloaded, changed: %v", changed)
+	for _, callback := range listeners {
+		callback(changed)
+	}
+
+	return
+}
+
+// Start reloads on SIGHUP and, if watching, when the file modification time changes
+func (source *ConfigFileManager) Start() {
+	if !Settings.Config.Enabled() {
+		return
+	}
+
+	source.mutex.Lock()
+	defer source.mutex.Unlock()
+
+	if source.stop == nil {
+		source.stop = make(chan struct{})
+		go source.watch(source.stop)
+	}
+}
+
+func (source *ConfigFileManager) watch(stop chan struct{}) {
+	signals := make(chan os.Signal, 1)
+	signal.Notify(signals, syscall.SIGHUP)
+	defer signal.Stop(signals)
+
+	var tick <-chan time.Time
+	if interval := Settings.Config.GetWatchInterval(); interval > 0 {
+		ticker := time.NewTicker(interval)
+		defer ticker.Stop()
+		tick = ticker.C
+	}
+
+	for {
+		select {
+		case <-stop:
+			return
+		case <-signals:
+			source.reload("SIGHUP")
+		case <-tick:
+			if source.isModified() {
+				source.reload("file change")
+			}
+		}
+	}
+}
+
+func (source *ConfigFileManager) reload(cause string) {
+	logrus.Infof("config file reload, cause: %s", cause)
+	if _, err := source.Reload(); err != nil {
+		logrus.Errorf("config file not reloaded, keeping current settings: %s", err.Error())
+	}
+}
+
+func (source *ConfigFileManager) isModified() bool {
+	info, err := os.Stat(Settings.Config.File)
+	if err != nil {
+		return false
+	}
+
+	source.mutex.Lock()
+	defer source.mutex.Unlock()
+
+	if info.ModTime().Equal(source.modified) {
+		return false
+	}
+
+	// invalid files are not retried until changed again
+	source.modified = info.ModTime()
+	return true
+}
+
+// Shutdown stops watching for changes
+func (source *ConfigFileManager) Shutdown() {
+	source.mutex.Lock()
+	defer source.mutex.Unlock()
+
+	if source.stop != nil {
+		close(source.stop)
+		source.stop = nil
+	}
+}
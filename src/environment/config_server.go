@@ -0,0 +1,38 @@
+package environment
+
+import (
+	"fmt"
+
+	library "github.com/nocodeleaks/quepasa/library"
+	whatsapp "github.com/nocodeleaks/quepasa/whatsapp"
+)
+
+// ConfigServer holds the overrides of a server, keyed by token on the configuration file.
+// Unset options keep the current value.
+type ConfigServer struct {
+	whatsapp.WhatsappOptions
+
+	Devel       *bool               `json:"devel,omitempty"`
+	Dispatching []ConfigDispatching `json:"dispatching,omitempty"`
+}
+
+// Validate checks the dispatching entries of the server
+func (source ConfigServer) Validate() error {
+	for index, dispatching := range source.Dispatching {
+		if err := dispatching.Validate(); err != nil {
+			return fmt.Errorf("dispatching %d: %w", index, err)
+		}
+	}
+	return nil
+}
+
+// GetRedacted returns a copy with dispatching passwords masked
+func (source ConfigServer) GetRedacted() ConfigServer {
+	redacted := source
+	redacted.Dispatching = make([]ConfigDispatching, len(source.Dispatching))
+	for index, dispatching := range source.Dispatching {
+		dispatching.ConnectionString = library.RedactURL(dispatching.ConnectionString)
+		redacted.Dispatching[index] = dispatching
+	}
+	return redacted
+}
@@ -0,0 +1,33 @@
+package environment
+
+import "time"
+
+// Config file environment variable names
+const (
+	ENV_CONFIG_FILE  = "CONFIG_FILE"  // yaml file with settings and per server overrides, values override environment (optional)
+	ENV_CONFIG_WATCH = "CONFIG_WATCH" // seconds between checks for file changes, 0 reloads only on SIGHUP (default: 10)
+)
+
+// ConfigSettings holds the configuration file location loaded from environment, never from the file itself
+type ConfigSettings struct {
+	File  string `json:"file"`
+	Watch uint32 `json:"watch"`
+}
+
+// NewConfigSettings creates a new config file settings by loading all values from environment
+func NewConfigSettings() ConfigSettings {
+	return ConfigSettings{
+		File:  getEnvOrDefaultString(ENV_CONFIG_FILE, ""),
+		Watch: getEnvOrDefaultUint32(ENV_CONFIG_WATCH, 10),
+	}
+}
+
+// Enabled returns true if a configuration file is set
+func (config ConfigSettings) Enabled() bool {
+	return len(config.File) > 0
+}
+
+// GetWatchInterval returns the interval between checks for file changes as time.Duration, 0 disables
+func (config ConfigSettings) GetWatchInterval() time.Duration {
+	return time.Duration(config.Watch) * time.Second
+}
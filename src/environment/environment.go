@@ -5,6 +5,7 @@ import (
 	"os"
 	"strconv"
 	"strings"
+	"sync"
 
 	"github.com/joho/godotenv"
 	logrus "github.com/sirupsen/logrus"
@@ -14,22 +15,43 @@ import (
 // This is the main class that aggregates all environment variable management.
 type EnvironmentSettings struct {
 	// Embedded structs for organized access to different environment categories
-	Database  DatabaseSettings
-	API       APISettings
-	WebServer WebServerSettings
-	Swagger   SwaggerSettings
-	Metrics   MetricsSettings
-	Form      FormSettings
-	WhatsApp  WhatsAppSettings
-	Whatsmeow WhatsmeowSettings
-	SIPProxy  SIPProxySettings
-	General   GeneralSettings
-	RabbitMQ  RabbitMQSettings
+	Database  DatabaseSettings  `json:"database"`
+	API       APISettings       `json:"api"`
+	WebServer WebServerSettings `json:"webserver"`
+	Swagger   SwaggerSettings   `json:"swagger"`
+	Metrics   MetricsSettings   `json:"metrics"`
+	Form      FormSettings      `json:"form"`
+	WhatsApp  WhatsAppSettings  `json:"whatsapp"`
+	Whatsmeow WhatsmeowSettings `json:"whatsmeow"`
+	SIPProxy  SIPProxySettings  `json:"sipproxy"`
+	General   GeneralSettings   `json:"general"`
+	RabbitMQ  RabbitMQSettings  `json:"rabbitmq"`
+
+	Transcription TranscriptionSettings `json:"transcription"`
+	SenderPool    SenderPoolSettings    `json:"senderpool"`
+	Cluster       ClusterSettings       `json:"cluster"`
+	Telemetry     TelemetrySettings     `json:"telemetry"`
+	Watchdog      WatchdogSettings      `json:"watchdog"`
+	Users         UsersSettings         `json:"users"`
+	OIDC          OIDCSettings          `json:"oidc"`
+	Audit         AuditSettings         `json:"audit"`
+	Config        ConfigSettings        `json:"config"`
 }
 
 // Settings is the global singleton instance for accessing all environment configurations.
+// Reloadable settings change on config file reloads, read them through the getters of config_file_manager.go.
 var Settings EnvironmentSettings
 
+// settingsMutex guards the reloadable settings while a config file reload applies them
+var settingsMutex sync.RWMutex
+
+// GetSettings returns a copy of all settings, safe while reloading
+func GetSettings() EnvironmentSettings {
+	settingsMutex.RLock()
+	defer settingsMutex.RUnlock()
+	return Settings
+}
+
 // Initialize the global environment manager
 func init() {
 	logentry := logrus.NewEntry(logrus.StandardLogger()).WithField("package", "environment")
@@ -43,7 +65,23 @@ func init() {
 		logentry.Println("Successfully loaded .env file")
 	}
 
-	Settings = EnvironmentSettings{
+	Settings = NewEnvironmentSettings()
+
+	// optional file, overriding environment
+	if Settings.Config.Enabled() {
+		err = ConfigFile.Load(&Settings)
+		if err != nil {
+			logentry.Fatalf("invalid configuration: %s", err.Error())
+		}
+		logentry.Printf("Successfully loaded config file: %s", Settings.Config.File)
+	}
+
+	logentry.Println("Environment Manager ready - All configurations loaded!")
+}
+
+// NewEnvironmentSettings loads all categories from environment
+func NewEnvironmentSettings() EnvironmentSettings {
+	return EnvironmentSettings{
 		Database:  NewDatabaseSettings(),
 		API:       NewAPISettings(),
 		WebServer: NewWebServerSettings(),
@@ -55,9 +93,17 @@ func init() {
 		SIPProxy:  NewSIPProxySettings(),
 		General:   NewGeneralSettings(),
 		RabbitMQ:  NewRabbitMQSettings(),
-	}
 
-	logentry.Println("Environment Manager ready - All configurations loaded!")
+		Transcription: NewTranscriptionSettings(),
+		SenderPool:    NewSenderPoolSettings(),
+		Cluster:       NewClusterSettings(),
+		Telemetry:     NewTelemetrySettings(),
+		Watchdog:      NewWatchdogSettings(),
+		Users:         NewUsersSettings(),
+		OIDC:          NewOIDCSettings(),
+		Audit:         NewAuditSettings(),
+		Config:        NewConfigSettings(),
+	}
 }
 
 // ErrEnvVarEmpty is returned when an environment variable is requested but is empty.
@@ -6,13 +6,15 @@ const (
 	ENV_METRICS_PREFIX           = "METRICS_PREFIX"           // metrics endpoint path prefix (default: "metrics")
 	ENV_METRICS_DASHBOARD        = "METRICS_DASHBOARD"        // dashboard endpoint enable/disable
 	ENV_METRICS_DASHBOARD_PREFIX = "METRICS_DASHBOARD_PREFIX" // dashboard endpoint path prefix (default: "dashboard")
+	ENV_METRICS_MAX_SERVERS      = "METRICS_MAX_SERVERS"      // maximum servers with labeled metrics, cardinality guard (default: 500)
 )
 
 // MetricsSettings holds all Metrics configuration loaded from environment
 type MetricsSettings struct {
-	Enabled   bool              `json:"enabled"`
-	Prefix    string            `json:"prefix"`
-	Dashboard DashboardSettings `json:"dashboard"`
+	Enabled    bool              `json:"enabled"`
+	Prefix     string            `json:"prefix"`
+	MaxServers uint32            `json:"max_servers"`
+	Dashboard  DashboardSettings `json:"dashboard"`
 }
 
 // DashboardSettings holds all Dashboard configuration loaded from environment
@@ -24,8 +26,9 @@ type DashboardSettings struct {
 // NewMetricsSettings creates a new Metrics settings by loading all values from environment
 func NewMetricsSettings() MetricsSettings {
 	return MetricsSettings{
-		Enabled: getEnvOrDefaultBool(ENV_METRICS, true),
-		Prefix:  getEnvOrDefaultString(ENV_METRICS_PREFIX, "metrics"),
+		Enabled:    getEnvOrDefaultBool(ENV_METRICS, true),
+		Prefix:     getEnvOrDefaultString(ENV_METRICS_PREFIX, "metrics"),
+		MaxServers: getEnvOrDefaultUint32(ENV_METRICS_MAX_SERVERS, 500),
 		Dashboard: DashboardSettings{
 			Enabled: getEnvOrDefaultBool(ENV_METRICS_DASHBOARD, true),
 			Prefix:  getEnvOrDefaultString(ENV_METRICS_DASHBOARD_PREFIX, "dashboard"),
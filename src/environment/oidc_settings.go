@@ -0,0 +1,93 @@
+package environment
+
+import "strings"
+
+// OIDC environment variable names
+const (
+	ENV_OIDC_ISSUER          = "OIDC_ISSUER"          // issuer url, enables single sign-on on the web login (optional)
+	ENV_OIDC_CLIENT_ID       = "OIDC_CLIENT_ID"       // client id registered on the provider
+	ENV_OIDC_CLIENT_SECRET   = "OIDC_CLIENT_SECRET"   // client secret, empty for public clients relying on PKCE only (optional)
+	ENV_OIDC_REDIRECT_URL    = "OIDC_REDIRECT_URL"    // callback url registered on the provider (default: derived from request, {scheme}://{host}/login/oidc/callback)
+	ENV_OIDC_SCOPES          = "OIDC_SCOPES"          // requested scopes, space or comma separated (default: "openid email profile")
+	ENV_OIDC_USERNAME_CLAIM  = "OIDC_USERNAME_CLAIM"  // id token claim used as local username (default: "email")
+	ENV_OIDC_GROUPS_CLAIM    = "OIDC_GROUPS_CLAIM"    // id token claim holding the user groups (default: "groups")
+	ENV_OIDC_ADMIN_GROUPS    = "OIDC_ADMIN_GROUPS"    // comma separated groups mapped to admin role (optional)
+	ENV_OIDC_OPERATOR_GROUPS = "OIDC_OPERATOR_GROUPS" // comma separated groups mapped to operator role (optional)
+	ENV_OIDC_VIEWER_GROUPS   = "OIDC_VIEWER_GROUPS"   // comma separated groups mapped to viewer role (optional)
+	ENV_OIDC_DEFAULT_ROLE    = "OIDC_DEFAULT_ROLE"    // role for users without mapped groups, "none" denies them (default: "viewer")
+	ENV_OIDC_AUTO_PROVISION  = "OIDC_AUTO_PROVISION"  // creates unknown users on first login (default: true)
+)
+
+// OIDC role that denies users without mapped groups
+const OIDCRoleNone = "none"
+
+// OIDCSettings holds the OpenID Connect single sign-on configuration loaded from environment
+type OIDCSettings struct {
+	Issuer         string   `json:"issuer"`
+	ClientId       string   `json:"client_id"`
+	ClientSecret   string   `json:"-"`
+	RedirectURL    string   `json:"redirect_url"`
+	Scopes         []string `json:"scopes"`
+	UsernameClaim  string   `json:"username_claim"`
+	GroupsClaim    string   `json:"groups_claim"`
+	AdminGroups    []string `json:"admin_groups"`
+	OperatorGroups []string `json:"operator_groups"`
+	ViewerGroups   []string `json:"viewer_groups"`
+	DefaultRole    string   `json:"default_role"`
+	AutoProvision  bool     `json:"auto_provision"`
+}
+
+// NewOIDCSettings creates a new OIDC settings by loading all values from environment
+func NewOIDCSettings() OIDCSettings {
+	return OIDCSettings{
+		Issuer:         strings.TrimRight(getEnvOrDefaultString(ENV_OIDC_ISSUER, ""), "/"),
+		ClientId:       getEnvOrDefaultString(ENV_OIDC_CLIENT_ID, ""),
+		ClientSecret:   getEnvOrDefaultString(ENV_OIDC_CLIENT_SECRET, ""),
+		RedirectURL:    getEnvOrDefaultString(ENV_OIDC_REDIRECT_URL, ""),
+		Scopes:         splitList(getEnvOrDefaultString(ENV_OIDC_SCOPES, "openid email profile")),
+		UsernameClaim:  getEnvOrDefaultString(ENV_OIDC_USERNAME_CLAIM, "email"),
+		GroupsClaim:    getEnvOrDefaultString(ENV_OIDC_GROUPS_CLAIM, "groups"),
+		AdminGroups:    splitList(getEnvOrDefaultString(ENV_OIDC_ADMIN_GROUPS, "")),
+		OperatorGroups: splitList(getEnvOrDefaultString(ENV_OIDC_OPERATOR_GROUPS, "")),
+		ViewerGroups:   splitList(getEnvOrDefaultString(ENV_OIDC_VIEWER_GROUPS, "")),
+		DefaultRole:    strings.ToLower(getEnvOrDefaultString(ENV_OIDC_DEFAULT_ROLE, "viewer")),
+		AutoProvision:  getEnvOrDefaultBool(ENV_OIDC_AUTO_PROVISION, true),
+	}
+}
+
+// Enabled returns true if single sign-on is configured
+func (config OIDCSettings) Enabled() bool {
+	return len(config.Issuer) > 0 && len(config.ClientId) > 0
+}
+
+// GetRole maps the user groups to a role, the most privileged match wins.
+// Returns the default role and false when no group matches.
+func (config OIDCSettings) GetRole(groups []string) (role string, matched bool) {
+	mappings := []struct {
+		role   string
+		groups []string
+	}{
+		{"admin", config.AdminGroups},
+		{"operator", config.OperatorGroups},
+		{"viewer", config.ViewerGroups},
+	}
+
+	for _, mapping := range mappings {
+		for _, group := range groups {
+			for _, item := range mapping.groups {
+				if strings.EqualFold(group, item) {
+					return mapping.role, true
+				}
+			}
+		}
+	}
+
+	return config.DefaultRole, false
+}
+
+// splitList splits a comma or space separated value, ignoring empty items
+func splitList(value string) []string {
+	return strings.FieldsFunc(value, func(r rune) bool {
+		return r == ',' || r == ' '
+	})
+}
@@ -0,0 +1,30 @@
+package environment
+
+// Sender pool environment variable names
+const (
+	ENV_SENDERPOOL_STRATEGY    = "SENDERPOOL_STRATEGY"    // server selection for master key spam: roundrobin | lru
+	ENV_SENDERPOOL_DAILY_QUOTA = "SENDERPOOL_DAILY_QUOTA" // maximum messages per server per day (0 = unlimited)
+	ENV_SENDERPOOL_STICKY      = "SENDERPOOL_STICKY"      // same recipient always gets the same sender
+)
+
+// sender pool selection strategies
+const (
+	SenderPoolRoundRobin        = "roundrobin"
+	SenderPoolLeastRecentlyUsed = "lru"
+)
+
+// SenderPoolSettings holds the master key sender pool configuration loaded from environment
+type SenderPoolSettings struct {
+	Strategy   string `json:"strategy"`
+	DailyQuota uint32 `json:"daily_quota"`
+	Sticky     bool   `json:"sticky"`
+}
+
+// NewSenderPoolSettings creates a new sender pool settings by loading all values from environment
+func NewSenderPoolSettings() SenderPoolSettings {
+	return SenderPoolSettings{
+		Strategy:   getEnvOrDefaultString(ENV_SENDERPOOL_STRATEGY, SenderPoolRoundRobin),
+		DailyQuota: getEnvOrDefaultUint32(ENV_SENDERPOOL_DAILY_QUOTA, 0),
+		Sticky:     getEnvOrDefaultBool(ENV_SENDERPOOL_STICKY, true),
+	}
+}
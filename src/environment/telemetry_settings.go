@@ -0,0 +1,32 @@
+package environment
+
+// Telemetry (OpenTelemetry tracing) environment variable names
+const (
+	ENV_TELEMETRY                = "TELEMETRY"                // enables tracing, exported through OTLP (default: false)
+	ENV_TELEMETRY_ENDPOINT       = "TELEMETRY_ENDPOINT"       // OTLP/HTTP traces endpoint url, http scheme disables tls (default: OTEL_EXPORTER_OTLP_* or https://localhost:4318)
+	ENV_TELEMETRY_SERVICE_NAME   = "TELEMETRY_SERVICE_NAME"   // service name attached to traces (default: quepasa)
+	ENV_TELEMETRY_SAMPLE_PERCENT = "TELEMETRY_SAMPLE_PERCENT" // percent of new traces sampled, propagated ones follow parent (default: 100)
+)
+
+// TelemetrySettings holds the tracing configuration loaded from environment
+type TelemetrySettings struct {
+	Enabled       bool   `json:"enabled"`
+	Endpoint      string `json:"endpoint"`
+	ServiceName   string `json:"service_name"`
+	SamplePercent uint32 `json:"sample_percent"`
+}
+
+// NewTelemetrySettings creates a new telemetry settings by loading all values from environment
+func NewTelemetrySettings() TelemetrySettings {
+	percent := getEnvOrDefaultUint32(ENV_TELEMETRY_SAMPLE_PERCENT, 100)
+	if percent > 100 {
+		percent = 100
+	}
+
+	return TelemetrySettings{
+		Enabled:       getEnvOrDefaultBool(ENV_TELEMETRY, false),
+		Endpoint:      getEnvOrDefaultString(ENV_TELEMETRY_ENDPOINT, ""),
+		ServiceName:   getEnvOrDefaultString(ENV_TELEMETRY_SERVICE_NAME, "quepasa"),
+		SamplePercent: percent,
+	}
+}
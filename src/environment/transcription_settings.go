@@ -0,0 +1,44 @@
+package environment
+
+// Transcription environment variable names
+const (
+	ENV_TRANSCRIPTION_BACKEND  = "TRANSCRIPTION_BACKEND"  // speech-to-text backend: exec | http (empty = disabled)
+	ENV_TRANSCRIPTION_COMMAND  = "TRANSCRIPTION_COMMAND"  // exec backend command line, placeholders {file} {wav} {language}
+	ENV_TRANSCRIPTION_URL      = "TRANSCRIPTION_URL"      // http backend endpoint
+	ENV_TRANSCRIPTION_TOKEN    = "TRANSCRIPTION_TOKEN"    // http backend bearer token
+	ENV_TRANSCRIPTION_MODEL    = "TRANSCRIPTION_MODEL"    // http backend model name
+	ENV_TRANSCRIPTION_LANGUAGE = "TRANSCRIPTION_LANGUAGE" // language hint (ISO 639-1)
+	ENV_TRANSCRIPTION_TIMEOUT  = "TRANSCRIPTION_TIMEOUT"  // transcription timeout in seconds
+	ENV_TRANSCRIPTION_DEFAULT  = "TRANSCRIPTION_DEFAULT"  // transcribe voice notes for servers without explicit option
+)
+
+// TranscriptionSettings holds all speech-to-text configuration loaded from environment
+type TranscriptionSettings struct {
+	Backend  string `json:"backend"`
+	Command  string `json:"command"`
+	Url      string `json:"url"`
+	Token    string `json:"-"`
+	Model    string `json:"model"`
+	Language string `json:"language"`
+	Timeout  uint32 `json:"timeout"`
+	Default  bool   `json:"default"`
+}
+
+// NewTranscriptionSettings creates a new transcription settings by loading all values from environment
+func NewTranscriptionSettings() TranscriptionSettings {
+	return TranscriptionSettings{
+		Backend:  getEnvOrDefaultString(ENV_TRANSCRIPTION_BACKEND, ""),
+		Command:  getEnvOrDefaultString(ENV_TRANSCRIPTION_COMMAND, ""),
+		Url:      getEnvOrDefaultString(ENV_TRANSCRIPTION_URL, ""),
+		Token:    getEnvOrDefaultString(ENV_TRANSCRIPTION_TOKEN, ""),
+		Model:    getEnvOrDefaultString(ENV_TRANSCRIPTION_MODEL, ""),
+		Language: getEnvOrDefaultString(ENV_TRANSCRIPTION_LANGUAGE, ""),
+		Timeout:  getEnvOrDefaultUint32(ENV_TRANSCRIPTION_TIMEOUT, 60),
+		Default:  getEnvOrDefaultBool(ENV_TRANSCRIPTION_DEFAULT, false),
+	}
+}
+
+// Enabled returns true if a speech-to-text backend is configured
+func (config *TranscriptionSettings) Enabled() bool {
+	return len(config.Backend) > 0
+}
@@ -0,0 +1,28 @@
+package environment
+
+import "time"
+
+// Users environment variable names
+const (
+	ENV_USERS_LOCKOUT_ATTEMPTS = "USERS_LOCKOUT_ATTEMPTS" // failed logins before the account is locked, 0 disables (default: 5)
+	ENV_USERS_LOCKOUT_DURATION = "USERS_LOCKOUT_DURATION" // seconds an account remains locked after too many failed logins (default: 900)
+)
+
+// UsersSettings holds the user accounts configuration loaded from environment
+type UsersSettings struct {
+	LockoutAttempts uint32 `json:"lockout_attempts"`
+	LockoutDuration uint32 `json:"lockout_duration"`
+}
+
+// NewUsersSettings creates a new users settings by loading all values from environment
+func NewUsersSettings() UsersSettings {
+	return UsersSettings{
+		LockoutAttempts: getEnvOrDefaultUint32(ENV_USERS_LOCKOUT_ATTEMPTS, 5),
+		LockoutDuration: getEnvOrDefaultUint32(ENV_USERS_LOCKOUT_DURATION, 900),
+	}
+}
+
+// GetLockoutDuration returns how long an account remains locked as time.Duration
+func (config UsersSettings) GetLockoutDuration() time.Duration {
+	return time.Duration(config.LockoutDuration) * time.Second
+}
@@ -0,0 +1,72 @@
+package environment
+
+import "time"
+
+// Watchdog environment variable names
+const (
+	ENV_WATCHDOG             = "WATCHDOG"             // enables the connection watchdog, restarts unhealthy sessions (default: false)
+	ENV_WATCHDOG_INTERVAL    = "WATCHDOG_INTERVAL"    // seconds between checks (default: 30)
+	ENV_WATCHDOG_DOWN        = "WATCHDOG_DOWN"        // seconds a session may remain disconnected, failed or unprepared before a restart (default: 60)
+	ENV_WATCHDOG_IDLE        = "WATCHDOG_IDLE"        // seconds a ready session may remain without events before considered stuck, 0 disables (default: 0)
+	ENV_WATCHDOG_BACKOFF     = "WATCHDOG_BACKOFF"     // seconds before the first restart retry, doubled on each failure (default: 30)
+	ENV_WATCHDOG_BACKOFF_MAX = "WATCHDOG_BACKOFF_MAX" // maximum seconds between restart retries (default: 900)
+	ENV_WATCHDOG_WEBHOOK     = "WATCHDOG_WEBHOOK"     // url receiving state change alerts, posted as json (optional)
+)
+
+// WatchdogSettings holds the connection watchdog configuration loaded from environment
+type WatchdogSettings struct {
+	Enabled    bool   `json:"enabled"`
+	Interval   uint32 `json:"interval"`
+	Down       uint32 `json:"down"`
+	Idle       uint32 `json:"idle"`
+	Backoff    uint32 `json:"backoff"`
+	BackoffMax uint32 `json:"backoff_max"`
+	Webhook    string `json:"webhook"`
+}
+
+// NewWatchdogSettings creates a new watchdog settings by loading all values from environment
+func NewWatchdogSettings() WatchdogSettings {
+	interval := getEnvOrDefaultUint32(ENV_WATCHDOG_INTERVAL, 30)
+	if interval < 1 {
+		interval = 1
+	}
+
+	backoff := getEnvOrDefaultUint32(ENV_WATCHDOG_BACKOFF, 30)
+	if backoff < 1 {
+		backoff = 1
+	}
+
+	backoffMax := getEnvOrDefaultUint32(ENV_WATCHDOG_BACKOFF_MAX, 900)
+	if backoffMax < backoff {
+		backoffMax = backoff
+	}
+
+	return WatchdogSettings{
+		Enabled:    getEnvOrDefaultBool(ENV_WATCHDOG, false),
+		Interval:   interval,
+		Down:       getEnvOrDefaultUint32(ENV_WATCHDOG_DOWN, 60),
+		Idle:       getEnvOrDefaultUint32(ENV_WATCHDOG_IDLE, 0),
+		Backoff:    backoff,
+		BackoffMax: backoffMax,
+		Webhook:    getEnvOrDefaultString(ENV_WATCHDOG_WEBHOOK, ""),
+	}
+}
+
+// GetInterval returns the interval between checks as time.Duration
+func (config WatchdogSettings) GetInterval() time.Duration {
+	return time.Duration(config.Interval) * time.Second
+}
+
+// GetBackoff returns the delay before the next restart, after the given failed attempts
+func (config WatchdogSettings) GetBackoff(attempts uint) time.Duration {
+	delay := time.Duration(config.Backoff) * time.Second
+	limit := time.Duration(config.BackoffMax) * time.Second
+	for i := uint(0); i < attempts && delay < limit; i++ {
+		delay *= 2
+	}
+
+	if delay > limit {
+		delay = limit
+	}
+	return delay
+}
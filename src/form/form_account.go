@@ -100,5 +100,13 @@ func SetupHandler(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
+	// the first account of a fresh install manages the others
+	err = models.UserManager.EnsureAdmin(email)
+	if err != nil {
+		data.ErrorMessage = err.Error()
+		renderSetupForm(w, data)
+		return
+	}
+
 	RedirectToLogin(w, r)
 }
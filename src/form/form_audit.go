@@ -0,0 +1,48 @@
+package form
+
+import (
+	"net/http"
+	"strings"
+
+	api "github.com/nocodeleaks/quepasa/api"
+	library "github.com/nocodeleaks/quepasa/library"
+	models "github.com/nocodeleaks/quepasa/models"
+)
+
+// AuditForm records an action taken by a web user, target server token and summary are optional
+func AuditForm(r *http.Request, user *models.QpUser, action string, token string, summary string, err error) {
+	if user == nil || !models.AuditManager.Enabled() {
+		return
+	}
+
+	entry := &models.QpAuditEntry{
+		ActorType: models.AuditActorUser,
+		Actor:     user.Username,
+		Action:    "FORM " + action,
+		Token:     token,
+		Summary:   summary,
+		Success:   err == nil,
+		Result:    "ok",
+		Source:    api.GetAuditSource(r),
+	}
+
+	if err != nil {
+		entry.Result = err.Error()
+	}
+
+	models.AuditManager.Record(entry)
+}
+
+// GetFormAuditSummary collects key and target of toggle and delete forms, hiding url credentials
+func GetFormAuditSummary(r *http.Request) string {
+	items := []string{}
+	for _, field := range []string{"key", "url", "connection_string"} {
+		if value := library.GetRequestParameter(r, field); len(value) > 0 {
+			if field != "key" {
+				value = library.RedactURL(value)
+			}
+			items = append(items, field+"="+value)
+		}
+	}
+	return strings.Join(items, "; ")
+}
@@ -1,10 +1,12 @@
 package form
 
 import (
+	"fmt"
 	"html/template"
 	"net/http"
 
 	"github.com/go-chi/chi/v5"
+	"github.com/go-chi/chi/v5/middleware"
 	"github.com/go-chi/jwtauth"
 
 	api "github.com/nocodeleaks/quepasa/api"
@@ -30,6 +32,7 @@ var FormWebHooksEndpoint string = FormEndpointPrefix + "/webhooks"
 var FormRabbitMQEndpoint string = FormEndpointPrefix + "/rabbitmq"
 var FormVerifyEndpoint string = FormEndpointPrefix + "/verify"
 var FormDeleteEndpoint string = FormEndpointPrefix + "/delete"
+var FormUsersEndpoint string = FormEndpointPrefix + "/users"
 
 func RegisterFormAuthenticatedControllers(r chi.Router) {
 
@@ -38,20 +41,29 @@ func RegisterFormAuthenticatedControllers(r chi.Router) {
 
 	r.Use(HttpAuthenticatorHandler)
 
-	r.HandleFunc(FormWebsocketEndpoint, VerifyHandler)
+	// operators and admins only, viewers are read only
+	operator := r.With(FormRoleHandler(models.UserRoleOperator))
+	admin := r.With(FormRoleHandler(models.UserRoleAdmin))
+
+	operator.HandleFunc(FormWebsocketEndpoint, VerifyHandler)
 	r.Get(FormAccountEndpoint, FormAccountController)
 	r.Get(FormWebHooksEndpoint, FormWebHooksController)
 	r.Get(FormRabbitMQEndpoint, FormRabbitMQController)
-	r.Get(FormVerifyEndpoint, VerifyFormHandler)
+	operator.Post(FormWebHooksEndpoint, FormDispatchingController("webhook", api.WebhookController))
+	operator.Post(FormRabbitMQEndpoint, FormDispatchingController("rabbitmq", api.RabbitMQController))
+	operator.Get(FormVerifyEndpoint, VerifyFormHandler)
 
-	r.Post(FormDeleteEndpoint, FormDeleteController)
-	r.Post(FormEndpointPrefix+"/debug", FormDebugController)
-	r.Post(FormEndpointPrefix+"/toggle", FormToggleController)
+	operator.Post(FormDeleteEndpoint, FormDeleteController)
+	operator.Post(FormEndpointPrefix+"/debug", FormDebugController)
+	operator.Post(FormEndpointPrefix+"/toggle", FormToggleController)
 
-	r.Get(FormEndpointPrefix+"/server/{token}", FormSendController)
-	r.Get(FormEndpointPrefix+"/server/{token}/send", FormSendController)
-	r.Post(FormEndpointPrefix+"/server/{token}/send", FormSendController)
+	operator.Get(FormEndpointPrefix+"/server/{token}", FormSendController)
+	operator.Get(FormEndpointPrefix+"/server/{token}/send", FormSendController)
+	operator.Post(FormEndpointPrefix+"/server/{token}/send", FormSendController)
 	r.Get(FormEndpointPrefix+"/server/{token}/receive", FormReceiveController)
+
+	admin.Get(FormUsersEndpoint, FormUsersController)
+	admin.Post(FormUsersEndpoint, FormUsersActionController)
 }
 
 // Authentication manager on forms
@@ -69,10 +81,37 @@ func HttpAuthenticatorHandler(next http.Handler) http.Handler {
 			return
 		}
 
+		// deleted or disabled users lose their sessions immediately
+		_, err = GetFormUser(r)
+		if err != nil {
+			LogoutHandler(w, r)
+			return
+		}
+
 		next.ServeHTTP(w, r)
 	})
 }
 
+// FormRoleHandler allows only users with at least the given role
+func FormRoleHandler(role string) func(http.Handler) http.Handler {
+	return func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			user, err := GetFormUser(r)
+			if err != nil {
+				RedirectToLogin(w, r)
+				return
+			}
+
+			if !user.HasRole(role) {
+				api.RespondErrorCode(w, models.ErrUserForbidden, http.StatusForbidden)
+				return
+			}
+
+			next.ServeHTTP(w, r)
+		})
+	}
+}
+
 // Renders route GET "/{prefix}/account"
 func FormAccountController(w http.ResponseWriter, r *http.Request) {
 	user, err := GetFormUser(r)
@@ -99,7 +138,13 @@ func FormAccountController(w http.ResponseWriter, r *http.Request) {
 		data.HasSignalRActiveConnections = signalr.SignalRHub.HasActiveConnections(masterkey)
 	}
 
-	data.Servers = models.GetServersForUser(user)
+	// viewers get masked tokens, a token grants full api access
+	if user.IsAdmin() {
+		data.Servers = GetAllServers()
+	} else {
+		data.Servers = models.GetServersForUser(user)
+	}
+
 	data.Version = models.QpVersion
 	templates := template.Must(template.ParseFiles(GetViewPath("layouts/main.tmpl"), GetViewPath("account.tmpl")))
 	templates.ExecuteTemplate(w, "main", data)
@@ -118,20 +163,16 @@ func FormWebHooksController(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
-	data := models.QPFormWebHooksData{PageTitle: "WebHooks"}
+	data := models.QPFormWebHooksData{PageTitle: "WebHooks", ReadOnly: !user.CanWrite()}
 
 	token := library.GetRequestParameter(r, "token")
 	if len(token) > 0 {
-		server, err := models.GetServerFromToken(token)
+		server, err := FindFormServer(user, token)
 		if err != nil {
-			data.ErrorMessage = "server not found"
+			data.ErrorMessage = "server token not found or dont owned by you"
 		} else {
-			if server.User != user.Username {
-				data.ErrorMessage = "server token not found or dont owned by you"
-			} else {
-				data.Server = server
-				data.Webhooks = server.GetWebhooks()
-			}
+			data.Server = server
+			data.Webhooks = server.GetWebhooks()
 		}
 	} else {
 		data.ErrorMessage = "missing token"
@@ -155,6 +196,7 @@ func FormRabbitMQController(w http.ResponseWriter, r *http.Request) {
 		ErrorMessage string                     `json:"errormessage,omitempty"`
 		Server       *models.QpWhatsappServer   `json:"server,omitempty"`
 		RabbitMQ     []*models.QpRabbitMQConfig `json:"rabbitmq,omitempty"`
+		ReadOnly     bool                       `json:"readonly,omitempty"` // viewers, no token and no changes
 	}
 
 	data := FormRabbitMQControllerData{
@@ -163,12 +205,11 @@ func FormRabbitMQController(w http.ResponseWriter, r *http.Request) {
 
 	user, err := GetFormUser(r)
 	if err == nil {
+		data.ReadOnly = !user.CanWrite()
 		token := r.URL.Query().Get("token")
 		if len(token) > 0 {
-			server, err := models.GetServerFromToken(token)
+			server, err := FindFormServer(user, token)
 			if err != nil {
-				data.ErrorMessage = "server token not found: " + err.Error()
-			} else if server.User != user.Username {
 				data.ErrorMessage = "server token not found or dont owned by you"
 			} else {
 				data.Server = server
@@ -186,3 +227,37 @@ func FormRabbitMQController(w http.ResponseWriter, r *http.Request) {
 
 	templates.ExecuteTemplate(w, "main", data)
 }
+
+// FormDispatchingController saves webhooks or rabbitmq entries through the api controller,
+// only on servers the user can access, so pages never call the api with the server token
+func FormDispatchingController(action string, controller http.HandlerFunc) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		user, err := GetFormUser(r)
+		if err != nil {
+			RedirectToLogin(w, r)
+			return
+		}
+
+		server, err := GetServerFromRequest(r)
+		if err != nil {
+			api.RespondErrorCode(w, err, http.StatusNotFound)
+			return
+		}
+
+		summary := ""
+		body := api.PeekJSONBody(r, api.AuditBodyLimit)
+		for _, field := range []string{"url", "connection_string"} {
+			if value, ok := body[field].(string); ok && len(value) > 0 {
+				summary = field + "=" + library.RedactURL(value)
+			}
+		}
+
+		ww := middleware.NewWrapResponseWriter(w, r.ProtoMajor)
+		controller(ww, r)
+
+		if ww.Status() >= http.StatusBadRequest {
+			err = fmt.Errorf("status: %d", ww.Status())
+		}
+		AuditForm(r, user, action, server.Token, summary, err)
+	}
+}
@@ -34,13 +34,14 @@ func WebSocketProtocol() string {
 
 // DebugHandler renders route POST "/bot/debug"
 func FormDebugController(w http.ResponseWriter, r *http.Request) {
-	_, server, err := GetUserAndServer(w, r)
+	user, server, err := GetUserAndServer(w, r)
 	if err != nil {
 		// retorno já tratado pela funcao
 		return
 	}
 
 	_, err = server.ToggleDevel()
+	AuditForm(r, user, "debug", server.Token, "", err)
 	if err != nil {
 		api.RespondServerError(server, w, err)
 		return
@@ -55,13 +56,14 @@ func FormDebugController(w http.ResponseWriter, r *http.Request) {
 func FormToggleController(w http.ResponseWriter, r *http.Request) {
 	var err error
 
-	_, server, err := GetUserAndServer(w, r)
+	user, server, err := GetUserAndServer(w, r)
 	if err != nil {
 		return
 	}
 
 	destination := FormAccountEndpoint
 	key := library.GetRequestParameter(r, "key")
+	defer func() { AuditForm(r, user, "toggle", server.Token, GetFormAuditSummary(r), err) }()
 	if len(key) > 0 {
 
 		if strings.HasPrefix(key, "server") {
@@ -92,6 +94,11 @@ func FormToggleController(w http.ResponseWriter, r *http.Request) {
 					err = models.ToggleCalls(server)
 					break
 				}
+			case "server-transcription":
+				{
+					err = models.ToggleTranscription(server)
+					break
+				}
 
 			default:
 				{
@@ -246,6 +253,7 @@ func VerifyHandler(w http.ResponseWriter, r *http.Request) {
 	}
 
 	conn, err := upgrader.Upgrade(w, r, nil)
+	AuditForm(r, user, "pair", "", "", err)
 	if err != nil {
 		log.Errorf("(websocket): service error: %s", err.Error())
 		return
@@ -270,13 +278,14 @@ func VerifyHandler(w http.ResponseWriter, r *http.Request) {
 func FormDeleteController(w http.ResponseWriter, r *http.Request) {
 	var err error
 
-	_, server, err := GetUserAndServer(w, r)
+	user, server, err := GetUserAndServer(w, r)
 	if err != nil {
 		return
 	}
 
 	var destination string
 	key := library.GetRequestParameter(r, "key")
+	defer func() { AuditForm(r, user, "delete", server.Token, GetFormAuditSummary(r), err) }()
 	if len(key) > 0 {
 		logentry := server.GetLogger()
 
@@ -357,12 +366,51 @@ func GetUserAndServer(w http.ResponseWriter, r *http.Request) (user *models.QpUs
 		return
 	}
 
+	if !user.CanAccess(server.User) {
+		err = fmt.Errorf("server token not found or dont owned by you")
+		api.RespondErrorCode(w, err, http.StatusForbidden)
+		return
+	}
+
 	return
 }
 
+// GetServerFromRequest gets the server from token, only if the authenticated user can access it
 func GetServerFromRequest(r *http.Request) (server *models.QpWhatsappServer, err error) {
-	token := api.GetToken(r)
-	return models.WhatsappService.FindByToken(token)
+	user, err := GetFormUser(r)
+	if err != nil {
+		return
+	}
+
+	return FindFormServer(user, api.GetToken(r))
+}
+
+// FindFormServer finds a server the user can access by token, or by masked token, as viewers never see full tokens
+func FindFormServer(user *models.QpUser, token string) (*models.QpWhatsappServer, error) {
+	server, err := models.WhatsappService.FindByToken(token)
+	if err != nil {
+		for _, item := range models.WhatsappService.GetServers() {
+			if item.GetMaskedToken() == token && user.CanAccess(item.User) {
+				return item, nil
+			}
+		}
+		return nil, err
+	}
+
+	if !user.CanAccess(server.User) {
+		return nil, fmt.Errorf("server token not found or dont owned by you")
+	}
+
+	return server, nil
+}
+
+// GetAllServers returns a copy of all servers, used for admins
+func GetAllServers() map[string]*models.QpWhatsappServer {
+	servers := make(map[string]*models.QpWhatsappServer)
+	for _, server := range models.WhatsappService.GetServers() {
+		servers[server.Token] = server
+	}
+	return servers
 }
 
 func GetDownloadPrefix(token string) (path string) {
@@ -385,5 +433,5 @@ func GetFormUser(r *http.Request) (*models.QpUser, error) {
 		return nil, models.ErrFormUnauthenticated
 	}
 
-	return models.WhatsappService.DB.Users.Find(user)
+	return models.UserManager.Find(user)
 }
@@ -27,6 +27,12 @@ func RegisterFormControllers(r chi.Router) {
 	r.Post(FormLoginEndpoint, LoginHandler)
 	r.Get(FormLogoutEndpoint, LogoutHandler)
 
+	// single sign-on, coexists with local accounts
+	if environment.Settings.OIDC.Enabled() {
+		r.Get(FormOIDCLoginEndpoint, OIDCLoginHandler)
+		r.Get(FormOIDCCallbackEndpoint, OIDCCallbackHandler)
+	}
+
 	// disable /setup if environment is false
 	if environment.Settings.General.AccountSetup {
 		r.Get(FormSetupEndpoint, SetupFormHandler)
@@ -36,7 +42,10 @@ func RegisterFormControllers(r chi.Router) {
 
 // LoginFormHandler renders route GET "/login"
 func LoginFormHandler(w http.ResponseWriter, r *http.Request) {
-	data := models.QPFormLoginData{PageTitle: "Login"}
+	data := models.QPFormLoginData{
+		PageTitle: "Login",
+		OIDC:      environment.Settings.OIDC.Enabled(),
+	}
 
 	templates := template.Must(template.ParseFiles(GetViewPath("layouts/main.tmpl"), GetViewPath("login.tmpl")))
 	templates.ExecuteTemplate(w, "main", data)
@@ -59,6 +68,18 @@ func LoginHandler(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
+	err = SetSessionCookie(w, user)
+	if err != nil {
+		api.RespondErrorCode(w, err, 500)
+		return
+	}
+
+	log.Debugf("setting cookie and redirecting to: %v", FormAccountEndpoint)
+	http.Redirect(w, r, FormAccountEndpoint, http.StatusFound)
+}
+
+// SetSessionCookie issues the web session token for an authenticated user
+func SetSessionCookie(w http.ResponseWriter, user *models.QpUser) error {
 	claims := jwt.MapClaims{"user_id": user.Username}
 	jwtauth.SetIssuedNow(claims)
 	jwtauth.SetExpiryIn(claims, 24*time.Hour)
@@ -66,8 +87,7 @@ func LoginHandler(w http.ResponseWriter, r *http.Request) {
 	tokenAuth := GetTokenAuth()
 	_, tokenString, err := tokenAuth.Encode(claims)
 	if err != nil {
-		api.RespondErrorCode(w, errors.New("cannot encode token to save"), 500)
-		return
+		return errors.New("cannot encode token to save")
 	}
 
 	cookie := &http.Cookie{
@@ -78,7 +98,6 @@ func LoginHandler(w http.ResponseWriter, r *http.Request) {
 		HttpOnly: true,
 	}
 
-	log.Debugf("setting cookie and redirecting to: %v", FormAccountEndpoint)
 	http.SetCookie(w, cookie)
-	http.Redirect(w, r, FormAccountEndpoint, http.StatusFound)
+	return nil
 }
@@ -0,0 +1,188 @@
+package form
+
+import (
+	"errors"
+	"fmt"
+	"net/http"
+	"strings"
+	"time"
+
+	jwt "github.com/dgrijalva/jwt-go"
+	"github.com/go-chi/jwtauth"
+	api "github.com/nocodeleaks/quepasa/api"
+	environment "github.com/nocodeleaks/quepasa/environment"
+	models "github.com/nocodeleaks/quepasa/models"
+	log "github.com/sirupsen/logrus"
+)
+
+var FormOIDCLoginEndpoint string = "/login/oidc"
+var FormOIDCCallbackEndpoint string = "/login/oidc/callback"
+
+// cookie holding state, nonce and PKCE verifier between login and callback
+const FormOIDCCookieName = "oidc"
+
+// maximum time to complete the login on the provider
+const FormOIDCCookieTimeout = 10 * time.Minute
+
+// GetOIDCRedirectURL returns the configured callback url, or derives it from the request
+func GetOIDCRedirectURL(r *http.Request) string {
+	redirect := environment.Settings.OIDC.RedirectURL
+	if len(redirect) > 0 {
+		return redirect
+	}
+
+	scheme := "http"
+	if r.TLS != nil {
+		scheme = "https"
+	}
+
+	if proto := r.Header.Get("X-Forwarded-Proto"); len(proto) > 0 {
+		scheme = strings.TrimSpace(strings.Split(proto, ",")[0])
+	}
+
+	return scheme + "://" + r.Host + FormOIDCCallbackEndpoint
+}
+
+// OIDCLoginHandler renders route GET "/login/oidc", redirecting to the provider
+func OIDCLoginHandler(w http.ResponseWriter, r *http.Request) {
+	state, err := NewOIDCSecret()
+	if err != nil {
+		api.RespondErrorCode(w, err, http.StatusInternalServerError)
+		return
+	}
+
+	nonce, err := NewOIDCSecret()
+	if err != nil {
+		api.RespondErrorCode(w, err, http.StatusInternalServerError)
+		return
+	}
+
+	verifier, err := NewOIDCSecret()
+	if err != nil {
+		api.RespondErrorCode(w, err, http.StatusInternalServerError)
+		return
+	}
+
+	redirect := GetOIDCRedirectURL(r)
+	destination, err := OIDCProvider.AuthCodeURL(r.Context(), redirect, state, nonce, verifier)
+	if err != nil {
+		log.Errorf("oidc login error: %s", err.Error())
+		api.RespondErrorCode(w, errors.New("single sign-on provider unavailable"), http.StatusBadGateway)
+		return
+	}
+
+	// signed, so the callback trusts its content
+	claims := jwt.MapClaims{"state": state, "nonce": nonce, "verifier": verifier, "redirect": redirect}
+	jwtauth.SetExpiryIn(claims, FormOIDCCookieTimeout)
+	_, tokenString, err := GetTokenAuth().Encode(claims)
+	if err != nil {
+		api.RespondErrorCode(w, errors.New("cannot encode token to save"), http.StatusInternalServerError)
+		return
+	}
+
+	cookie := &http.Cookie{
+		Name:     FormOIDCCookieName,
+		Value:    tokenString,
+		MaxAge:   int(FormOIDCCookieTimeout.Seconds()),
+		Path:     FormOIDCLoginEndpoint,
+		HttpOnly: true,
+		Secure:   strings.HasPrefix(redirect, "https://"),
+		SameSite: http.SameSiteLaxMode,
+	}
+
+	http.SetCookie(w, cookie)
+	http.Redirect(w, r, destination, http.StatusFound)
+}
+
+// OIDCCallbackHandler renders route GET "/login/oidc/callback", signing in the user returned by the provider
+func OIDCCallbackHandler(w http.ResponseWriter, r *http.Request) {
+
+	// single use, cleared whatever the result
+	http.SetCookie(w, &http.Cookie{Name: FormOIDCCookieName, Value: "", MaxAge: -1, Path: FormOIDCLoginEndpoint, HttpOnly: true})
+
+	user, err := OIDCAuthenticate(r)
+	if err != nil {
+		log.Warnf("oidc login denied: %s", err.Error())
+		api.RespondUnauthorized(w, err)
+		return
+	}
+
+	err = SetSessionCookie(w, user)
+	if err != nil {
+		api.RespondErrorCode(w, err, http.StatusInternalServerError)
+		return
+	}
+
+	log.Infof("oidc login for user: %s", user.Username)
+	http.Redirect(w, r, FormAccountEndpoint, http.StatusFound)
+}
+
+// OIDCAuthenticate validates the callback and resolves the local user, provisioning and syncing its role
+func OIDCAuthenticate(r *http.Request) (*models.QpUser, error) {
+	query := r.URL.Query()
+	if code := query.Get("error"); len(code) > 0 {
+		return nil, fmt.Errorf("provider error: %s %s", code, query.Get("error_description"))
+	}
+
+	cookie, err := r.Cookie(FormOIDCCookieName)
+	if err != nil {
+		return nil, errors.New("login session expired, try again")
+	}
+
+	token, err := GetTokenAuth().Decode(cookie.Value)
+	if err != nil || !token.Valid {
+		return nil, errors.New("login session expired, try again")
+	}
+
+	pending, _ := token.Claims.(jwt.MapClaims)
+	state, _ := pending["state"].(string)
+	if len(state) == 0 || state != query.Get("state") {
+		return nil, errors.New("login state mismatch")
+	}
+
+	code := query.Get("code")
+	if len(code) == 0 {
+		return nil, errors.New("missing authorization code")
+	}
+
+	verifier, _ := pending["verifier"].(string)
+	redirect, _ := pending["redirect"].(string)
+	raw, err := OIDCProvider.Exchange(r.Context(), redirect, code, verifier)
+	if err != nil {
+		return nil, err
+	}
+
+	nonce, _ := pending["nonce"].(string)
+	claims, err := OIDCProvider.Verify(r.Context(), raw, nonce)
+	if err != nil {
+		return nil, err
+	}
+
+	settings := environment.Settings.OIDC
+	username, _ := claims[settings.UsernameClaim].(string)
+	if len(username) == 0 {
+		return nil, fmt.Errorf("id token without username claim: %s", settings.UsernameClaim)
+	}
+
+	// an unverified email could impersonate another user, a missing claim is not verified
+	if settings.UsernameClaim == "email" {
+		if verified, _ := claims["email_verified"].(bool); !verified {
+			return nil, fmt.Errorf("email not verified by provider: %s", username)
+		}
+	}
+
+	// users are linked by issuer and subject, never by username only, issuer was checked on verify
+	issuer := settings.Issuer
+	subject, _ := claims["sub"].(string)
+	if len(subject) == 0 {
+		return nil, fmt.Errorf("id token without subject")
+	}
+
+	groups := claimStrings(claims[settings.GroupsClaim])
+	role, matched := settings.GetRole(groups)
+	if role == environment.OIDCRoleNone {
+		return nil, fmt.Errorf("user (%s) not member of any allowed group", username)
+	}
+
+	return models.UserManager.LoginExternal(issuer, subject, username, role, matched, settings.AutoProvision)
+}
@@ -0,0 +1,403 @@
+package form
+
+import (
+	"context"
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/rsa"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"math/big"
+	"net/http"
+	"net/url"
+	"strings"
+	"sync"
+	"time"
+
+	jwt "github.com/dgrijalva/jwt-go"
+	environment "github.com/nocodeleaks/quepasa/environment"
+)
+
+// tolerated clock difference with the provider when checking token times
+const FormOIDCLeeway = 60 * time.Second
+
+// discovery document is refreshed after this interval
+const FormOIDCDiscoveryTTL = time.Hour
+
+// minimum interval between signing keys refreshes, for unknown key ids
+const FormOIDCKeysRefresh = time.Minute
+
+// signature algorithms accepted on id tokens, never symmetric ones
+var FormOIDCSigningMethods = []string{"RS256", "RS384", "RS512", "PS256", "PS384", "PS512", "ES256", "ES384", "ES512"}
+
+// FormOIDCProvider talks to the OpenID Connect provider, caching discovery and signing keys, thread safe
+type FormOIDCProvider struct {
+	mutex      sync.Mutex
+	client     *http.Client
+	discovery  *formOIDCDiscovery
+	discovered time.Time
+	keys       map[string]interface{}
+	fetched    time.Time
+}
+
+type formOIDCDiscovery struct {
+	Issuer                   string   `json:"issuer"`
+	AuthorizationEndpoint    string   `json:"authorization_endpoint"`
+	TokenEndpoint            string   `json:"token_endpoint"`
+	JwksURI                  string   `json:"jwks_uri"`
+	TokenEndpointAuthMethods []string `json:"token_endpoint_auth_methods_supported"`
+}
+
+type formOIDCTokenResponse struct {
+	IdToken          string `json:"id_token"`
+	Error            string `json:"error"`
+	ErrorDescription string `json:"error_description"`
+}
+
+type formOIDCKey struct {
+	Kid string `json:"kid"`
+	Kty string `json:"kty"`
+	Use string `json:"use"`
+	N   string `json:"n"`
+	E   string `json:"e"`
+	Crv string `json:"crv"`
+	X   string `json:"x"`
+	Y   string `json:"y"`
+}
+
+var OIDCProvider = &FormOIDCProvider{
+	client: &http.Client{Timeout: 15 * time.Second},
+}
+
+func (source *FormOIDCProvider) getJSON(ctx context.Context, endpoint string, result interface{}) error {
+	request, err := http.NewRequestWithContext(ctx, http.MethodGet, endpoint, nil)
+	if err != nil {
+		return err
+	}
+
+	request.Header.Set("Accept", "application/json")
+	response, err := source.client.Do(request)
+	if err != nil {
+		return err
+	}
+	defer response.Body.Close()
+
+	if response.StatusCode != http.StatusOK {
+		return fmt.Errorf("unexpected status from %s: %s", endpoint, response.Status)
+	}
+
+	return json.NewDecoder(io.LimitReader(response.Body, 1<<20)).Decode(result)
+}
+
+// getDiscovery returns the provider metadata, refreshed hourly
+func (source *FormOIDCProvider) getDiscovery(ctx context.Context) (*formOIDCDiscovery, error) {
+	source.mutex.Lock()
+	defer source.mutex.Unlock()
+
+	if source.discovery != nil && time.Since(source.discovered) < FormOIDCDiscoveryTTL {
+		return source.discovery, nil
+	}
+
+	issuer := environment.Settings.OIDC.Issuer
+	discovery := &formOIDCDiscovery{}
+	err := source.getJSON(ctx, issuer+"/.well-known/openid-configuration", discovery)
+	if err != nil {
+		return nil, fmt.Errorf("oidc discovery error: %s", err.Error())
+	}
+
+	if strings.TrimRight(discovery.Issuer, "/") != issuer {
+		return nil, fmt.Errorf("oidc discovery issuer mismatch: %s", discovery.Issuer)
+	}
+
+	if len(discovery.AuthorizationEndpoint) == 0 || len(discovery.TokenEndpoint) == 0 || len(discovery.JwksURI) == 0 {
+		return nil, fmt.Errorf("oidc discovery incomplete for issuer: %s", issuer)
+	}
+
+	source.discovery = discovery
+	source.discovered = time.Now()
+	return discovery, nil
+}
+
+// AuthCodeURL builds the authorization request, with PKCE challenge derived from the verifier
+func (source *FormOIDCProvider) AuthCodeURL(ctx context.Context, redirect string, state string, nonce string, verifier string) (string, error) {
+	discovery, err := source.getDiscovery(ctx)
+	if err != nil {
+		return "", err
+	}
+
+	settings := environment.Settings.OIDC
+	challenge := sha256.Sum256([]byte(verifier))
+
+	values := url.Values{}
+	values.Set("response_type", "code")
+	values.Set("client_id", settings.ClientId)
+	values.Set("redirect_uri", redirect)
+	values.Set("scope", strings.Join(settings.Scopes, " "))
+	values.Set("state", state)
+	values.Set("nonce", nonce)
+	values.Set("code_challenge", base64.RawURLEncoding.EncodeToString(challenge[:]))
+	values.Set("code_challenge_method", "S256")
+
+	separator := "?"
+	if strings.Contains(discovery.AuthorizationEndpoint, "?") {
+		separator = "&"
+	}
+
+	return discovery.AuthorizationEndpoint + separator + values.Encode(), nil
+}
+
+// Exchange redeems the authorization code and returns the raw id token
+func (source *FormOIDCProvider) Exchange(ctx context.Context, redirect string, code string, verifier string) (string, error) {
+	discovery, err := source.getDiscovery(ctx)
+	if err != nil {
+		return "", err
+	}
+
+	settings := environment.Settings.OIDC
+
+	values := url.Values{}
+	values.Set("grant_type", "authorization_code")
+	values.Set("code", code)
+	values.Set("redirect_uri", redirect)
+	values.Set("code_verifier", verifier)
+
+	// client_secret_basic is the default method, unless the provider only supports post
+	basic := len(settings.ClientSecret) > 0
+	if basic && len(discovery.TokenEndpointAuthMethods) > 0 && !containsString(discovery.TokenEndpointAuthMethods, "client_secret_basic") {
+		basic = false
+	}
+
+	if !basic {
+		values.Set("client_id", settings.ClientId)
+		if len(settings.ClientSecret) > 0 {
+			values.Set("client_secret", settings.ClientSecret)
+		}
+	}
+
+	request, err := http.NewRequestWithContext(ctx, http.MethodPost, discovery.TokenEndpoint, strings.NewReader(values.Encode()))
+	if err != nil {
+		return "", err
+	}
+
+	request.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+	request.Header.Set("Accept", "application/json")
+	if basic {
+		request.SetBasicAuth(url.QueryEscape(settings.ClientId), url.QueryEscape(settings.ClientSecret))
+	}
+
+	response, err := source.client.Do(request)
+	if err != nil {
+		return "", fmt.Errorf("oidc token request error: %s", err.Error())
+	}
+	defer response.Body.Close()
+
+	result := &formOIDCTokenResponse{}
+	err = json.NewDecoder(io.LimitReader(response.Body, 1<<20)).Decode(result)
+	if err != nil {
+		return "", fmt.Errorf("oidc token response error: %s, status: %s", err.Error(), response.Status)
+	}
+
+	if len(result.Error) > 0 {
+		return "", fmt.Errorf("oidc token error: %s %s", result.Error, result.ErrorDescription)
+	}
+
+	if len(result.IdToken) == 0 {
+		return "", fmt.Errorf("oidc token response without id_token, status: %s", response.Status)
+	}
+
+	return result.IdToken, nil
+}
+
+// Verify checks the id token signature, issuer, audience, times and nonce, returning its claims
+func (source *FormOIDCProvider) Verify(ctx context.Context, raw string, nonce string) (jwt.MapClaims, error) {
+	discovery, err := source.getDiscovery(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	parser := &jwt.Parser{ValidMethods: FormOIDCSigningMethods, SkipClaimsValidation: true}
+	claims := jwt.MapClaims{}
+	_, err = parser.ParseWithClaims(raw, claims, func(token *jwt.Token) (interface{}, error) {
+		kid, _ := token.Header["kid"].(string)
+		return source.getKey(ctx, discovery, kid)
+	})
+	if err != nil {
+		return nil, fmt.Errorf("oidc id token invalid: %s", err.Error())
+	}
+
+	if !claims.VerifyIssuer(discovery.Issuer, true) {
+		return nil, fmt.Errorf("oidc id token issuer mismatch")
+	}
+
+	clientId := environment.Settings.OIDC.ClientId
+	audiences := claimStrings(claims["aud"])
+	if !containsString(audiences, clientId) {
+		return nil, fmt.Errorf("oidc id token audience mismatch")
+	}
+
+	if len(audiences) > 1 {
+		if azp, _ := claims["azp"].(string); azp != clientId {
+			return nil, fmt.Errorf("oidc id token authorized party mismatch")
+		}
+	}
+
+	now := time.Now()
+	if !claims.VerifyExpiresAt(now.Add(-FormOIDCLeeway).Unix(), true) {
+		return nil, fmt.Errorf("oidc id token expired")
+	}
+
+	if !claims.VerifyNotBefore(now.Add(FormOIDCLeeway).Unix(), false) {
+		return nil, fmt.Errorf("oidc id token not valid yet")
+	}
+
+	if value, _ := claims["nonce"].(string); len(nonce) == 0 || value != nonce {
+		return nil, fmt.Errorf("oidc id token nonce mismatch")
+	}
+
+	return claims, nil
+}
+
+// getKey finds the signing key by id, refreshing the key set for unknown ids at most once a minute
+func (source *FormOIDCProvider) getKey(ctx context.Context, discovery *formOIDCDiscovery, kid string) (interface{}, error) {
+	source.mutex.Lock()
+	defer source.mutex.Unlock()
+
+	key, found := source.findKey(kid)
+	if found {
+		return key, nil
+	}
+
+	if source.keys != nil && time.Since(source.fetched) < FormOIDCKeysRefresh {
+		return nil, fmt.Errorf("signing key not found: %s", kid)
+	}
+
+	set := struct {
+		Keys []formOIDCKey `json:"keys"`
+	}{}
+
+	err := source.getJSON(ctx, discovery.JwksURI, &set)
+	if err != nil {
+		return nil, fmt.Errorf("signing keys error: %s", err.Error())
+	}
+
+	keys := make(map[string]interface{})
+	for _, item := range set.Keys {
+		if item.Use == "enc" {
+			continue
+		}
+
+		public, err := item.GetPublicKey()
+		if err != nil {
+			continue
+		}
+
+		keys[item.Kid] = public
+	}
+
+	source.keys = keys
+	source.fetched = time.Now()
+
+	key, found = source.findKey(kid)
+	if !found {
+		return nil, fmt.Errorf("signing key not found: %s", kid)
+	}
+
+	return key, nil
+}
+
+// findKey looks up a cached key, tokens without key id match a single key set
+func (source *FormOIDCProvider) findKey(kid string) (interface{}, bool) {
+	if len(kid) == 0 && len(source.keys) == 1 {
+		for _, key := range source.keys {
+			return key, true
+		}
+	}
+
+	key, found := source.keys[kid]
+	return key, found
+}
+
+// GetPublicKey converts the json web key into a rsa or ecdsa public key
+func (source formOIDCKey) GetPublicKey() (interface{}, error) {
+	switch source.Kty {
+	case "RSA":
+		n, err := base64.RawURLEncoding.DecodeString(source.N)
+		if err != nil {
+			return nil, err
+		}
+
+		e, err := base64.RawURLEncoding.DecodeString(source.E)
+		if err != nil {
+			return nil, err
+		}
+
+		return &rsa.PublicKey{N: new(big.Int).SetBytes(n), E: int(new(big.Int).SetBytes(e).Int64())}, nil
+	case "EC":
+		var curve elliptic.Curve
+		switch source.Crv {
+		case "P-256":
+			curve = elliptic.P256()
+		case "P-384":
+			curve = elliptic.P384()
+		case "P-521":
+			curve = elliptic.P521()
+		default:
+			return nil, fmt.Errorf("unsupported curve: %s", source.Crv)
+		}
+
+		x, err := base64.RawURLEncoding.DecodeString(source.X)
+		if err != nil {
+			return nil, err
+		}
+
+		y, err := base64.RawURLEncoding.DecodeString(source.Y)
+		if err != nil {
+			return nil, err
+		}
+
+		return &ecdsa.PublicKey{Curve: curve, X: new(big.Int).SetBytes(x), Y: new(big.Int).SetBytes(y)}, nil
+	default:
+		return nil, errors.New("unsupported key type: " + source.Kty)
+	}
+}
+
+// NewOIDCSecret generates a random url safe value, used for state, nonce and PKCE verifier
+func NewOIDCSecret() (string, error) {
+	secret := make([]byte, 32)
+	if _, err := rand.Read(secret); err != nil {
+		return "", err
+	}
+	return base64.RawURLEncoding.EncodeToString(secret), nil
+}
+
+// claimStrings reads a claim that may be a single string or a list of strings
+func claimStrings(value interface{}) []string {
+	switch typed := value.(type) {
+	case string:
+		return strings.FieldsFunc(typed, func(r rune) bool { return r == ',' || r == ' ' })
+	case []interface{}:
+		result := []string{}
+		for _, item := range typed {
+			if text, ok := item.(string); ok {
+				result = append(result, text)
+			}
+		}
+		return result
+	default:
+		return nil
+	}
+}
+
+func containsString(items []string, value string) bool {
+	for _, item := range items {
+		if item == value {
+			return true
+		}
+	}
+	return false
+}
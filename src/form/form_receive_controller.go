@@ -27,8 +27,14 @@ func FormReceiveController(w http.ResponseWriter, r *http.Request) {
 
 	if server != nil {
 		data.Number = server.GetWId()
-		data.Token = server.Token
-		data.DownloadPrefix = GetDownloadPrefix(server.Token)
+
+		// downloads go through the api with the token, not for viewers
+		if user, _ := GetFormUser(r); user != nil && user.CanWrite() {
+			data.Token = server.Token
+			data.DownloadPrefix = GetDownloadPrefix(server.Token)
+		} else {
+			data.Token = server.GetMaskedToken()
+		}
 
 		// Evitando tentativa de download de anexos sem o bot estar devidamente sincronizado
 		status := server.GetStatus()
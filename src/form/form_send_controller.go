@@ -72,7 +72,9 @@ func controllerHttpPost(w http.ResponseWriter, r *http.Request) {
 	}
 
 	msg.Id = r.Form.Get("id")
+	user, _ := GetFormUser(r)
 	_, err = server.SendMessage(msg)
+	AuditForm(r, user, "send", server.Token, "chatid="+msg.Chat.Id, err)
 	if err != nil {
 		api.RespondServerError(server, w, err)
 		return
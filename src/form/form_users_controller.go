@@ -0,0 +1,128 @@
+package form
+
+import (
+	"fmt"
+	"html/template"
+	"net/http"
+	"strings"
+
+	"github.com/nbutton23/zxcvbn-go"
+	library "github.com/nocodeleaks/quepasa/library"
+	models "github.com/nocodeleaks/quepasa/models"
+	log "github.com/sirupsen/logrus"
+)
+
+func renderUsersForm(w http.ResponseWriter, data models.QPFormUsersData) {
+	users, err := models.UserManager.List()
+	if err != nil && len(data.ErrorMessage) == 0 {
+		data.ErrorMessage = err.Error()
+	}
+
+	data.PageTitle = "Users"
+	data.Users = users
+	data.Roles = models.UserRoles
+
+	templates := template.Must(template.ParseFiles(GetViewPath("layouts/main.tmpl"), GetViewPath("users.tmpl")))
+	templates.ExecuteTemplate(w, "main", data)
+}
+
+// FormUsersController renders route GET "/{prefix}/users", admins only
+func FormUsersController(w http.ResponseWriter, r *http.Request) {
+	user, err := GetFormUser(r)
+	if err != nil {
+		RedirectToLogin(w, r)
+		return
+	}
+
+	renderUsersForm(w, models.QPFormUsersData{User: *user})
+}
+
+// FormUsersActionController renders route POST "/{prefix}/users", admins only
+func FormUsersActionController(w http.ResponseWriter, r *http.Request) {
+	user, err := GetFormUser(r)
+	if err != nil {
+		RedirectToLogin(w, r)
+		return
+	}
+
+	data := models.QPFormUsersData{User: *user}
+
+	r.ParseForm()
+	action := r.Form.Get("action")
+	username := strings.TrimSpace(r.Form.Get("username"))
+	if len(username) == 0 {
+		data.ErrorMessage = "missing username"
+		renderUsersForm(w, data)
+		return
+	}
+
+	// avoids admins locking themselves out
+	if username == user.Username && action != "password" {
+		data.ErrorMessage = "you can not change your own role or status, ask another admin"
+		renderUsersForm(w, data)
+		return
+	}
+
+	switch action {
+	case "create":
+		password := r.Form.Get("password")
+		if !library.IsValidEMail(username) {
+			err = fmt.Errorf("email is invalid: %s", username)
+		} else if err = validateFormPassword(password); err == nil {
+			_, err = models.UserManager.Create(username, password, r.Form.Get("role"))
+		}
+		data.SuccessMessage = "user created: " + username
+	case "role":
+		role := r.Form.Get("role")
+		_, err = models.UserManager.SetRole(username, role)
+		data.SuccessMessage = fmt.Sprintf("user (%s) role changed to: %s", username, role)
+	case "disable":
+		_, err = models.UserManager.SetDisabled(username, true)
+		data.SuccessMessage = "user disabled: " + username
+	case "enable":
+		_, err = models.UserManager.SetDisabled(username, false)
+		data.SuccessMessage = "user enabled: " + username
+	case "unlock":
+		_, err = models.UserManager.Unlock(username)
+		data.SuccessMessage = "user unlocked: " + username
+	case "password":
+		password := r.Form.Get("password")
+		if err = validateFormPassword(password); err == nil {
+			err = models.UserManager.ResetPassword(username, password)
+		}
+		data.SuccessMessage = "password reset for user: " + username
+	case "delete":
+		err = models.UserManager.Delete(username)
+		data.SuccessMessage = "user deleted: " + username
+	default:
+		err = fmt.Errorf("invalid action: %s", action)
+	}
+
+	summary := "username=" + username
+	if action == "create" || action == "role" {
+		summary += "; role=" + r.Form.Get("role")
+	}
+	AuditForm(r, user, "users "+action, "", summary, err)
+
+	if err != nil {
+		data.SuccessMessage = ""
+		data.ErrorMessage = err.Error()
+	} else {
+		log.Infof("users: %s by admin (%s) for: %s", action, user.Username, username)
+	}
+
+	renderUsersForm(w, data)
+}
+
+func validateFormPassword(password string) error {
+	if len(password) == 0 {
+		return fmt.Errorf("missing password")
+	}
+
+	res := zxcvbn.PasswordStrength(password, nil)
+	if res.Score < 1 {
+		return fmt.Errorf("password is too weak, crack time: %s", res.CrackTimeDisplay)
+	}
+
+	return nil
+}
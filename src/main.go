@@ -6,6 +6,7 @@ import (
 	library "github.com/nocodeleaks/quepasa/library"
 	_ "github.com/nocodeleaks/quepasa/metrics"
 	models "github.com/nocodeleaks/quepasa/models"
+	telemetry "github.com/nocodeleaks/quepasa/telemetry"
 	webserver "github.com/nocodeleaks/quepasa/webserver"
 	whatsapp "github.com/nocodeleaks/quepasa/whatsapp"
 	whatsmeow "github.com/nocodeleaks/quepasa/whatsmeow"
@@ -36,12 +37,29 @@ func main() {
 	logentry.Level = loglevel
 	logentry.Infof("current log level: %v", logentry.Level)
 
+	// log level is reloadable from config file
+	environment.ConfigFile.OnReload(func(changed []string) {
+		level := environment.Settings.GetLogLevel(logrus.InfoLevel)
+		if level != logrus.GetLevel() {
+			logrus.SetLevel(level)
+			logentry.Level = level
+			logentry.Infof("log level changed to: %v", level)
+		}
+	})
+	environment.ConfigFile.Start()
+
 	// checks for pending database migrations
 	err := models.MigrateToLatest(logentry)
 	if err != nil {
 		logentry.Fatalf("database migration error: %s", err.Error())
 	}
 
+	// tracing, spans are exported only if enabled
+	err = telemetry.Start(logentry)
+	if err != nil {
+		logentry.Errorf("telemetry starting error: %s", err.Error())
+	}
+
 	// should became before whatsmeow start
 	title := environment.Settings.General.AppTitle
 	if len(title) > 0 {
@@ -86,6 +104,21 @@ func main() {
 	}
 
 	err = webserver.WebServerStart(logentry)
+
+	// stop watching sessions before they are stopped
+	models.Watchdog.Shutdown()
+
+	// releasing sessions owned by this node, if running on cluster mode
+	models.ClusterManager.Shutdown()
+
+	// flushing pending audit entries
+	models.AuditManager.Shutdown()
+
+	environment.ConfigFile.Shutdown()
+
+	// flushing pending spans
+	telemetry.Shutdown()
+
 	if err != nil {
 		logentry.Info("end with errors")
 	} else {
@@ -0,0 +1,115 @@
+package media
+
+import (
+	"bytes"
+	"fmt"
+	"image"
+	"image/color"
+	"image/jpeg"
+
+	// registering decoders for profile picture sources
+	_ "image/gif"
+	_ "image/png"
+)
+
+// ProfilePictureSize is the side, in pixels, of profile pictures sent to whatsapp
+const ProfilePictureSize = 640
+
+// ProfilePictureMaxLength is the maximum jpeg length accepted for profile pictures
+const ProfilePictureMaxLength = 500 * 1024
+
+// ProfilePictureSourceMaxLength is the maximum length of source images, before conversion
+const ProfilePictureSourceMaxLength = 10 << 20
+
+// ProfilePictureSourceMaxPixels is the maximum width x height of source images, checked before decoding
+const ProfilePictureSourceMaxPixels = 25_000_000
+
+// ConvertToProfilePicture decodes a jpeg, png or gif image, crops the center square
+// and resizes it to ProfilePictureSize (never upscales), encoding as jpeg.
+// Quality is reduced until the result fits on ProfilePictureMaxLength.
+func ConvertToProfilePicture(content []byte) ([]byte, error) {
+	if len(content) > ProfilePictureSourceMaxLength {
+		return nil, fmt.Errorf("image too large, maximum: %d bytes", ProfilePictureSourceMaxLength)
+	}
+
+	// small files may declare huge dimensions, checking before allocating
+	config, _, err := image.DecodeConfig(bytes.NewReader(content))
+	if err != nil {
+		return nil, fmt.Errorf("invalid image: %w", err)
+	}
+
+	if int64(config.Width)*int64(config.Height) > ProfilePictureSourceMaxPixels {
+		return nil, fmt.Errorf("image dimensions too large: %dx%d, maximum: %d pixels", config.Width, config.Height, ProfilePictureSourceMaxPixels)
+	}
+
+	source, _, err := image.Decode(bytes.NewReader(content))
+	if err != nil {
+		return nil, fmt.Errorf("invalid image: %w", err)
+	}
+
+	bounds := source.Bounds()
+	side := min(bounds.Dx(), bounds.Dy())
+	if side == 0 {
+		return nil, fmt.Errorf("invalid image: empty dimensions")
+	}
+
+	crop := image.Rect(0, 0, side, side).Add(image.Pt(
+		bounds.Min.X+(bounds.Dx()-side)/2,
+		bounds.Min.Y+(bounds.Dy()-side)/2,
+	))
+
+	resized := resizeSquare(source, crop, min(side, ProfilePictureSize))
+
+	for quality := 90; quality >= 40; quality -= 10 {
+		var buffer bytes.Buffer
+		err = jpeg.Encode(&buffer, resized, &jpeg.Options{Quality: quality})
+		if err != nil {
+			return nil, fmt.Errorf("error encoding profile picture: %w", err)
+		}
+
+		if buffer.Len() <= ProfilePictureMaxLength {
+			return buffer.Bytes(), nil
+		}
+	}
+
+	return nil, fmt.Errorf("profile picture too large even on lowest quality")
+}
+
+// resizeSquare scales the crop area into a size x size opaque image, averaging source pixels (box filter)
+func resizeSquare(source image.Image, crop image.Rectangle, size int) *image.RGBA {
+	result := image.NewRGBA(image.Rect(0, 0, size, size))
+	scale := float64(crop.Dx()) / float64(size)
+
+	for y := 0; y < size; y++ {
+		y0 := crop.Min.Y + int(float64(y)*scale)
+		y1 := max(crop.Min.Y+int(float64(y+1)*scale), y0+1)
+
+		for x := 0; x < size; x++ {
+			x0 := crop.Min.X + int(float64(x)*scale)
+			x1 := max(crop.Min.X+int(float64(x+1)*scale), x0+1)
+
+			var r, g, b, a, count uint64
+			for sy := y0; sy < y1; sy++ {
+				for sx := x0; sx < x1; sx++ {
+					pr, pg, pb, pa := source.At(sx, sy).RGBA()
+					r += uint64(pr)
+					g += uint64(pg)
+					b += uint64(pb)
+					a += uint64(pa)
+					count++
+				}
+			}
+
+			// colors are alpha premultiplied, transparent areas become white
+			background := 0xffff - a/count
+			result.SetRGBA64(x, y, color.RGBA64{
+				R: uint16(r/count + background),
+				G: uint16(g/count + background),
+				B: uint16(b/count + background),
+				A: 0xffff,
+			})
+		}
+	}
+
+	return result
+}
@@ -0,0 +1,77 @@
+package media
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"time"
+)
+
+// Supported speech-to-text backends
+const (
+	TranscriptionBackendExec = "exec" // local executable (whisper.cpp, vosk, custom scripts)
+	TranscriptionBackendHTTP = "http" // remote service (openai compatible, whisper.cpp server)
+)
+
+// ITranscriber converts speech audio content into text
+type ITranscriber interface {
+	// Backend name, used for logging and result identification
+	GetBackend() string
+
+	// Transcribe the audio content, mimetype is used to pick a file extension or content type
+	Transcribe(ctx context.Context, content []byte, mimetype string) (*TranscriptionResult, error)
+}
+
+// TranscriptionResult holds the text recognized by a transcriber
+type TranscriptionResult struct {
+	Text     string `json:"text"`
+	Language string `json:"language,omitempty"`
+}
+
+// TranscriptionOptions holds the configuration used to build a transcriber
+type TranscriptionOptions struct {
+	Backend  string        // exec | http
+	Command  string        // exec: command line, placeholders {file}, {wav} and {language}
+	Url      string        // http: endpoint that receives a multipart audio upload
+	Token    string        // http: optional bearer token
+	Model    string        // http: optional model name
+	Language string        // optional language hint (ISO 639-1)
+	Timeout  time.Duration // max duration for a single transcription
+}
+
+// NewTranscriber returns a transcriber for the configured backend
+func NewTranscriber(options TranscriptionOptions) (ITranscriber, error) {
+	switch strings.ToLower(options.Backend) {
+	case TranscriptionBackendExec:
+		if len(strings.TrimSpace(options.Command)) == 0 {
+			return nil, fmt.Errorf("transcription command not configured for exec backend")
+		}
+		return &ExecTranscriber{Options: options}, nil
+	case TranscriptionBackendHTTP:
+		if len(strings.TrimSpace(options.Url)) == 0 {
+			return nil, fmt.Errorf("transcription url not configured for http backend")
+		}
+		return &HttpTranscriber{Options: options}, nil
+	default:
+		return nil, fmt.Errorf("unknown transcription backend: %s", options.Backend)
+	}
+}
+
+// GetAudioExtensionFromMime returns a file extension (without dot) for common voice note mime types
+func GetAudioExtensionFromMime(mimetype string) string {
+	mimeOnly := strings.ToLower(strings.TrimSpace(strings.Split(mimetype, ";")[0]))
+	switch mimeOnly {
+	case "audio/ogg", "audio/opus":
+		return "ogg"
+	case "audio/mpeg", "audio/mp3":
+		return "mp3"
+	case "audio/mp4", "audio/m4a", "audio/aac", "audio/x-m4a":
+		return "m4a"
+	case "audio/wav", "audio/x-wav", "audio/wave":
+		return "wav"
+	case "audio/webm":
+		return "webm"
+	default:
+		return "ogg"
+	}
+}
@@ -0,0 +1,123 @@
+package media
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"os"
+	"os/exec"
+	"strings"
+)
+
+// ExecTranscriber runs a local speech-to-text executable for each audio
+//
+// The command line accepts the placeholders:
+//   - {file}     path of the original audio file
+//   - {wav}      path of a 16kHz mono wave copy (requires ffmpeg), as whisper.cpp and vosk expect
+//   - {language} configured language hint
+//
+// Example: whisper-cli -m /models/ggml-base.bin -l {language} -nt -np -f {wav}
+//
+// Recognized text is read from the process standard output.
+type ExecTranscriber struct {
+	Options TranscriptionOptions
+}
+
+func (source *ExecTranscriber) GetBackend() string {
+	return TranscriptionBackendExec
+}
+
+func (source *ExecTranscriber) Transcribe(ctx context.Context, content []byte, mimetype string) (*TranscriptionResult, error) {
+	if len(content) == 0 {
+		return nil, fmt.Errorf("empty audio content")
+	}
+
+	if source.Options.Timeout > 0 {
+		var cancel context.CancelFunc
+		ctx, cancel = context.WithTimeout(ctx, source.Options.Timeout)
+		defer cancel()
+	}
+
+	extension := GetAudioExtensionFromMime(mimetype)
+	inputFile, err := os.CreateTemp("", fmt.Sprintf("transcription-*.%s", extension))
+	if err != nil {
+		return nil, fmt.Errorf("error creating temporary audio file for transcription: %w", err)
+	}
+	defer os.Remove(inputFile.Name())
+
+	if _, err := inputFile.Write(content); err != nil {
+		inputFile.Close()
+		return nil, fmt.Errorf("error writing temporary audio file for transcription: %w", err)
+	}
+	if err := inputFile.Close(); err != nil {
+		return nil, fmt.Errorf("error closing temporary audio file for transcription: %w", err)
+	}
+
+	args := strings.Fields(source.Options.Command)
+	if strings.Contains(source.Options.Command, "{wav}") {
+		wavFile, err := transcodeToSpeechWAV(ctx, inputFile.Name())
+		if err != nil {
+			return nil, err
+		}
+		defer os.Remove(wavFile)
+
+		for i := range args {
+			args[i] = strings.ReplaceAll(args[i], "{wav}", wavFile)
+		}
+	}
+
+	for i := range args {
+		args[i] = strings.ReplaceAll(args[i], "{file}", inputFile.Name())
+		args[i] = strings.ReplaceAll(args[i], "{language}", source.Options.Language)
+	}
+
+	cmd := exec.CommandContext(ctx, args[0], args[1:]...)
+
+	var stdout bytes.Buffer
+	var stderr bytes.Buffer
+	cmd.Stdout = &stdout
+	cmd.Stderr = &stderr
+
+	logentry.Debugf("executing transcription command: %s", cmd.String())
+	if err := cmd.Run(); err != nil {
+		return nil, fmt.Errorf("error executing transcription command: %w\nstderr: %s", err, stderr.String())
+	}
+
+	result := &TranscriptionResult{
+		Text:     strings.TrimSpace(stdout.String()),
+		Language: source.Options.Language,
+	}
+	return result, nil
+}
+
+// transcodeToSpeechWAV converts an audio file to 16kHz mono PCM wave, returning the new file path
+func transcodeToSpeechWAV(ctx context.Context, input string) (string, error) {
+	if !IsFFMpegAvailable() {
+		return "", fmt.Errorf("ffmpeg is not available: %w", GetInitError())
+	}
+
+	outputFile, err := os.CreateTemp("", "transcription-*.wav")
+	if err != nil {
+		return "", fmt.Errorf("error creating temporary wave file for transcription: %w", err)
+	}
+	outputFile.Close()
+
+	cmd := exec.CommandContext(ctx, "ffmpeg",
+		"-i", input,
+		"-ar", "16000",
+		"-ac", "1",
+		"-c:a", "pcm_s16le",
+		"-y", // Overwrite output file without asking
+		outputFile.Name(),
+	)
+
+	var stderr bytes.Buffer
+	cmd.Stderr = &stderr
+
+	if err := cmd.Run(); err != nil {
+		os.Remove(outputFile.Name())
+		return "", fmt.Errorf("error transcoding audio for transcription: %w\nstderr: %s", err, stderr.String())
+	}
+
+	return outputFile.Name(), nil
+}
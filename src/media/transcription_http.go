@@ -0,0 +1,102 @@
+package media
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"mime/multipart"
+	"net/http"
+	"net/textproto"
+	"strings"
+)
+
+// HttpTranscriber posts the audio as multipart/form-data to a remote service
+//
+// Compatible with OpenAI style "/v1/audio/transcriptions" endpoints and the whisper.cpp server "/inference".
+// The audio goes on the "file" field, optional "model" and "language" fields are sent when configured.
+// Response may be a json object with a "text" field or plain text.
+type HttpTranscriber struct {
+	Options TranscriptionOptions
+}
+
+func (source *HttpTranscriber) GetBackend() string {
+	return TranscriptionBackendHTTP
+}
+
+func (source *HttpTranscriber) Transcribe(ctx context.Context, content []byte, mimetype string) (*TranscriptionResult, error) {
+	if len(content) == 0 {
+		return nil, fmt.Errorf("empty audio content")
+	}
+
+	if source.Options.Timeout > 0 {
+		var cancel context.CancelFunc
+		ctx, cancel = context.WithTimeout(ctx, source.Options.Timeout)
+		defer cancel()
+	}
+
+	body := &bytes.Buffer{}
+	writer := multipart.NewWriter(body)
+
+	header := make(textproto.MIMEHeader)
+	filename := "audio." + GetAudioExtensionFromMime(mimetype)
+	header.Set("Content-Disposition", fmt.Sprintf(`form-data; name="file"; filename="%s"`, filename))
+	if len(mimetype) > 0 {
+		header.Set("Content-Type", strings.TrimSpace(strings.Split(mimetype, ";")[0]))
+	}
+
+	part, err := writer.CreatePart(header)
+	if err != nil {
+		return nil, fmt.Errorf("error creating transcription request body: %w", err)
+	}
+	if _, err := part.Write(content); err != nil {
+		return nil, fmt.Errorf("error writing transcription request body: %w", err)
+	}
+
+	if len(source.Options.Model) > 0 {
+		writer.WriteField("model", source.Options.Model)
+	}
+	if len(source.Options.Language) > 0 {
+		writer.WriteField("language", source.Options.Language)
+	}
+	writer.WriteField("response_format", "json")
+
+	if err := writer.Close(); err != nil {
+		return nil, fmt.Errorf("error closing transcription request body: %w", err)
+	}
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, source.Options.Url, body)
+	if err != nil {
+		return nil, fmt.Errorf("error creating transcription request: %w", err)
+	}
+	req.Header.Set("Content-Type", writer.FormDataContentType())
+	req.Header.Set("User-Agent", "Quepasa")
+	if len(source.Options.Token) > 0 {
+		req.Header.Set("Authorization", "Bearer "+source.Options.Token)
+	}
+
+	resp, err := http.DefaultClient.Do(req)
+	if err != nil {
+		return nil, fmt.Errorf("error posting audio for transcription: %w", err)
+	}
+	defer resp.Body.Close()
+
+	response, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return nil, fmt.Errorf("error reading transcription response: %w", err)
+	}
+
+	if resp.StatusCode < 200 || resp.StatusCode > 299 {
+		return nil, fmt.Errorf("transcription service returned status %d: %s", resp.StatusCode, string(response))
+	}
+
+	result := &TranscriptionResult{Language: source.Options.Language}
+	if err := json.Unmarshal(response, result); err != nil {
+		// not a json response, assuming plain text
+		result.Text = string(response)
+	}
+
+	result.Text = strings.TrimSpace(result.Text)
+	return result, nil
+}
@@ -1,6 +1,9 @@
 package metrics
 
 import (
+	"net/http"
+	"sync"
+
 	"github.com/go-chi/chi/v5"
 	environment "github.com/nocodeleaks/quepasa/environment"
 	webserver "github.com/nocodeleaks/quepasa/webserver"
@@ -32,10 +35,39 @@ func Configure(r chi.Router) {
 	}
 }
 
+var (
+	collectHooks     []func()
+	collectHooksLock sync.Mutex
+)
+
+// RegisterCollectHook registers a function executed before each scrape,
+// used to refresh gauges that depend on current state (ex: per server status)
+func RegisterCollectHook(hook func()) {
+	collectHooksLock.Lock()
+	defer collectHooksLock.Unlock()
+	collectHooks = append(collectHooks, hook)
+}
+
+// runCollectHooks executes registered hooks, without locking
+func runCollectHooks() {
+	for _, hook := range collectHooks {
+		hook()
+	}
+}
+
 // ServeMetrics serves the Prometheus metrics endpoint
 func ServeMetrics(r chi.Router) {
 	prefix := environment.Settings.Metrics.Prefix
-	r.Handle("/"+prefix, promhttp.Handler())
+	handler := promhttp.Handler()
+	r.Handle("/"+prefix, http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+
+		// one scrape at a time, hooks reset gauges that would be half filled for concurrent scrapes
+		collectHooksLock.Lock()
+		defer collectHooksLock.Unlock()
+
+		runCollectHooks()
+		handler.ServeHTTP(w, r)
+	}))
 }
 
 func ServeDashboard(r chi.Router) {
@@ -1,6 +1,9 @@
 package metrics
 
 import (
+	"fmt"
+	"sync"
+
 	"github.com/prometheus/client_golang/prometheus"
 	"github.com/prometheus/client_golang/prometheus/promauto"
 )
@@ -17,7 +20,10 @@ type CounterRecorder interface {
 
 // GaugeRecorder for gauge metrics
 type GaugeRecorder interface {
+	Set(float64)
 	Add(float64)
+	Inc()
+	Dec()
 }
 
 // HistogramRecorder for histogram metrics
@@ -35,6 +41,12 @@ type CounterVecRecorder interface {
 	WithLabelValues(...string) CounterRecorder
 }
 
+// GaugeVecRecorder for gauge vector metrics, Reset removes all label sets (ex: servers removed)
+type GaugeVecRecorder interface {
+	WithLabelValues(...string) GaugeRecorder
+	Reset()
+}
+
 // HistogramVecRecorder for histogram vector metrics
 type HistogramVecRecorder interface {
 	WithLabelValues(...string) HistogramRecorder
@@ -280,3 +292,97 @@ func CreateCounterRecorder(name, help string) CounterRecorder {
 	}
 	return &NoOpCounterRecorder{}
 }
+
+// NoOpGaugeRecorder provides no-op implementation for gauges
+type NoOpGaugeRecorder struct{}
+
+func (n *NoOpGaugeRecorder) Set(float64) {}
+func (n *NoOpGaugeRecorder) Add(float64) {}
+func (n *NoOpGaugeRecorder) Inc()        {}
+func (n *NoOpGaugeRecorder) Dec()        {}
+
+// CreateGaugeRecorder creates a new gauge recorder
+// Gauges are updated synchronously, async updates could be applied out of order
+func CreateGaugeRecorder(name, help string) GaugeRecorder {
+	if MetricsEnabled {
+		return promauto.NewGauge(prometheus.GaugeOpts{
+			Name: name,
+			Help: help,
+		})
+	}
+	return &NoOpGaugeRecorder{}
+}
+
+// NoOpGaugeVecRecorder provides no-op implementation for gauge vectors
+type NoOpGaugeVecRecorder struct{}
+
+func (n *NoOpGaugeVecRecorder) WithLabelValues(labels ...string) GaugeRecorder {
+	return &NoOpGaugeRecorder{}
+}
+
+func (n *NoOpGaugeVecRecorder) Reset() {}
+
+// PrometheusGaugeVecRecorder wraps prometheus GaugeVec, with an optional cardinality guard
+type PrometheusGaugeVecRecorder struct {
+	vec     *prometheus.GaugeVec
+	limit   int                 // maximum label sets, 0 = unlimited
+	series  map[string]struct{} // label sets in use, tracked only when limited
+	dropped GaugeRecorder       // label sets refused by the guard since last reset
+	mutex   sync.Mutex
+}
+
+func (p *PrometheusGaugeVecRecorder) WithLabelValues(labels ...string) GaugeRecorder {
+	if p.limit > 0 {
+		key := fmt.Sprint(labels)
+
+		p.mutex.Lock()
+		_, exists := p.series[key]
+		if !exists {
+			if len(p.series) >= p.limit {
+				p.mutex.Unlock()
+				p.dropped.Inc()
+				return &NoOpGaugeRecorder{}
+			}
+			p.series[key] = struct{}{}
+		}
+		p.mutex.Unlock()
+	}
+
+	return p.vec.WithLabelValues(labels...)
+}
+
+func (p *PrometheusGaugeVecRecorder) Reset() {
+	p.mutex.Lock()
+	defer p.mutex.Unlock()
+
+	p.vec.Reset()
+	if p.limit > 0 {
+		p.series = map[string]struct{}{}
+		p.dropped.Set(0)
+	}
+}
+
+// CreateGaugeVecRecorder creates a new gauge vector recorder
+// This is a generic factory function for modules to create their own gauge vectors
+func CreateGaugeVecRecorder(name, help string, labelNames []string) GaugeVecRecorder {
+	return CreateLimitedGaugeVecRecorder(name, help, labelNames, 0)
+}
+
+// CreateLimitedGaugeVecRecorder creates a new gauge vector recorder with a cardinality guard,
+// label sets over limit are ignored and counted on <name>_dropped gauge until the next reset
+func CreateLimitedGaugeVecRecorder(name, help string, labelNames []string, limit int) GaugeVecRecorder {
+	if MetricsEnabled {
+		vec := promauto.NewGaugeVec(prometheus.GaugeOpts{
+			Name: name,
+			Help: help,
+		}, labelNames)
+
+		recorder := &PrometheusGaugeVecRecorder{vec: vec, limit: limit}
+		if limit > 0 {
+			recorder.series = map[string]struct{}{}
+			recorder.dropped = CreateGaugeRecorder(name+"_dropped", "Label sets ignored by cardinality guard of "+name)
+		}
+		return recorder
+	}
+	return &NoOpGaugeVecRecorder{}
+}
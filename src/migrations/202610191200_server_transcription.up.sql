@@ -0,0 +1,2 @@
+-- Per server option for speech-to-text of incoming voice notes
+ALTER TABLE `servers` ADD COLUMN `transcription` INT(1) NOT NULL DEFAULT 0;
@@ -0,0 +1,6 @@
+-- Sticky recipient to sender routes, used by master key sender pool
+CREATE TABLE IF NOT EXISTS `sender_routes` (
+  `chatid` VARCHAR (255) PRIMARY KEY UNIQUE NOT NULL,
+  `wid` VARCHAR (255) NOT NULL,
+  `timestamp` TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP
+);
@@ -0,0 +1,29 @@
+-- Bulk campaigns, message template sent to a list of recipients through a server
+CREATE TABLE IF NOT EXISTS `campaigns` (
+  `id` CHAR (100) PRIMARY KEY UNIQUE NOT NULL,
+  `context` CHAR (100) NOT NULL REFERENCES `servers`(`token`),
+  `name` VARCHAR (255) NOT NULL DEFAULT '',
+  `template` TEXT NOT NULL DEFAULT '',
+  `url` VARCHAR (1024) NOT NULL DEFAULT '',
+  `content` TEXT NOT NULL DEFAULT '',
+  `filename` VARCHAR (255) NOT NULL DEFAULT '',
+  `delay` INT NOT NULL DEFAULT 3000,
+  `status` VARCHAR (50) NOT NULL DEFAULT 'draft',
+  `error` TEXT NOT NULL DEFAULT '',
+  `timestamp` TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP,
+  `updated` TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP
+);
+
+-- Per recipient variables and delivery status
+CREATE TABLE IF NOT EXISTS `campaign_recipients` (
+  `campaign` CHAR (100) NOT NULL REFERENCES `campaigns`(`id`),
+  `chatid` VARCHAR (255) NOT NULL,
+  `variables` BLOB DEFAULT NULL,
+  `status` VARCHAR (50) NOT NULL DEFAULT 'queued',
+  `messageid` VARCHAR (255) NOT NULL DEFAULT '',
+  `error` TEXT NOT NULL DEFAULT '',
+  `timestamp` TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP,
+  PRIMARY KEY (`campaign`, `chatid`)
+);
+
+CREATE INDEX IF NOT EXISTS `campaign_recipients_messageid` ON `campaign_recipients` (`messageid`);
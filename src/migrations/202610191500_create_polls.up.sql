@@ -0,0 +1,22 @@
+-- Polls sent or received, options are needed to resolve votes (sha256 of option names)
+-- Poll secrets used to decrypt votes are kept by whatsmeow store (message secrets)
+CREATE TABLE IF NOT EXISTS `polls` (
+  `context` CHAR (100) NOT NULL REFERENCES `servers`(`token`),
+  `id` VARCHAR (255) NOT NULL,
+  `chatid` VARCHAR (255) NOT NULL,
+  `question` TEXT NOT NULL DEFAULT '',
+  `options` BLOB DEFAULT NULL,
+  `selections` INT NOT NULL DEFAULT 1,
+  `timestamp` TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP,
+  PRIMARY KEY (`context`, `id`)
+);
+
+-- Latest vote of each voter, a new vote replaces the previous one
+CREATE TABLE IF NOT EXISTS `poll_votes` (
+  `context` CHAR (100) NOT NULL REFERENCES `servers`(`token`),
+  `poll` VARCHAR (255) NOT NULL,
+  `voter` VARCHAR (255) NOT NULL,
+  `options` BLOB DEFAULT NULL,
+  `timestamp` TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP,
+  PRIMARY KEY (`context`, `poll`, `voter`)
+);
@@ -0,0 +1,14 @@
+-- Cluster nodes, alive while expires is in the future, url is used to forward requests
+CREATE TABLE IF NOT EXISTS `cluster_nodes` (
+  `id` VARCHAR (255) PRIMARY KEY NOT NULL,
+  `url` VARCHAR (1024) NOT NULL DEFAULT '',
+  `expires` TIMESTAMP NOT NULL
+);
+
+-- Session ownership leases, only the owner node starts the session
+-- Without reference to servers, leases of deleted servers are released by the owner
+CREATE TABLE IF NOT EXISTS `cluster_leases` (
+  `token` CHAR (100) PRIMARY KEY NOT NULL,
+  `node` VARCHAR (255) NOT NULL,
+  `expires` TIMESTAMP NOT NULL
+);
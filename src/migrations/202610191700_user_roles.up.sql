@@ -0,0 +1,10 @@
+-- User roles (admin, operator, viewer), disabled accounts and login lockout
+ALTER TABLE `users` ADD COLUMN `role` CHAR (20) NOT NULL DEFAULT 'operator';
+ALTER TABLE `users` ADD COLUMN `disabled` INT(1) NOT NULL DEFAULT 0;
+ALTER TABLE `users` ADD COLUMN `failures` INT NOT NULL DEFAULT 0;
+ALTER TABLE `users` ADD COLUMN `locked` TIMESTAMP DEFAULT NULL;
+
+-- The oldest real account becomes admin, the seeded default account never logs in
+UPDATE `users` SET `role` = 'admin' WHERE `username` = (
+  SELECT `username` FROM `users` WHERE `username` <> 'default@quepasa.io' ORDER BY `timestamp` LIMIT 1
+);
@@ -0,0 +1,16 @@
+-- Audit log of administrative and sending actions, actortype is master, token, user or system
+CREATE TABLE IF NOT EXISTS `audit` (
+  `id` INTEGER PRIMARY KEY AUTOINCREMENT,
+  `timestamp` TIMESTAMP NOT NULL,
+  `actortype` CHAR (20) NOT NULL,
+  `actor` VARCHAR (255) NOT NULL DEFAULT '',
+  `action` VARCHAR (255) NOT NULL,
+  `token` CHAR (100) NOT NULL DEFAULT '',
+  `summary` TEXT NOT NULL DEFAULT '',
+  `success` INT(1) NOT NULL DEFAULT 0,
+  `result` TEXT NOT NULL DEFAULT '',
+  `source` VARCHAR (255) NOT NULL DEFAULT ''
+);
+
+CREATE INDEX IF NOT EXISTS `audit_timestamp` ON `audit` (`timestamp`);
+CREATE INDEX IF NOT EXISTS `audit_token` ON `audit` (`token`, `timestamp`);
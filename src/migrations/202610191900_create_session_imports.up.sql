@@ -0,0 +1,6 @@
+-- Handoff archives already imported, an archive is imported only once
+CREATE TABLE IF NOT EXISTS `session_imports` (
+  `id` CHAR (100) PRIMARY KEY NOT NULL,
+  `token` CHAR (100) NOT NULL,
+  `timestamp` TIMESTAMP NOT NULL
+);
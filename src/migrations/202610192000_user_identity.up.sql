@@ -0,0 +1,4 @@
+-- Identity provider link of users created for single sign-on, empty for local accounts
+-- Only users with the same issuer and subject log in through the provider, subject is set on the first login
+ALTER TABLE `users` ADD COLUMN `issuer` VARCHAR (255) NOT NULL DEFAULT '';
+ALTER TABLE `users` ADD COLUMN `subject` VARCHAR (255) NOT NULL DEFAULT '';
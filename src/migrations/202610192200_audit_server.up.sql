@@ -0,0 +1,4 @@
+-- Keyed hash of the target server token, entries are filtered by it, token keeps only the mask for display
+ALTER TABLE `audit` ADD COLUMN `server` CHAR (64) NOT NULL DEFAULT '';
+
+CREATE INDEX IF NOT EXISTS `audit_server` ON `audit` (`server`, `timestamp`);
@@ -1,6 +1,7 @@
 package models
 
 import (
+	environment "github.com/nocodeleaks/quepasa/environment"
 	metrics "github.com/nocodeleaks/quepasa/metrics"
 )
 
@@ -10,10 +11,29 @@ var (
 	WebhookSendErrors         = metrics.CreateCounterRecorder("quepasa_webhook_send_errors_total", "Total webhook send errors")
 	MessageProcessingDuration = metrics.CreateHistogramVecRecorder("quepasa_message_processing_duration_seconds", "Time spent processing different types of messages", []float64{0.001, 0.005, 0.01, 0.025, 0.05, 0.1, 0.25, 0.5, 1.0, 2.5, 5.0}, []string{"message_type", "source", "processing_stage"})
 	MessageProcessingErrors   = metrics.CreateCounterVecRecorder("quepasa_message_processing_errors_total", "Total message processing errors by type and stage", []string{"message_type", "source", "processing_stage", "error_type"})
-	MessageQueueDepth         = metrics.CreateCounterVecRecorder("quepasa_message_queue_depth", "Current depth of internal message processing queues", []string{"queue_type", "priority"}) // Using counter for now, gauge not available
+	MessageQueueDepth         = metrics.CreateGaugeVecRecorder("quepasa_message_queue_depth", "Current depth of internal message processing queues", []string{"queue_type", "priority"})
 	MessageRetries            = metrics.CreateCounterVecRecorder("quepasa_message_retries_total", "Total message processing retries", []string{"message_type", "retry_reason", "source"})
 	WebhookLatency            = metrics.CreateHistogramVecRecorder("quepasa_webhook_duration_seconds", "Webhook request duration in seconds", []float64{.005, .01, .025, .05, .1, .25, .5, 1, 2.5, 5, 10}, []string{})
 	WebhookTimeouts           = metrics.CreateCounterRecorder("quepasa_webhook_timeouts_total", "Total webhook timeout errors")
 	WebhookHTTPErrors         = metrics.CreateCounterVecRecorder("quepasa_webhook_http_errors_total", "Total webhook HTTP errors by status code", []string{"status_code"})
 	WebhookSuccess            = metrics.CreateCounterRecorder("quepasa_webhook_success_total", "Total successful webhooks (HTTP 200)")
+	WatchdogEvents            = metrics.CreateCounterVecRecorder("quepasa_watchdog_events_total", "Total session state changes detected by watchdog", []string{"event"})
+	WatchdogRestarts          = metrics.CreateCounterVecRecorder("quepasa_watchdog_restarts_total", "Total session restarts made by watchdog", []string{"event"})
+	AuditEntries              = metrics.CreateCounterVecRecorder("quepasa_audit_entries_total", "Total audit entries recorded", []string{"actortype", "success"})
+	AuditDropped              = metrics.CreateCounterRecorder("quepasa_audit_dropped_total", "Total audit entries dropped, queue full or database errors")
+)
+
+// maximum servers with labeled metrics, avoids unbounded series on large deployments
+var serverMetricsLimit = int(environment.Settings.Metrics.MaxServers)
+
+// Per server gauges, labeled by wid, refreshed on each scrape
+var (
+	ServersDisconnected       = metrics.CreateGaugeRecorder("quepasa_servers_disconnected", "Verified servers not ready, for alerting")
+	SignalRConnections        = metrics.CreateGaugeRecorder("quepasa_signalr_connections", "Current SignalR connections with a registered token")
+	ServerReady               = metrics.CreateLimitedGaugeVecRecorder("quepasa_server_ready", "Server ready (1) or not (0)", []string{"wid"}, serverMetricsLimit)
+	ServerConnectionState     = metrics.CreateLimitedGaugeVecRecorder("quepasa_server_connection_state", "Current connection state of server, 1 on the active state label", []string{"wid", "state"}, serverMetricsLimit)
+	ServerLastMessageAge      = metrics.CreateLimitedGaugeVecRecorder("quepasa_server_last_message_age_seconds", "Seconds since the last received message", []string{"wid"}, serverMetricsLimit)
+	ServerCacheSize           = metrics.CreateLimitedGaugeVecRecorder("quepasa_server_cache_messages", "Messages on server cache", []string{"wid"}, serverMetricsLimit)
+	ServerDispatchingFailures = metrics.CreateLimitedGaugeVecRecorder("quepasa_server_dispatching_failing", "Dispatchings (webhooks, rabbitmq) currently on failure state", []string{"wid"}, serverMetricsLimit)
+	ServerSignalRConnections  = metrics.CreateLimitedGaugeVecRecorder("quepasa_server_signalr_connections", "SignalR connections receiving server events", []string{"wid"}, serverMetricsLimit)
 )
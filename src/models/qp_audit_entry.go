@@ -0,0 +1,30 @@
+package models
+
+import "time"
+
+// Audit actor types
+const (
+	AuditActorMaster = "master" // master key
+	AuditActorToken  = "token"  // server token, actor holds it masked
+	AuditActorUser   = "user"   // web interface user
+	AuditActorSystem = "system" // internal, ex: watchdog
+
+	AuditActorAnonymous = "anonymous" // no credentials, ex: refused requests
+)
+
+// QpAuditEntry records who did what on which server, and the result
+type QpAuditEntry struct {
+	Id        int64     `db:"id" json:"id"`
+	Timestamp time.Time `db:"timestamp" json:"timestamp"`
+	ActorType string    `db:"actortype" json:"actortype"`
+	Actor     string    `db:"actor" json:"actor,omitempty"`
+	Action    string    `db:"action" json:"action"`
+	Token     string    `db:"token" json:"token,omitempty"` // target server, masked when recorded
+	Server    string    `db:"server" json:"-"`              // keyed hash of the target server token, for filtering
+	Summary   string    `db:"summary" json:"summary,omitempty"`
+	Success   bool      `db:"success" json:"success"`
+	Result    string    `db:"result" json:"result,omitempty"`
+	Source    string    `db:"source" json:"source,omitempty"` // remote address
+
+	target string // full target server token, for dispatch, never stored
+}
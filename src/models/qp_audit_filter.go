@@ -0,0 +1,17 @@
+package models
+
+import "time"
+
+// QpAuditFilter selects audit entries, empty fields are not filtered
+type QpAuditFilter struct {
+	ActorType string     `json:"actortype,omitempty"`
+	Actor     string     `json:"actor,omitempty"`
+	Action    string     `json:"action,omitempty"` // contains
+	Token     string     `json:"token,omitempty"`  // full token, matched by its hash
+	Server    string     `json:"-"`                // hash of the token, set on find
+	Success   *bool      `json:"success,omitempty"`
+	From      *time.Time `json:"from,omitempty"`
+	To        *time.Time `json:"to,omitempty"`
+	Limit     uint       `json:"limit,omitempty"`
+	Offset    uint       `json:"offset,omitempty"`
+}
@@ -0,0 +1,257 @@
+package models
+
+import (
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"sync"
+	"time"
+
+	"github.com/google/uuid"
+	environment "github.com/nocodeleaks/quepasa/environment"
+	library "github.com/nocodeleaks/quepasa/library"
+	whatsapp "github.com/nocodeleaks/quepasa/whatsapp"
+	log "github.com/sirupsen/logrus"
+)
+
+// pending entries before new ones are dropped, keeps requests from waiting on database
+const AuditQueueSize = 1024
+
+// default and maximum entries returned by a query
+const AuditQueryLimit = 100
+const AuditQueryMaxLimit = 1000
+
+// interval between purges of entries older than retention
+const AuditPurgeInterval = time.Hour
+
+// QpAuditManager records audit entries asynchronously, purges old ones and optionally dispatches them, thread safe
+type QpAuditManager struct {
+	mutex sync.Mutex
+	queue chan *QpAuditEntry
+	stop  chan struct{}
+	done  chan struct{}
+}
+
+var AuditManager = &QpAuditManager{}
+
+func (source *QpAuditManager) getDB() (QpDataAuditInterface, error) {
+	if WhatsappService == nil || WhatsappService.DB == nil || WhatsappService.DB.Audit == nil {
+		return nil, fmt.Errorf("audit database not ready")
+	}
+	return WhatsappService.DB.Audit, nil
+}
+
+// Enabled returns true if actions should be recorded
+func (source *QpAuditManager) Enabled() bool {
+	return environment.Settings.Audit.Enabled
+}
+
+// Initialize starts the writer and the periodic purge
+func (source *QpAuditManager) Initialize() {
+	if !source.Enabled() {
+		return
+	}
+
+	settings := environment.Settings.Audit
+	log.Infof("audit enabled, retention: %v days, dispatch: %v", settings.Retention, settings.Dispatch)
+
+	source.mutex.Lock()
+	defer source.mutex.Unlock()
+
+	if source.stop == nil {
+		source.queue = make(chan *QpAuditEntry, AuditQueueSize)
+		source.stop = make(chan struct{})
+		source.done = make(chan struct{})
+		go source.run(source.queue, source.stop, source.done)
+	}
+}
+
+// GetAuditServerHash returns the hmac sha256 of the token, keyed by the signing secret
+func GetAuditServerHash(token string) string {
+	hash := hmac.New(sha256.New, []byte(environment.Settings.API.SigningSecret))
+	hash.Write([]byte(token))
+	return hex.EncodeToString(hash.Sum(nil))
+}
+
+// HashTokens hashes and masks target tokens of entries recorded in full, before hashing
+func (source *QpAuditManager) HashTokens() {
+	db, err := source.getDB()
+	if err != nil {
+		return
+	}
+
+	count, err := db.HashTokens(GetAuditServerHash)
+	if err != nil {
+		log.Errorf("audit, error on hashing tokens: %s", err.Error())
+		return
+	}
+
+	if count > 0 {
+		log.Infof("audit, hashed tokens of %d entries", count)
+	}
+}
+
+func (source *QpAuditManager) run(queue chan *QpAuditEntry, stop chan struct{}, done chan struct{}) {
+	defer close(done)
+
+	source.HashTokens()
+	ticker := time.NewTicker(AuditPurgeInterval)
+	defer ticker.Stop()
+
+	source.Purge()
+	for {
+		select {
+		case <-stop:
+			// flushing pending entries
+			for {
+				select {
+				case entry := <-queue:
+					source.save(entry)
+				default:
+					return
+				}
+			}
+		case entry := <-queue:
+			source.save(entry)
+		case <-ticker.C:
+			source.Purge()
+		}
+	}
+}
+
+// Shutdown stops the writer after flushing pending entries
+func (source *QpAuditManager) Shutdown() {
+	source.mutex.Lock()
+	stop, done := source.stop, source.done
+	source.stop = nil
+	source.mutex.Unlock()
+
+	if stop != nil {
+		close(stop)
+		<-done
+	}
+}
+
+// Record queues an entry, never blocks, entries are dropped if the queue is full
+func (source *QpAuditManager) Record(entry *QpAuditEntry) {
+	if entry == nil || !source.Enabled() {
+		return
+	}
+
+	if entry.Timestamp.IsZero() {
+		entry.Timestamp = time.Now().UTC()
+	}
+
+	// tokens are credentials, stored hashed for filtering and masked for display
+	if len(entry.Token) > 0 && len(entry.target) == 0 {
+		entry.target = entry.Token
+		entry.Server = GetAuditServerHash(entry.Token)
+		entry.Token = library.MaskToken(entry.Token)
+	}
+
+	source.mutex.Lock()
+	queue := source.queue
+	running := source.stop != nil
+	source.mutex.Unlock()
+
+	if !running {
+		go source.save(entry)
+		return
+	}
+
+	select {
+	case queue <- entry:
+	default:
+		AuditDropped.Inc()
+		log.Warnf("audit queue full, entry dropped: %s %s", entry.ActorType, entry.Action)
+	}
+}
+
+func (source *QpAuditManager) save(entry *QpAuditEntry) {
+	db, err := source.getDB()
+	if err != nil {
+		log.Errorf("audit, entry not saved: %s", err.Error())
+		return
+	}
+
+	err = db.Add(entry)
+	if err != nil {
+		AuditDropped.Inc()
+		log.Errorf("audit, error on saving entry: %s", err.Error())
+		return
+	}
+
+	AuditEntries.WithLabelValues(entry.ActorType, fmt.Sprint(entry.Success)).Inc()
+
+	if environment.Settings.Audit.Dispatch {
+		source.dispatch(entry)
+	}
+}
+
+// dispatch streams the entry to the dispatching pipeline of its server, as a system message
+func (source *QpAuditManager) dispatch(entry *QpAuditEntry) {
+	if len(entry.target) == 0 || WhatsappService == nil {
+		return
+	}
+
+	server, err := WhatsappService.FindByToken(entry.target)
+	if err != nil || server.Handler == nil {
+		return
+	}
+
+	message := &whatsapp.WhatsappMessage{
+		Id:        uuid.New().String(),
+		Timestamp: entry.Timestamp,
+		Type:      whatsapp.SystemMessageType,
+		Chat:      whatsapp.WASYSTEMCHAT,
+		Text:      "audit",
+		Info:      entry,
+	}
+
+	go server.Handler.Trigger(message, "audit")
+}
+
+// Find returns entries matching the filter, newest first, applying default and maximum limits
+func (source *QpAuditManager) Find(filter *QpAuditFilter) ([]*QpAuditEntry, error) {
+	db, err := source.getDB()
+	if err != nil {
+		return nil, err
+	}
+
+	// entries are matched by the hash of the full token, masks may collide
+	if len(filter.Token) > 0 {
+		filter.Server = GetAuditServerHash(filter.Token)
+	}
+
+	if filter.Limit == 0 {
+		filter.Limit = AuditQueryLimit
+	} else if filter.Limit > AuditQueryMaxLimit {
+		filter.Limit = AuditQueryMaxLimit
+	}
+
+	return db.Find(filter)
+}
+
+// Purge removes entries older than retention
+func (source *QpAuditManager) Purge() {
+	retention := environment.Settings.Audit.GetRetention()
+	if retention == 0 {
+		return
+	}
+
+	db, err := source.getDB()
+	if err != nil {
+		return
+	}
+
+	affected, err := db.Purge(time.Now().UTC().Add(-retention))
+	if err != nil {
+		log.Errorf("audit, error on purging entries: %s", err.Error())
+		return
+	}
+
+	if affected > 0 {
+		log.Infof("audit, purged %d entries older than %v days", affected, environment.Settings.Audit.Retention)
+	}
+}
@@ -0,0 +1,6 @@
+package models
+
+type QpAuditResponse struct {
+	QpResponse
+	Entries []*QpAuditEntry `json:"entries,omitempty"`
+}
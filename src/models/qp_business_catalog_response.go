@@ -0,0 +1,10 @@
+package models
+
+import whatsapp "github.com/nocodeleaks/quepasa/whatsapp"
+
+type QpBusinessCatalogResponse struct {
+	QpResponse
+	Products    []whatsapp.WhatsappBusinessProduct    `json:"products,omitempty"`
+	Cursor      string                                `json:"cursor,omitempty"`      // next page cursor, empty on last page
+	Collections []whatsapp.WhatsappBusinessCollection `json:"collections,omitempty"` // only on first page
+}
@@ -0,0 +1,79 @@
+package models
+
+import (
+	"fmt"
+
+	whatsapp "github.com/nocodeleaks/quepasa/whatsapp"
+)
+
+// default and maximum amount of products per catalog page or collection
+const (
+	QpBusinessCatalogDefaultLimit = 10
+	QpBusinessCatalogMaxLimit     = 100
+)
+
+// Compile-time check to ensure QpBusinessManager implements whatsapp.WhatsappBusinessManagerInterface
+var _ whatsapp.WhatsappBusinessManagerInterface = (*QpBusinessManager)(nil)
+
+// QpBusinessManager handles business profile and catalog operations for QpWhatsappServer
+// Implements whatsapp.WhatsappBusinessManagerInterface interface
+type QpBusinessManager struct {
+	*QpWhatsappServer // embedded server for direct access
+}
+
+// NewQpBusinessManager creates a new QpBusinessManager instance
+func NewQpBusinessManager(server *QpWhatsappServer) *QpBusinessManager {
+	return &QpBusinessManager{
+		QpWhatsappServer: server,
+	}
+}
+
+// getBusinessManager is a helper function to get the business manager from connection
+func (bm *QpBusinessManager) getBusinessManager() (whatsapp.WhatsappBusinessManagerInterface, error) {
+	conn, err := bm.GetValidConnection()
+	if err != nil {
+		return nil, fmt.Errorf("failed to get valid connection: %v", err)
+	}
+
+	businessManager := conn.GetBusinessManager()
+	if businessManager == nil {
+		return nil, fmt.Errorf("business manager not available")
+	}
+
+	return businessManager, nil
+}
+
+// getCatalogLimit applies default and maximum products limit
+func getCatalogLimit(limit int) int {
+	if limit <= 0 {
+		return QpBusinessCatalogDefaultLimit
+	}
+	return min(limit, QpBusinessCatalogMaxLimit)
+}
+
+// GetBusinessProfile returns the business profile of a contact, or own profile if wid is empty
+func (bm *QpBusinessManager) GetBusinessProfile(wid string) (*whatsapp.WhatsappBusinessProfile, error) {
+	businessManager, err := bm.getBusinessManager()
+	if err != nil {
+		return nil, err
+	}
+	return businessManager.GetBusinessProfile(wid)
+}
+
+// GetCatalog returns one page of own catalog products
+func (bm *QpBusinessManager) GetCatalog(limit int, cursor string) (*whatsapp.WhatsappBusinessCatalog, error) {
+	businessManager, err := bm.getBusinessManager()
+	if err != nil {
+		return nil, err
+	}
+	return businessManager.GetCatalog(getCatalogLimit(limit), cursor)
+}
+
+// GetCollections returns own catalog collections, with up to limit products each
+func (bm *QpBusinessManager) GetCollections(limit int) ([]whatsapp.WhatsappBusinessCollection, error) {
+	businessManager, err := bm.getBusinessManager()
+	if err != nil {
+		return nil, err
+	}
+	return businessManager.GetCollections(getCatalogLimit(limit))
+}
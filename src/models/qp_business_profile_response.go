@@ -0,0 +1,8 @@
+package models
+
+import whatsapp "github.com/nocodeleaks/quepasa/whatsapp"
+
+type QpBusinessProfileResponse struct {
+	QpResponse
+	Profile *whatsapp.WhatsappBusinessProfile `json:"profile,omitempty"`
+}
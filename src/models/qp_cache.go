@@ -77,16 +77,16 @@ func (source *QpCache) GetOrdered() (items []QpCacheItem) {
 	return
 }
 
-// remove old ones, by timestamp, until a maximum length
-func (source *QpCache) CleanUp(max uint64) {
+// remove old ones, by timestamp, until a maximum length, returns the removed keys
+func (source *QpCache) CleanUp(max uint64) (removed []string) {
 	if max > 0 {
 
 		// first checks only counter to avoid unecessary array searchs
 		length := source.counter.Load()
-		amount := length - max
 
 		// if really has items to do a cleanup
-		if amount > 0 {
+		if length > max {
+			amount := length - max
 
 			// searches the array for ordering and define the oldest items
 			items := source.GetOrdered()
@@ -96,7 +96,21 @@ func (source *QpCache) CleanUp(max uint64) {
 
 			for i := 0; i < int(amount) && i < itemsLength; i++ {
 				source.DeleteByKey(items[i].Key)
+				removed = append(removed, items[i].Key)
 			}
 		}
 	}
+	return
+}
+
+// remove expired ones, returns the removed keys
+func (source *QpCache) CleanUpExpired() (removed []string) {
+	now := time.Now()
+	for _, item := range source.GetSliceOfCachedItems() {
+		if now.After(item.Expiration) {
+			source.DeleteByKey(item.Key)
+			removed = append(removed, item.Key)
+		}
+	}
+	return
 }
@@ -0,0 +1,45 @@
+package models
+
+import "time"
+
+// campaign states
+const (
+	QpCampaignStatusDraft    = "draft"    // created, recipients can be uploaded
+	QpCampaignStatusRunning  = "running"  // sending queued recipients
+	QpCampaignStatusPaused   = "paused"   // stopped, can be resumed
+	QpCampaignStatusCanceled = "canceled" // stopped, remaining recipients will not be sent
+	QpCampaignStatusFinished = "finished" // all recipients processed
+)
+
+// default delay between sends, in milliseconds
+const QpCampaignDefaultDelay = 3000
+
+// QpCampaign is a message template sent to a list of recipients through a server
+type QpCampaign struct {
+	Id        string    `db:"id" json:"id"`
+	Context   string    `db:"context" json:"-"` // server token
+	Name      string    `db:"name" json:"name,omitempty"`
+	Template  string    `db:"template" json:"template"` // text with {{placeholders}} from recipient variables
+	Url       string    `db:"url" json:"url,omitempty"` // optional media to download
+	Content   string    `db:"content" json:"-"`         // optional BASE64 embed media
+	FileName  string    `db:"filename" json:"filename,omitempty"`
+	Delay     uint32    `db:"delay" json:"delay"` // milliseconds between sends
+	Status    string    `db:"status" json:"status"`
+	Error     string    `db:"error" json:"error,omitempty"` // last error that stopped the campaign
+	Timestamp time.Time `db:"timestamp" json:"timestamp"`
+	Updated   time.Time `db:"updated" json:"updated"`
+}
+
+func (source *QpCampaign) HasMedia() bool {
+	return len(source.Url) > 0 || len(source.Content) > 0
+}
+
+// IsEditable indicates if recipients can still be appended
+func (source *QpCampaign) IsEditable() bool {
+	return source.Status == QpCampaignStatusDraft || source.Status == QpCampaignStatusPaused
+}
+
+// IsClosed indicates if the campaign will not send anymore
+func (source *QpCampaign) IsClosed() bool {
+	return source.Status == QpCampaignStatusCanceled || source.Status == QpCampaignStatusFinished
+}
@@ -0,0 +1,47 @@
+package models
+
+import (
+	"strings"
+)
+
+// Request to create a campaign, recipients can be passed as json list or csv text
+type QpCampaignCreateRequest struct {
+	Name       string                       `json:"name,omitempty"`
+	Template   string                       `json:"template,omitempty"` // text with {{placeholders}}
+	Url        string                       `json:"url,omitempty"`      // media to download
+	Content    string                       `json:"content,omitempty"`  // BASE64 embed media
+	FileName   string                       `json:"filename,omitempty"`
+	Delay      uint32                       `json:"delay,omitempty"` // milliseconds between sends
+	Recipients []QpCampaignRecipientRequest `json:"recipients,omitempty"`
+	Csv        string                       `json:"csv,omitempty"`   // csv with header, chatid or phone column required
+	Start      bool                         `json:"start,omitempty"` // start right after creation
+}
+
+func (source *QpCampaignCreateRequest) ToCampaign() *QpCampaign {
+	return &QpCampaign{
+		Name:     source.Name,
+		Template: source.Template,
+		Url:      strings.TrimSpace(source.Url),
+		Content:  source.Content,
+		FileName: source.FileName,
+		Delay:    source.Delay,
+	}
+}
+
+// GetRecipients validates json and csv recipients
+func (source *QpCampaignCreateRequest) GetRecipients() (recipients []*QpCampaignRecipient, err error) {
+	recipients, err = ParseCampaignRecipients(source.Recipients)
+	if err != nil {
+		return
+	}
+
+	if len(strings.TrimSpace(source.Csv)) > 0 {
+		fromCsv, csvErr := ParseCampaignRecipientsCSV(strings.NewReader(source.Csv))
+		if csvErr != nil {
+			return nil, csvErr
+		}
+		recipients = append(recipients, fromCsv...)
+	}
+
+	return
+}
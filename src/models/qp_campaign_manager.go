@@ -0,0 +1,532 @@
+package models
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/google/uuid"
+	whatsapp "github.com/nocodeleaks/quepasa/whatsapp"
+	log "github.com/sirupsen/logrus"
+)
+
+// interval to check again a server that is not ready for sending
+const QpCampaignServerWait = 30 * time.Second
+
+var ErrCampaignNotFound error = errors.New("the requested campaign was not found")
+
+// QpCampaignManager runs campaigns and tracks recipients receipts, thread safe
+type QpCampaignManager struct {
+	mutex    sync.Mutex
+	runners  map[string]chan struct{} // campaign id => stop signal of running campaigns
+	messages sync.Map                 // sent message id => campaign id, awaiting receipts
+	receipts sync.Mutex               // receipts wait for the message id of sent recipients to be saved
+}
+
+var CampaignManager = &QpCampaignManager{
+	runners: map[string]chan struct{}{},
+}
+
+func (source *QpCampaignManager) getDB() (QpDataCampaignsInterface, error) {
+	if WhatsappService == nil || WhatsappService.DB == nil || WhatsappService.DB.Campaigns == nil {
+		return nil, fmt.Errorf("campaigns database not ready")
+	}
+	return WhatsappService.DB.Campaigns, nil
+}
+
+// Initialize loads recipients awaiting receipts and resumes running campaigns
+func (source *QpCampaignManager) Initialize() error {
+	db, err := source.getDB()
+	if err != nil {
+		return err
+	}
+
+	awaiting, err := db.FindAwaitingReceipts()
+	if err != nil {
+		return err
+	}
+
+	for _, recipient := range awaiting {
+		if len(recipient.MessageId) > 0 {
+			source.messages.Store(strings.ToUpper(recipient.MessageId), recipient.Campaign)
+		}
+	}
+
+	running, err := db.FindByStatus(QpCampaignStatusRunning)
+	if err != nil {
+		return err
+	}
+
+	for _, campaign := range running {
+		// on cluster mode, other nodes resume the campaigns of their own sessions
+		if !ClusterManager.IsOwner(campaign.Context) {
+			continue
+		}
+
+		source.resume(db, campaign)
+	}
+
+	return nil
+}
+
+// Resume runs the running campaigns of a server, used when a cluster node claims it
+func (source *QpCampaignManager) Resume(token string) {
+	db, err := source.getDB()
+	if err != nil {
+		log.Errorf("campaigns, %s", err.Error())
+		return
+	}
+
+	running, err := db.FindByStatus(QpCampaignStatusRunning)
+	if err != nil {
+		log.Errorf("campaigns, error on finding running campaigns: %s", err.Error())
+		return
+	}
+
+	for _, campaign := range running {
+		if campaign.Context == token {
+			source.resume(db, campaign)
+		}
+	}
+}
+
+// resume spawns the runner of a campaign interrupted by a restart or by a node failure,
+// recipients left on sending may have been delivered, so they are failed instead of sent again
+func (source *QpCampaignManager) resume(db QpDataCampaignsInterface, campaign *QpCampaign) {
+	source.mutex.Lock()
+	defer source.mutex.Unlock()
+
+	if _, ok := source.runners[campaign.Id]; ok {
+		return
+	}
+
+	interrupted, err := db.FindRecipients(campaign.Id, QpCampaignRecipientSending)
+	if err != nil {
+		log.Errorf("campaign %s, error on finding interrupted recipients: %s", campaign.Id, err.Error())
+		return
+	}
+
+	for _, recipient := range interrupted {
+		recipient.Status = QpCampaignRecipientFailed
+		recipient.Error = "interrupted while sending, it may have been delivered"
+		recipient.Timestamp = time.Now().UTC()
+		err = db.UpdateRecipient(recipient)
+		if err != nil {
+			log.Errorf("campaign %s, error on updating interrupted recipient %s: %s", campaign.Id, recipient.ChatId, err.Error())
+		}
+	}
+
+	log.Infof("resuming campaign: %s", campaign.Id)
+	source.spawnLocked(campaign)
+}
+
+// Find a campaign owned by the server token
+func (source *QpCampaignManager) Find(token string, id string) (*QpCampaign, error) {
+	db, err := source.getDB()
+	if err != nil {
+		return nil, err
+	}
+
+	campaign, err := db.Find(id)
+	if err != nil {
+		return nil, err
+	}
+
+	if campaign == nil || campaign.Context != token {
+		return nil, ErrCampaignNotFound
+	}
+
+	return campaign, nil
+}
+
+// Create saves a new draft campaign with its initial recipients
+func (source *QpCampaignManager) Create(token string, campaign *QpCampaign, recipients []*QpCampaignRecipient) (err error) {
+	db, err := source.getDB()
+	if err != nil {
+		return
+	}
+
+	if len(campaign.Template) == 0 && !campaign.HasMedia() {
+		return fmt.Errorf("template or media is required, do not send empty messages")
+	}
+
+	if campaign.Delay == 0 {
+		campaign.Delay = QpCampaignDefaultDelay
+	}
+
+	campaign.Id = uuid.New().String()
+	campaign.Context = token
+	campaign.Status = QpCampaignStatusDraft
+	campaign.Timestamp = time.Now().UTC()
+	campaign.Updated = campaign.Timestamp
+
+	err = db.Add(campaign)
+	if err != nil {
+		return
+	}
+
+	if len(recipients) > 0 {
+		_, err = source.AddRecipients(campaign, recipients)
+	}
+	return
+}
+
+// AddRecipients appends recipients to a draft or paused campaign, duplicated chats are ignored
+func (source *QpCampaignManager) AddRecipients(campaign *QpCampaign, recipients []*QpCampaignRecipient) (affected uint, err error) {
+	db, err := source.getDB()
+	if err != nil {
+		return
+	}
+
+	if !campaign.IsEditable() {
+		err = fmt.Errorf("recipients can not be added to a %s campaign", campaign.Status)
+		return
+	}
+
+	now := time.Now().UTC()
+	for _, recipient := range recipients {
+		recipient.Status = QpCampaignRecipientQueued
+		recipient.Timestamp = now
+	}
+
+	return db.AddRecipients(campaign.Id, recipients)
+}
+
+// Start runs a draft or paused campaign
+func (source *QpCampaignManager) Start(campaign *QpCampaign) error {
+	if !campaign.IsEditable() {
+		return fmt.Errorf("a %s campaign can not be started", campaign.Status)
+	}
+
+	campaign.Status = QpCampaignStatusRunning
+	campaign.Error = ""
+	err := source.save(campaign)
+	if err != nil {
+		return err
+	}
+
+	source.spawn(campaign)
+	return nil
+}
+
+// Pause stops a running campaign, keeping queued recipients
+func (source *QpCampaignManager) Pause(campaign *QpCampaign) error {
+	if campaign.Status != QpCampaignStatusRunning {
+		return fmt.Errorf("a %s campaign can not be paused", campaign.Status)
+	}
+
+	return source.stop(campaign, QpCampaignStatusPaused)
+}
+
+// Cancel stops a campaign, queued recipients will not be sent
+func (source *QpCampaignManager) Cancel(campaign *QpCampaign) error {
+	if campaign.IsClosed() {
+		return fmt.Errorf("a %s campaign can not be canceled", campaign.Status)
+	}
+
+	return source.stop(campaign, QpCampaignStatusCanceled)
+}
+
+// Delete cancels (if running) and removes a campaign with all recipients
+func (source *QpCampaignManager) Delete(campaign *QpCampaign) error {
+	db, err := source.getDB()
+	if err != nil {
+		return err
+	}
+
+	if campaign.Status == QpCampaignStatusRunning {
+		err = source.stop(campaign, QpCampaignStatusCanceled)
+		if err != nil {
+			return err
+		}
+	}
+
+	return db.Delete(campaign.Id)
+}
+
+// GetCounters returns the number of recipients per status
+func (source *QpCampaignManager) GetCounters(campaign *QpCampaign) (map[string]uint, error) {
+	db, err := source.getDB()
+	if err != nil {
+		return nil, err
+	}
+	return db.CountRecipients(campaign.Id)
+}
+
+// GetRecipients of a campaign, filtered by status if not empty
+func (source *QpCampaignManager) GetRecipients(campaign *QpCampaign, status string) ([]*QpCampaignRecipient, error) {
+	db, err := source.getDB()
+	if err != nil {
+		return nil, err
+	}
+	return db.FindRecipients(campaign.Id, status)
+}
+
+// StatusUpdate moves campaign recipients forward from message receipts
+func (source *QpCampaignManager) StatusUpdate(id string, status whatsapp.WhatsappMessageStatus) {
+	key := strings.ToUpper(id)
+	if _, ok := source.messages.Load(key); !ok {
+		return
+	}
+
+	var next string
+	switch status {
+	case whatsapp.WhatsappMessageStatusDelivered:
+		next = QpCampaignRecipientDelivered
+	case whatsapp.WhatsappMessageStatusRead:
+		next = QpCampaignRecipientRead
+	default:
+		return
+	}
+
+	db, err := source.getDB()
+	if err != nil {
+		return
+	}
+
+	source.receipts.Lock()
+	defer source.receipts.Unlock()
+
+	recipient, err := db.FindRecipientByMessageId(id)
+	if err != nil || recipient == nil {
+		return
+	}
+
+	if GetCampaignRecipientStatusOrder(next) <= GetCampaignRecipientStatusOrder(recipient.Status) {
+		return
+	}
+
+	recipient.Status = next
+	recipient.Timestamp = time.Now().UTC()
+	err = db.UpdateRecipient(recipient)
+	if err != nil {
+		log.Warnf("campaign %s, error on updating recipient status: %s", recipient.Campaign, err.Error())
+		return
+	}
+
+	// final state, no more receipts expected
+	if next == QpCampaignRecipientRead {
+		source.messages.Delete(key)
+	}
+}
+
+func (source *QpCampaignManager) save(campaign *QpCampaign) error {
+	db, err := source.getDB()
+	if err != nil {
+		return err
+	}
+
+	campaign.Updated = time.Now().UTC()
+	return db.Update(campaign)
+}
+
+// stop signals the runner and saves the new status
+func (source *QpCampaignManager) stop(campaign *QpCampaign, status string) error {
+	source.mutex.Lock()
+	defer source.mutex.Unlock()
+
+	if stop, ok := source.runners[campaign.Id]; ok {
+		close(stop)
+		delete(source.runners, campaign.Id)
+	}
+
+	campaign.Status = status
+	return source.save(campaign)
+}
+
+// spawn starts the runner goroutine if not running yet
+func (source *QpCampaignManager) spawn(campaign *QpCampaign) {
+	source.mutex.Lock()
+	defer source.mutex.Unlock()
+
+	source.spawnLocked(campaign)
+}
+
+// spawnLocked without locking, a runner stopped but still sending may coexist with the new one,
+// recipients are claimed one by one, so none is sent twice
+func (source *QpCampaignManager) spawnLocked(campaign *QpCampaign) {
+	if _, ok := source.runners[campaign.Id]; ok {
+		return
+	}
+
+	stop := make(chan struct{})
+	source.runners[campaign.Id] = stop
+	go source.run(*campaign, stop)
+}
+
+// finish closes the campaign if it was not stopped meanwhile
+func (source *QpCampaignManager) finish(campaign *QpCampaign, stop chan struct{}, status string, cause error) {
+	source.mutex.Lock()
+	defer source.mutex.Unlock()
+
+	if current, ok := source.runners[campaign.Id]; !ok || current != stop {
+		return
+	}
+	delete(source.runners, campaign.Id)
+
+	campaign.Status = status
+	if cause != nil {
+		campaign.Error = cause.Error()
+	}
+
+	err := source.save(campaign)
+	if err != nil {
+		log.Errorf("campaign %s, error on saving status: %s", campaign.Id, err.Error())
+	}
+}
+
+// wait returns false if stopped while waiting
+func (source *QpCampaignManager) wait(stop chan struct{}, duration time.Duration) bool {
+	timer := time.NewTimer(duration)
+	defer timer.Stop()
+
+	select {
+	case <-stop:
+		return false
+	case <-timer.C:
+		return true
+	}
+}
+
+// getReadyServer waits for the campaign server to be ready and served by this node, nil if stopped
+func (source *QpCampaignManager) getReadyServer(campaign *QpCampaign, stop chan struct{}) *QpWhatsappServer {
+	for {
+		server, err := GetServerFromToken(campaign.Context)
+		if err == nil && server.GetStatus() == whatsapp.Ready && ClusterManager.IsOwner(campaign.Context) {
+			return server
+		}
+
+		log.Warnf("campaign %s, server not ready, waiting", campaign.Id)
+		if !source.wait(stop, QpCampaignServerWait) {
+			return nil
+		}
+	}
+}
+
+// run sends queued recipients, one at a time, respecting the campaign delay
+func (source *QpCampaignManager) run(campaign QpCampaign, stop chan struct{}) {
+	logentry := log.WithField("campaign", campaign.Id)
+	logentry.Info("campaign started")
+
+	db, err := source.getDB()
+	if err != nil {
+		source.finish(&campaign, stop, QpCampaignStatusPaused, err)
+		return
+	}
+
+	// media is downloaded once for all recipients
+	media := &QpSendAnyRequest{Url: campaign.Url, Content: campaign.Content}
+	media.FileName = campaign.FileName
+	if len(media.Url) > 0 {
+		err = media.GenerateUrlContent()
+	} else if len(media.Content) > 0 {
+		err = media.GenerateEmbedContent()
+	}
+	if err != nil {
+		source.finish(&campaign, stop, QpCampaignStatusPaused, fmt.Errorf("error on getting media: %s", err.Error()))
+		return
+	}
+
+	recipients, err := db.FindRecipients(campaign.Id, QpCampaignRecipientQueued)
+	if err != nil {
+		source.finish(&campaign, stop, QpCampaignStatusPaused, err)
+		return
+	}
+
+	for index, recipient := range recipients {
+		server := source.getReadyServer(&campaign, stop)
+		if server == nil {
+			logentry.Info("campaign stopped")
+			return
+		}
+
+		// stopped while sending previous recipient
+		select {
+		case <-stop:
+			logentry.Info("campaign stopped")
+			return
+		default:
+		}
+
+		// a previous runner of this campaign may have sent it meanwhile
+		recipient.Timestamp = time.Now().UTC()
+		claimed, err := db.ClaimRecipient(recipient)
+		if err != nil {
+			logentry.Errorf("error on claiming recipient %s: %s", recipient.ChatId, err.Error())
+			continue
+		}
+		if !claimed {
+			continue
+		}
+
+		messageid, err := source.send(server, &campaign, &media.QpSendRequest, recipient)
+		recipient.Timestamp = time.Now().UTC()
+		if err != nil {
+			logentry.Warnf("error on sending to %s: %s", recipient.ChatId, err.Error())
+			recipient.Status = QpCampaignRecipientFailed
+			recipient.Error = err.Error()
+		} else {
+			recipient.Status = QpCampaignRecipientSent
+			recipient.MessageId = messageid
+		}
+
+		// receipts arriving meanwhile are applied after the save, over the sent status
+		source.receipts.Lock()
+		if len(recipient.MessageId) > 0 {
+			source.messages.Store(strings.ToUpper(recipient.MessageId), campaign.Id)
+		}
+
+		err = db.UpdateRecipient(recipient)
+		source.receipts.Unlock()
+		if err != nil {
+			logentry.Errorf("error on updating recipient %s: %s", recipient.ChatId, err.Error())
+		}
+
+		if index < len(recipients)-1 && !source.wait(stop, time.Duration(campaign.Delay)*time.Millisecond) {
+			logentry.Info("campaign stopped")
+			return
+		}
+	}
+
+	logentry.Info("campaign finished")
+	source.finish(&campaign, stop, QpCampaignStatusFinished, nil)
+}
+
+// send renders the template for the recipient and sends through the server send path
+func (source *QpCampaignManager) send(server *QpWhatsappServer, campaign *QpCampaign, media *QpSendRequest, recipient *QpCampaignRecipient) (string, error) {
+	request := &QpSendRequest{
+		ChatId:     recipient.ChatId,
+		Text:       recipient.Render(campaign.Template),
+		TrackId:    "campaign:" + campaign.Id,
+		FileName:   media.FileName,
+		Mimetype:   media.Mimetype,
+		FileLength: media.FileLength,
+	}
+
+	// each message owns its content, attachment treatments may change it
+	if len(media.Content) > 0 {
+		request.Content = append([]byte(nil), media.Content...)
+	}
+
+	att := request.ToWhatsappAttachment()
+	waMsg, err := request.ToWhatsappMessage()
+	if err != nil {
+		return "", err
+	}
+
+	if att.Attach != nil {
+		waMsg.Attachment = att.Attach
+		waMsg.Type = whatsapp.GetMessageType(att.Attach)
+	} else {
+		waMsg.Type = whatsapp.TextMessageType
+	}
+
+	response, err := server.SendMessage(waMsg)
+	if err != nil {
+		return "", err
+	}
+
+	return response.GetId(), nil
+}
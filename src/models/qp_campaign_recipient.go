@@ -0,0 +1,199 @@
+package models
+
+import (
+	"encoding/csv"
+	"encoding/json"
+	"fmt"
+	"io"
+	"regexp"
+	"strings"
+	"time"
+
+	whatsapp "github.com/nocodeleaks/quepasa/whatsapp"
+)
+
+// recipient states, delivered and read are driven by receipts
+const (
+	QpCampaignRecipientQueued    = "queued"
+	QpCampaignRecipientSending   = "sending" // claimed by a runner, being sent
+	QpCampaignRecipientSent      = "sent"
+	QpCampaignRecipientDelivered = "delivered"
+	QpCampaignRecipientRead      = "read"
+	QpCampaignRecipientFailed    = "failed"
+)
+
+type QpCampaignRecipient struct {
+	Campaign  string            `db:"campaign" json:"-"`
+	ChatId    string            `db:"chatid" json:"chatid"`
+	Extra     []byte            `db:"variables" json:"-"` // json serialized variables
+	Variables map[string]string `db:"-" json:"variables,omitempty"`
+	Status    string            `db:"status" json:"status"`
+	MessageId string            `db:"messageid" json:"messageid,omitempty"`
+	Error     string            `db:"error" json:"error,omitempty"`
+	Timestamp time.Time         `db:"timestamp" json:"timestamp"`
+}
+
+// GetExtraText returns variables serialized as json
+func (source *QpCampaignRecipient) GetExtraText() string {
+	if len(source.Variables) == 0 {
+		return ""
+	}
+
+	extraJson, err := json.Marshal(source.Variables)
+	if err != nil {
+		return ""
+	}
+	return string(extraJson)
+}
+
+// ParseExtra fills variables from json serialized column
+func (source *QpCampaignRecipient) ParseExtra() {
+	if len(source.Extra) > 0 {
+		_ = json.Unmarshal(source.Extra, &source.Variables)
+	}
+}
+
+// GetCampaignRecipientStatusOrder is used to never move a recipient status backwards
+func GetCampaignRecipientStatusOrder(status string) uint {
+	switch status {
+	case QpCampaignRecipientSent:
+		return 1
+	case QpCampaignRecipientDelivered:
+		return 2
+	case QpCampaignRecipientRead:
+		return 3
+	case QpCampaignRecipientFailed:
+		return 4
+	}
+	return 0
+}
+
+var campaignPlaceholder = regexp.MustCompile(`\{\{\s*([\w.-]+)\s*\}\}`)
+
+// Render replaces {{placeholders}} of template with recipient variables (case insensitive)
+// Built in: {{chatid}} and {{phone}}, unknown placeholders are kept
+func (source *QpCampaignRecipient) Render(template string) string {
+	return campaignPlaceholder.ReplaceAllStringFunc(template, func(match string) string {
+		key := strings.ToLower(campaignPlaceholder.FindStringSubmatch(match)[1])
+		if value, ok := source.Variables[key]; ok {
+			return value
+		}
+
+		switch key {
+		case "chatid":
+			return source.ChatId
+		case "phone":
+			phone, _ := whatsapp.GetPhoneIfValid(source.ChatId)
+			return phone
+		}
+
+		return match
+	})
+}
+
+// NewCampaignRecipient validates the chatid and normalizes variable names to lower case
+func NewCampaignRecipient(chatid string, variables map[string]string) (*QpCampaignRecipient, error) {
+	formatted, err := whatsapp.FormatEndpoint(strings.TrimSpace(chatid))
+	if err != nil {
+		return nil, err
+	}
+
+	recipient := &QpCampaignRecipient{
+		ChatId: formatted,
+		Status: QpCampaignRecipientQueued,
+	}
+
+	for key, value := range variables {
+		if recipient.Variables == nil {
+			recipient.Variables = map[string]string{}
+		}
+		recipient.Variables[strings.ToLower(strings.TrimSpace(key))] = value
+	}
+
+	return recipient, nil
+}
+
+// ParseCampaignRecipientsCSV reads a csv with header, "chatid" or "phone" column is required
+// Other columns are used as variables
+func ParseCampaignRecipientsCSV(reader io.Reader) (recipients []*QpCampaignRecipient, err error) {
+	csvReader := csv.NewReader(reader)
+	csvReader.TrimLeadingSpace = true
+
+	header, err := csvReader.Read()
+	if err != nil {
+		err = fmt.Errorf("invalid csv header: %s", err.Error())
+		return
+	}
+
+	column := -1
+	for index, name := range header {
+		name = strings.ToLower(strings.TrimSpace(name))
+		header[index] = name
+		if column == -1 && (name == "chatid" || name == "phone") {
+			column = index
+		}
+	}
+
+	if column == -1 {
+		err = fmt.Errorf("csv header must have a chatid or phone column")
+		return
+	}
+
+	line := 1
+	for {
+		record, readErr := csvReader.Read()
+		if readErr == io.EOF {
+			break
+		}
+
+		line++
+		if readErr != nil {
+			err = fmt.Errorf("invalid csv at line %d: %s", line, readErr.Error())
+			return
+		}
+
+		variables := map[string]string{}
+		for index, value := range record {
+			if index != column && index < len(header) {
+				variables[header[index]] = value
+			}
+		}
+
+		recipient, parseErr := NewCampaignRecipient(record[column], variables)
+		if parseErr != nil {
+			err = fmt.Errorf("invalid recipient at line %d: %s", line, parseErr.Error())
+			return
+		}
+
+		recipients = append(recipients, recipient)
+	}
+
+	return
+}
+
+// QpCampaignRecipientRequest is a json recipient, "phone" is accepted instead of "chatid"
+type QpCampaignRecipientRequest struct {
+	ChatId    string            `json:"chatid,omitempty"`
+	Phone     string            `json:"phone,omitempty"`
+	Variables map[string]string `json:"variables,omitempty"`
+}
+
+// ParseCampaignRecipients validates json recipients
+func ParseCampaignRecipients(items []QpCampaignRecipientRequest) (recipients []*QpCampaignRecipient, err error) {
+	for index, item := range items {
+		chatid := item.ChatId
+		if len(chatid) == 0 {
+			chatid = item.Phone
+		}
+
+		recipient, parseErr := NewCampaignRecipient(chatid, item.Variables)
+		if parseErr != nil {
+			err = fmt.Errorf("invalid recipient at index %d: %s", index, parseErr.Error())
+			return
+		}
+
+		recipients = append(recipients, recipient)
+	}
+
+	return
+}
@@ -0,0 +1,8 @@
+package models
+
+type QpCampaignRecipientsResponse struct {
+	QpResponse
+	Total      int                    `json:"total"`
+	Affected   uint                   `json:"affected,omitempty"` // recipients appended on upload
+	Recipients []*QpCampaignRecipient `json:"recipients,omitempty"`
+}
@@ -0,0 +1,6 @@
+package models
+
+type QpCampaignResponse struct {
+	QpResponse
+	Campaign *QpCampaignSummary `json:"campaign,omitempty"`
+}
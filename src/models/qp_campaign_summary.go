@@ -0,0 +1,21 @@
+package models
+
+// Campaign with recipients counters per status
+type QpCampaignSummary struct {
+	*QpCampaign
+	Total    uint            `json:"total"`
+	Counters map[string]uint `json:"counters,omitempty"` // queued, sending, sent, delivered, read, failed
+}
+
+func NewQpCampaignSummary(campaign *QpCampaign) (*QpCampaignSummary, error) {
+	counters, err := CampaignManager.GetCounters(campaign)
+	if err != nil {
+		return nil, err
+	}
+
+	summary := &QpCampaignSummary{QpCampaign: campaign, Counters: counters}
+	for _, count := range counters {
+		summary.Total += count
+	}
+	return summary, nil
+}
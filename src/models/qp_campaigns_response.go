@@ -0,0 +1,7 @@
+package models
+
+type QpCampaignsResponse struct {
+	QpResponse
+	Total     int                  `json:"total"`
+	Campaigns []*QpCampaignSummary `json:"campaigns,omitempty"`
+}
@@ -0,0 +1,93 @@
+package models
+
+import (
+	"fmt"
+	"time"
+
+	whatsapp "github.com/nocodeleaks/quepasa/whatsapp"
+)
+
+// Compile-time check to ensure QpChatManager implements whatsapp.WhatsappChatManagerInterface
+var _ whatsapp.WhatsappChatManagerInterface = (*QpChatManager)(nil)
+
+// QpChatManager handles chat management operations for QpWhatsappServer
+// Implements whatsapp.WhatsappChatManagerInterface interface
+type QpChatManager struct {
+	*QpWhatsappServer // embedded server for direct access
+}
+
+// NewQpChatManager creates a new QpChatManager instance
+func NewQpChatManager(server *QpWhatsappServer) *QpChatManager {
+	return &QpChatManager{
+		QpWhatsappServer: server,
+	}
+}
+
+// getChatManager is a helper function to get the chat manager from connection
+func (cm *QpChatManager) getChatManager() (whatsapp.WhatsappChatManagerInterface, error) {
+	conn, err := cm.GetValidConnection()
+	if err != nil {
+		return nil, fmt.Errorf("failed to get valid connection: %v", err)
+	}
+
+	chatManager := conn.GetChatManager()
+	if chatManager == nil {
+		return nil, fmt.Errorf("chat manager not available")
+	}
+
+	return chatManager, nil
+}
+
+// PinChat pins or unpins a chat
+func (cm *QpChatManager) PinChat(chatId string, pin bool) error {
+	chatManager, err := cm.getChatManager()
+	if err != nil {
+		return err
+	}
+	return chatManager.PinChat(chatId, pin)
+}
+
+// MuteChat mutes or unmutes a chat, zero duration mutes forever
+func (cm *QpChatManager) MuteChat(chatId string, mute bool, duration time.Duration) error {
+	chatManager, err := cm.getChatManager()
+	if err != nil {
+		return err
+	}
+	return chatManager.MuteChat(chatId, mute, duration)
+}
+
+// StarMessage stars or unstars a message
+func (cm *QpChatManager) StarMessage(msg *whatsapp.WhatsappMessage, starred bool) error {
+	chatManager, err := cm.getChatManager()
+	if err != nil {
+		return err
+	}
+	return chatManager.StarMessage(msg, starred)
+}
+
+// DeleteMessageForMe deletes a message only for the connected account
+func (cm *QpChatManager) DeleteMessageForMe(msg *whatsapp.WhatsappMessage, deleteMedia bool) error {
+	chatManager, err := cm.getChatManager()
+	if err != nil {
+		return err
+	}
+	return chatManager.DeleteMessageForMe(msg, deleteMedia)
+}
+
+// ClearChat clears all messages of a chat
+func (cm *QpChatManager) ClearChat(chatId string) error {
+	chatManager, err := cm.getChatManager()
+	if err != nil {
+		return err
+	}
+	return chatManager.ClearChat(chatId)
+}
+
+// SetDisappearingTimer sets the disappearing messages timer of a chat, zero disables
+func (cm *QpChatManager) SetDisappearingTimer(chatId string, timer time.Duration) error {
+	chatManager, err := cm.getChatManager()
+	if err != nil {
+		return err
+	}
+	return chatManager.SetDisappearingTimer(chatId, timer)
+}
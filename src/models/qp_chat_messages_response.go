@@ -0,0 +1,14 @@
+package models
+
+import (
+	whatsapp "github.com/nocodeleaks/quepasa/whatsapp"
+)
+
+// Page of a chat conversation, newer first
+type QpChatMessagesResponse struct {
+	QpResponse
+	Chat     whatsapp.WhatsappChat      `json:"chat"`
+	Before   string                     `json:"before,omitempty"` // cursor for older messages, empty if no more
+	After    string                     `json:"after,omitempty"`  // cursor for newer messages, empty if no more
+	Messages []whatsapp.WhatsappMessage `json:"messages,omitempty"`
+}
@@ -0,0 +1,19 @@
+package models
+
+import (
+	"time"
+
+	whatsapp "github.com/nocodeleaks/quepasa/whatsapp"
+)
+
+// Chat list item, built from cached messages and chat settings
+type QpChatSummary struct {
+	whatsapp.WhatsappChat
+
+	LastMessage *whatsapp.WhatsappMessage `json:"lastmessage,omitempty"`
+	Unread      int                       `json:"unread"`             // incoming messages after last read
+	Archived    bool                      `json:"archived,omitempty"` // chat is archived
+	Pinned      bool                      `json:"pinned,omitempty"`   // chat is pinned on top
+	MutedUntil  *time.Time                `json:"muteduntil,omitempty"`
+	Total       int                       `json:"total"` // cached messages of this chat
+}
@@ -0,0 +1,7 @@
+package models
+
+type QpChatsResponse struct {
+	QpResponse
+	Total int             `json:"total"`
+	Chats []QpChatSummary `json:"chats,omitempty"`
+}
@@ -0,0 +1,15 @@
+package models
+
+import "time"
+
+// Session ownership lease, renewed by the owner node
+type QpClusterLease struct {
+	Token   string    `db:"token" json:"token"`
+	Node    string    `db:"node" json:"node"`
+	Expires time.Time `db:"expires" json:"expires"`
+}
+
+// IsValid returns true if the lease is not expired
+func (source *QpClusterLease) IsValid(now time.Time) bool {
+	return source != nil && source.Expires.After(now)
+}
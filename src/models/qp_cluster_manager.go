@@ -0,0 +1,327 @@
+package models
+
+import (
+	"fmt"
+	"strings"
+	"sync"
+	"time"
+
+	environment "github.com/nocodeleaks/quepasa/environment"
+	whatsapp "github.com/nocodeleaks/quepasa/whatsapp"
+	log "github.com/sirupsen/logrus"
+)
+
+// QpClusterManager distributes sessions between nodes through renewable leases on the shared database,
+// each node starts only the sessions it owns, thread safe
+type QpClusterManager struct {
+	mutex sync.Mutex
+	owned map[string]bool // tokens leased by this node
+	stop  chan struct{}
+}
+
+var ClusterManager = &QpClusterManager{
+	owned: map[string]bool{},
+}
+
+func (source *QpClusterManager) getDB() (QpDataClusterInterface, error) {
+	if WhatsappService == nil || WhatsappService.DB == nil || WhatsappService.DB.Cluster == nil {
+		return nil, fmt.Errorf("cluster database not ready")
+	}
+	return WhatsappService.DB.Cluster, nil
+}
+
+// Enabled returns true if running in cluster mode
+func (source *QpClusterManager) Enabled() bool {
+	return environment.Settings.Cluster.Enabled
+}
+
+// Initialize registers this node, claims its share of sessions and keeps renewing leases
+func (source *QpClusterManager) Initialize() error {
+	if !source.Enabled() {
+		return nil
+	}
+
+	_, err := source.getDB()
+	if err != nil {
+		return err
+	}
+
+	settings := environment.Settings.Cluster
+	log.Infof("cluster mode enabled, node: %s, url: %s, lease: %v", settings.NodeId, settings.NodeUrl, settings.GetLeaseDuration())
+	log.Infof("cluster leases are stored on the quepasa sqlite database, all nodes must open the same file, see docs/CLUSTER.md")
+	if len(settings.NodeUrl) == 0 {
+		log.Warnf("cluster node url not set, requests for sessions owned by this node can not be forwarded")
+	}
+
+	source.Balance()
+
+	source.mutex.Lock()
+	if source.stop == nil {
+		source.stop = make(chan struct{})
+		go source.run(source.stop, settings.GetLeaseDuration()/3)
+	}
+	source.mutex.Unlock()
+	return nil
+}
+
+func (source *QpClusterManager) run(stop chan struct{}, interval time.Duration) {
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-stop:
+			return
+		case <-ticker.C:
+			source.Balance()
+		}
+	}
+}
+
+// Shutdown stops renewing and releases owned sessions, so other nodes claim them without awaiting expiration
+func (source *QpClusterManager) Shutdown() {
+	if !source.Enabled() {
+		return
+	}
+
+	source.mutex.Lock()
+	defer source.mutex.Unlock()
+
+	if source.stop != nil {
+		close(source.stop)
+		source.stop = nil
+	}
+
+	for token := range source.owned {
+		source.stopServer(token, "cluster shutdown")
+	}
+	source.owned = map[string]bool{}
+
+	db, err := source.getDB()
+	if err != nil {
+		return
+	}
+
+	err = db.RemoveNode(environment.Settings.Cluster.NodeId)
+	if err != nil {
+		log.Errorf("cluster, error on removing node: %s", err.Error())
+	}
+}
+
+// Balance renews owned leases, then claims or releases sessions to keep a fair share per alive node
+func (source *QpClusterManager) Balance() {
+	db, err := source.getDB()
+	if err != nil {
+		log.Errorf("cluster, %s", err.Error())
+		return
+	}
+
+	settings := environment.Settings.Cluster
+	node := settings.NodeId
+	now := time.Now().UTC()
+	expires := now.Add(settings.GetLeaseDuration())
+
+	err = db.Heartbeat(&QpClusterNode{Id: node, Url: settings.NodeUrl, Expires: expires})
+	if err != nil {
+		log.Errorf("cluster, error on heartbeat: %s", err.Error())
+		return
+	}
+
+	nodes, err := db.FindNodes(now)
+	if err != nil {
+		log.Errorf("cluster, error on finding nodes: %s", err.Error())
+		return
+	}
+
+	leases, err := db.FindLeases()
+	if err != nil {
+		log.Errorf("cluster, error on finding leases: %s", err.Error())
+		return
+	}
+
+	leased := map[string]*QpClusterLease{}
+	for _, lease := range leases {
+		if lease.IsValid(now) {
+			leased[lease.Token] = lease
+		}
+	}
+
+	// only verified sessions can be started, the others are served by any node
+	candidates := map[string]*QpServer{}
+	for _, info := range WhatsappService.DB.Servers.FindAll() {
+		if info.Verified {
+			candidates[info.Token] = info
+		}
+	}
+
+	alive := len(nodes)
+	if alive == 0 {
+		alive = 1
+	}
+	share := (len(candidates) + alive - 1) / alive
+
+	source.mutex.Lock()
+	defer source.mutex.Unlock()
+
+	// renewing, a lease may be lost after a long pause or database failure
+	for token := range source.owned {
+		if _, ok := candidates[token]; !ok {
+			source.release(db, token, node, "session removed or unverified")
+			continue
+		}
+
+		claimed, err := db.Claim(token, node, expires, now)
+		if err != nil {
+			log.Errorf("cluster, error on renewing lease: %s, %s", token, err.Error())
+			continue
+		}
+
+		if !claimed {
+			delete(source.owned, token)
+			source.stopServer(token, "cluster lease lost")
+		}
+	}
+
+	// releasing surplus, a node joined the cluster
+	for token := range source.owned {
+		if len(source.owned) <= share {
+			break
+		}
+		source.release(db, token, node, "cluster rebalance")
+	}
+
+	// claiming free or expired sessions, a node died or new sessions were added
+	for token, info := range candidates {
+		if len(source.owned) >= share {
+			break
+		}
+
+		if source.owned[token] {
+			continue
+		}
+
+		if lease, ok := leased[token]; ok && lease.Node != node {
+			continue
+		}
+
+		claimed, err := db.Claim(token, node, expires, now)
+		if err != nil {
+			log.Errorf("cluster, error on claiming lease: %s, %s", token, err.Error())
+			continue
+		}
+
+		if claimed {
+			source.owned[token] = true
+			source.startServer(info)
+		}
+	}
+}
+
+// release without locking, stops the local session and removes its lease
+func (source *QpClusterManager) release(db QpDataClusterInterface, token string, node string, cause string) {
+	delete(source.owned, token)
+	source.stopServer(token, cause)
+
+	err := db.Release(token, node)
+	if err != nil {
+		log.Errorf("cluster, error on releasing lease: %s, %s", token, err.Error())
+	}
+}
+
+func (source *QpClusterManager) startServer(info *QpServer) {
+	server, ok := WhatsappService.GetServer(info.Token)
+	if !ok {
+		var err error
+		server, err = WhatsappService.AppendNewServer(info)
+		if err != nil {
+			log.Errorf("cluster, error on appending claimed server: %s, %s", info.Token, err.Error())
+			return
+		}
+	}
+
+	state := server.GetStatus()
+	if state == whatsapp.UnPrepared || IsValidToStart(state) {
+		log.Infof("cluster, starting claimed server: %s, on %s state", info.Token, state)
+		go server.Initialize()
+	}
+
+	// campaigns of the session were running on the previous owner node
+	go CampaignManager.Resume(info.Token)
+}
+
+func (source *QpClusterManager) stopServer(token string, cause string) {
+	server, ok := WhatsappService.GetServer(token)
+	if ok {
+		err := server.Stop(cause)
+		if err != nil {
+			log.Errorf("cluster, error on stopping server: %s, %s", token, err.Error())
+		}
+	}
+}
+
+// Acquire claims a session for this node, used when it is paired here
+func (source *QpClusterManager) Acquire(token string) bool {
+	if !source.Enabled() {
+		return true
+	}
+
+	db, err := source.getDB()
+	if err != nil {
+		return false
+	}
+
+	now := time.Now().UTC()
+	expires := now.Add(environment.Settings.Cluster.GetLeaseDuration())
+
+	source.mutex.Lock()
+	defer source.mutex.Unlock()
+
+	claimed, err := db.Claim(token, environment.Settings.Cluster.NodeId, expires, now)
+	if err != nil {
+		log.Errorf("cluster, error on acquiring lease: %s, %s", token, err.Error())
+		return false
+	}
+
+	if claimed {
+		source.owned[token] = true
+	}
+	return claimed
+}
+
+// IsOwner returns true if the session is served by this node, always true outside cluster mode
+func (source *QpClusterManager) IsOwner(token string) bool {
+	if !source.Enabled() {
+		return true
+	}
+
+	source.mutex.Lock()
+	defer source.mutex.Unlock()
+
+	for owned := range source.owned {
+		if strings.EqualFold(owned, token) {
+			return true
+		}
+	}
+	return false
+}
+
+// GetOwner returns the alive node owning the session, nil if not leased
+func (source *QpClusterManager) GetOwner(token string) (*QpClusterNode, error) {
+	db, err := source.getDB()
+	if err != nil {
+		return nil, err
+	}
+
+	now := time.Now().UTC()
+	lease, err := db.FindLease(token)
+	if err != nil || !lease.IsValid(now) {
+		return nil, err
+	}
+
+	node, err := db.FindNode(lease.Node)
+	if err != nil || !node.IsAlive(now) {
+		return nil, err
+	}
+
+	return node, nil
+}
@@ -0,0 +1,15 @@
+package models
+
+import "time"
+
+// Cluster node, alive while heartbeats keep expiration in the future
+type QpClusterNode struct {
+	Id      string    `db:"id" json:"id"`
+	Url     string    `db:"url" json:"url,omitempty"`
+	Expires time.Time `db:"expires" json:"expires"`
+}
+
+// IsAlive returns true if the last heartbeat is still valid
+func (source *QpClusterNode) IsAlive(now time.Time) bool {
+	return source != nil && source.Expires.After(now)
+}
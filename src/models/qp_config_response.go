@@ -0,0 +1,16 @@
+package models
+
+import (
+	"time"
+
+	environment "github.com/nocodeleaks/quepasa/environment"
+)
+
+type QpConfigResponse struct {
+	QpResponse
+	File       string                              `json:"file,omitempty"`
+	Loaded     *time.Time                          `json:"loaded,omitempty"`
+	Reloadable []string                            `json:"reloadable,omitempty"`
+	Settings   map[string]interface{}              `json:"settings,omitempty"`
+	Servers    map[string]environment.ConfigServer `json:"servers,omitempty"`
+}
@@ -0,0 +1,110 @@
+package models
+
+import (
+	"fmt"
+
+	environment "github.com/nocodeleaks/quepasa/environment"
+	library "github.com/nocodeleaks/quepasa/library"
+	whatsapp "github.com/nocodeleaks/quepasa/whatsapp"
+	whatsmeow "github.com/nocodeleaks/quepasa/whatsmeow"
+)
+
+// ApplyConfigServer applies the config file overrides of a server, if any.
+// Options and devel are saved, dispatching entries added or updated, never removed.
+func ApplyConfigServer(server *QpWhatsappServer) (err error) {
+	config, ok := environment.ConfigFile.GetServers()[server.Token]
+	if !ok {
+		return
+	}
+
+	logentry := server.GetLogger()
+	changed := false
+
+	options := []struct {
+		current *whatsapp.WhatsappBoolean
+		value   whatsapp.WhatsappBoolean
+	}{
+		{&server.Groups, config.Groups},
+		{&server.Broadcasts, config.Broadcasts},
+		{&server.ReadReceipts, config.ReadReceipts},
+		{&server.Calls, config.Calls},
+		{&server.Transcription, config.Transcription},
+	}
+
+	for _, option := range options {
+		if option.value != whatsapp.UnSetBooleanType && *option.current != option.value {
+			*option.current = option.value
+			changed = true
+		}
+	}
+
+	if config.Devel != nil && server.Devel != *config.Devel {
+		server.Devel = *config.Devel
+		changed = true
+	}
+
+	if changed {
+		err = server.Save(fmt.Sprintf("config file: %v, devel: %v", server.WhatsappOptions, server.Devel))
+		if err != nil {
+			return
+		}
+	}
+
+	for _, item := range config.Dispatching {
+		dispatching := &QpDispatching{
+			WhatsappOptions:  item.WhatsappOptions,
+			ConnectionString: item.ConnectionString,
+			Type:             item.GetType(),
+			ForwardInternal:  item.ForwardInternal,
+			TrackId:          item.TrackId,
+			Extra:            item.Extra,
+			Wid:              server.Wid,
+		}
+
+		current := server.GetDispatchingByType(dispatching.ConnectionString, dispatching.Type)
+		if current != nil && current.WhatsappOptions == dispatching.WhatsappOptions &&
+			current.ForwardInternal == dispatching.ForwardInternal && current.TrackId == dispatching.TrackId &&
+			library.ToJson(current.Extra) == library.ToJson(dispatching.Extra) {
+			continue
+		}
+
+		_, err = server.DispatchingAddOrUpdate(dispatching)
+		if err != nil {
+			return fmt.Errorf("dispatching (%s): %w", library.RedactURL(dispatching.ConnectionString), err)
+		}
+
+		logentry.Infof("config file, %s updated: %s", dispatching.Type, library.RedactURL(dispatching.ConnectionString))
+	}
+
+	return
+}
+
+// OnConfigReload applies the reloaded overrides and presence to running servers
+func OnConfigReload(changed []string) {
+	presence := ""
+	for _, path := range changed {
+		if path == "whatsapp.presence" {
+			presence = environment.Settings.GetPresence()
+			whatsapp.Options.Presence = presence
+			if whatsmeow.WhatsmeowService != nil {
+				whatsmeow.WhatsmeowService.Options.Presence = presence
+			}
+		}
+	}
+
+	if WhatsappService == nil {
+		return
+	}
+
+	for _, server := range WhatsappService.GetServers() {
+		if err := ApplyConfigServer(server); err != nil {
+			server.GetLogger().Errorf("config file, overrides not applied: %s", err.Error())
+		}
+
+		if len(presence) > 0 {
+			if conn, ok := server.GetConnection().(*whatsmeow.WhatsmeowConnection); ok {
+				conn.UpdatePresence(presence)
+			}
+		}
+	}
+}
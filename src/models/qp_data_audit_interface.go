@@ -0,0 +1,10 @@
+package models
+
+import "time"
+
+type QpDataAuditInterface interface {
+	Add(element *QpAuditEntry) error
+	Find(filter *QpAuditFilter) ([]*QpAuditEntry, error)
+	Purge(before time.Time) (int64, error)
+	HashTokens(hash func(string) string) (int64, error)
+}
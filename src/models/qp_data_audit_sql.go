@@ -0,0 +1,109 @@
+package models
+
+import (
+	"strings"
+	"time"
+
+	"github.com/jmoiron/sqlx"
+	library "github.com/nocodeleaks/quepasa/library"
+)
+
+type QpDataAuditSql struct {
+	db *sqlx.DB
+}
+
+func (source QpDataAuditSql) Add(element *QpAuditEntry) error {
+	query := `INSERT INTO audit (timestamp, actortype, actor, action, token, server, summary, success, result, source)
+	VALUES (:timestamp, :actortype, :actor, :action, :token, :server, :summary, :success, :result, :source)`
+	result, err := source.db.NamedExec(query, element)
+	if err != nil {
+		return err
+	}
+
+	element.Id, _ = result.LastInsertId()
+	return nil
+}
+
+// Find returns entries matching the filter, newest first
+func (source QpDataAuditSql) Find(filter *QpAuditFilter) ([]*QpAuditEntry, error) {
+	conditions := []string{}
+	args := []interface{}{}
+
+	if len(filter.ActorType) > 0 {
+		conditions = append(conditions, "actortype = ?")
+		args = append(args, filter.ActorType)
+	}
+
+	if len(filter.Actor) > 0 {
+		conditions = append(conditions, "actor = ?")
+		args = append(args, filter.Actor)
+	}
+
+	if len(filter.Action) > 0 {
+		conditions = append(conditions, "action LIKE ?")
+		args = append(args, "%"+filter.Action+"%")
+	}
+
+	if len(filter.Server) > 0 {
+		conditions = append(conditions, "server = ?")
+		args = append(args, filter.Server)
+	}
+
+	if filter.Success != nil {
+		conditions = append(conditions, "success = ?")
+		args = append(args, *filter.Success)
+	}
+
+	if filter.From != nil {
+		conditions = append(conditions, "timestamp >= ?")
+		args = append(args, filter.From.UTC())
+	}
+
+	if filter.To != nil {
+		conditions = append(conditions, "timestamp < ?")
+		args = append(args, filter.To.UTC())
+	}
+
+	query := "SELECT * FROM audit"
+	if len(conditions) > 0 {
+		query += " WHERE " + strings.Join(conditions, " AND ")
+	}
+
+	query += " ORDER BY id DESC LIMIT ? OFFSET ?"
+	args = append(args, filter.Limit, filter.Offset)
+
+	result := []*QpAuditEntry{}
+	err := source.db.Select(&result, query, args...)
+	return result, err
+}
+
+// Purge removes entries older than the given time
+func (source QpDataAuditSql) Purge(before time.Time) (int64, error) {
+	result, err := source.db.Exec("DELETE FROM audit WHERE timestamp < ?", before.UTC())
+	if err != nil {
+		return 0, err
+	}
+
+	return result.RowsAffected()
+}
+
+// HashTokens fills the server hash and masks the token of entries recorded with the full token.
+// Entries already masked without hash are left as is, only visible with the master key.
+func (source QpDataAuditSql) HashTokens(hash func(string) string) (count int64, err error) {
+	tokens := []string{}
+	err = source.db.Select(&tokens, "SELECT DISTINCT token FROM audit WHERE server = '' AND token <> '' AND token NOT LIKE '%...%' AND replace(token, '*', '') <> ''")
+	if err != nil {
+		return
+	}
+
+	for _, token := range tokens {
+		result, err := source.db.Exec("UPDATE audit SET server = ?, token = ? WHERE server = '' AND token = ?", hash(token), library.MaskToken(token), token)
+		if err != nil {
+			return count, err
+		}
+
+		affected, _ := result.RowsAffected()
+		count += affected
+	}
+	return
+}
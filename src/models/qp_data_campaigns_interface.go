@@ -0,0 +1,18 @@
+package models
+
+type QpDataCampaignsInterface interface {
+	Find(id string) (*QpCampaign, error)
+	FindAll(context string) ([]*QpCampaign, error)
+	FindByStatus(status string) ([]*QpCampaign, error)
+	Add(element *QpCampaign) error
+	Update(element *QpCampaign) error
+	Delete(id string) error
+
+	AddRecipients(campaign string, recipients []*QpCampaignRecipient) (affected uint, err error)
+	FindRecipients(campaign string, status string) ([]*QpCampaignRecipient, error)
+	FindRecipientByMessageId(messageid string) (*QpCampaignRecipient, error)
+	FindAwaitingReceipts() ([]*QpCampaignRecipient, error)
+	ClaimRecipient(element *QpCampaignRecipient) (bool, error)
+	UpdateRecipient(element *QpCampaignRecipient) error
+	CountRecipients(campaign string) (map[string]uint, error)
+}
@@ -0,0 +1,172 @@
+package models
+
+import (
+	"github.com/jmoiron/sqlx"
+)
+
+type QpDataCampaignsSql struct {
+	db *sqlx.DB
+}
+
+// Find returns nil without error if not found
+func (source QpDataCampaignsSql) Find(id string) (response *QpCampaign, err error) {
+	var result []QpCampaign
+	err = source.db.Select(&result, "SELECT * FROM campaigns WHERE id = ?", id)
+	if err != nil {
+		return
+	}
+
+	for _, element := range result {
+		response = &element
+		break
+	}
+
+	return
+}
+
+func (source QpDataCampaignsSql) FindAll(context string) ([]*QpCampaign, error) {
+	result := []*QpCampaign{}
+	err := source.db.Select(&result, "SELECT * FROM campaigns WHERE context = ? ORDER BY timestamp DESC", context)
+	return result, err
+}
+
+func (source QpDataCampaignsSql) FindByStatus(status string) ([]*QpCampaign, error) {
+	result := []*QpCampaign{}
+	err := source.db.Select(&result, "SELECT * FROM campaigns WHERE status = ?", status)
+	return result, err
+}
+
+func (source QpDataCampaignsSql) Add(element *QpCampaign) error {
+	query := `INSERT INTO campaigns (id, context, name, template, url, content, filename, delay, status, error, timestamp, updated) VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?)`
+	_, err := source.db.Exec(query, element.Id, element.Context, element.Name, element.Template, element.Url, element.Content, element.FileName, element.Delay, element.Status, element.Error, element.Timestamp, element.Updated)
+	return err
+}
+
+func (source QpDataCampaignsSql) Update(element *QpCampaign) error {
+	query := `UPDATE campaigns SET name = ?, template = ?, url = ?, content = ?, filename = ?, delay = ?, status = ?, error = ?, updated = ? WHERE id = ?`
+	_, err := source.db.Exec(query, element.Name, element.Template, element.Url, element.Content, element.FileName, element.Delay, element.Status, element.Error, element.Updated, element.Id)
+	return err
+}
+
+// Delete removes the campaign and all its recipients
+func (source QpDataCampaignsSql) Delete(id string) error {
+	_, err := source.db.Exec(`DELETE FROM campaign_recipients WHERE campaign = ?`, id)
+	if err != nil {
+		return err
+	}
+
+	_, err = source.db.Exec(`DELETE FROM campaigns WHERE id = ?`, id)
+	return err
+}
+
+// AddRecipients ignores recipients already present on campaign
+func (source QpDataCampaignsSql) AddRecipients(campaign string, recipients []*QpCampaignRecipient) (affected uint, err error) {
+	tx, err := source.db.Beginx()
+	if err != nil {
+		return
+	}
+
+	query := `INSERT OR IGNORE INTO campaign_recipients (campaign, chatid, variables, status, messageid, error, timestamp) VALUES (?, ?, ?, ?, ?, ?, ?)`
+	for _, element := range recipients {
+		element.Campaign = campaign
+		result, execErr := tx.Exec(query, element.Campaign, element.ChatId, element.GetExtraText(), element.Status, element.MessageId, element.Error, element.Timestamp)
+		if execErr != nil {
+			tx.Rollback()
+			return 0, execErr
+		}
+
+		if rows, _ := result.RowsAffected(); rows > 0 {
+			affected++
+		}
+	}
+
+	err = tx.Commit()
+	return
+}
+
+// FindRecipients of a campaign, filtered by status if not empty
+func (source QpDataCampaignsSql) FindRecipients(campaign string, status string) ([]*QpCampaignRecipient, error) {
+	result := []*QpCampaignRecipient{}
+
+	var err error
+	if len(status) > 0 {
+		err = source.db.Select(&result, "SELECT * FROM campaign_recipients WHERE campaign = ? AND status = ? ORDER BY rowid", campaign, status)
+	} else {
+		err = source.db.Select(&result, "SELECT * FROM campaign_recipients WHERE campaign = ? ORDER BY rowid", campaign)
+	}
+
+	for _, element := range result {
+		element.ParseExtra()
+	}
+	return result, err
+}
+
+// FindRecipientByMessageId returns nil without error if not found
+func (source QpDataCampaignsSql) FindRecipientByMessageId(messageid string) (response *QpCampaignRecipient, err error) {
+	var result []QpCampaignRecipient
+	err = source.db.Select(&result, "SELECT * FROM campaign_recipients WHERE messageid = ?", messageid)
+	if err != nil {
+		return
+	}
+
+	for _, element := range result {
+		element.ParseExtra()
+		response = &element
+		break
+	}
+
+	return
+}
+
+// FindAwaitingReceipts returns sent or delivered recipients, that can still be updated by receipts
+func (source QpDataCampaignsSql) FindAwaitingReceipts() ([]*QpCampaignRecipient, error) {
+	result := []*QpCampaignRecipient{}
+	err := source.db.Select(&result, "SELECT * FROM campaign_recipients WHERE status IN (?, ?)", QpCampaignRecipientSent, QpCampaignRecipientDelivered)
+	return result, err
+}
+
+// ClaimRecipient moves a queued recipient to sending, false if already claimed or not queued anymore
+func (source QpDataCampaignsSql) ClaimRecipient(element *QpCampaignRecipient) (bool, error) {
+	query := `UPDATE campaign_recipients SET status = ?, timestamp = ? WHERE campaign = ? AND chatid = ? AND status = ?`
+	result, err := source.db.Exec(query, QpCampaignRecipientSending, element.Timestamp, element.Campaign, element.ChatId, QpCampaignRecipientQueued)
+	if err != nil {
+		return false, err
+	}
+
+	affected, err := result.RowsAffected()
+	if err != nil {
+		return false, err
+	}
+
+	if affected == 0 {
+		return false, nil
+	}
+
+	element.Status = QpCampaignRecipientSending
+	return true, nil
+}
+
+func (source QpDataCampaignsSql) UpdateRecipient(element *QpCampaignRecipient) error {
+	query := `UPDATE campaign_recipients SET status = ?, messageid = ?, error = ?, timestamp = ? WHERE campaign = ? AND chatid = ?`
+	_, err := source.db.Exec(query, element.Status, element.MessageId, element.Error, element.Timestamp, element.Campaign, element.ChatId)
+	return err
+}
+
+// CountRecipients returns the number of recipients per status
+func (source QpDataCampaignsSql) CountRecipients(campaign string) (map[string]uint, error) {
+	rows := []struct {
+		Status string `db:"status"`
+		Count  uint   `db:"count"`
+	}{}
+
+	err := source.db.Select(&rows, "SELECT status, COUNT(*) AS count FROM campaign_recipients WHERE campaign = ? GROUP BY status", campaign)
+	if err != nil {
+		return nil, err
+	}
+
+	result := map[string]uint{}
+	for _, row := range rows {
+		result[row.Status] = row.Count
+	}
+	return result, nil
+}
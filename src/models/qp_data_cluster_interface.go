@@ -0,0 +1,15 @@
+package models
+
+import "time"
+
+type QpDataClusterInterface interface {
+	Heartbeat(element *QpClusterNode) error
+	FindNode(id string) (*QpClusterNode, error)
+	FindNodes(now time.Time) ([]*QpClusterNode, error)
+	RemoveNode(id string) error
+
+	Claim(token string, node string, expires time.Time, now time.Time) (bool, error)
+	Release(token string, node string) error
+	FindLease(token string) (*QpClusterLease, error)
+	FindLeases() ([]*QpClusterLease, error)
+}
@@ -0,0 +1,92 @@
+package models
+
+import (
+	"time"
+
+	"github.com/jmoiron/sqlx"
+)
+
+type QpDataClusterSql struct {
+	db *sqlx.DB
+}
+
+func (source QpDataClusterSql) Heartbeat(element *QpClusterNode) error {
+	query := `INSERT OR REPLACE INTO cluster_nodes (id, url, expires) VALUES (?, ?, ?)`
+	_, err := source.db.Exec(query, element.Id, element.Url, element.Expires)
+	return err
+}
+
+// FindNode returns nil without error if not found
+func (source QpDataClusterSql) FindNode(id string) (response *QpClusterNode, err error) {
+	var result []QpClusterNode
+	err = source.db.Select(&result, "SELECT * FROM cluster_nodes WHERE id = ?", id)
+	if err != nil {
+		return
+	}
+
+	for _, element := range result {
+		response = &element
+		break
+	}
+
+	return
+}
+
+// FindNodes returns alive nodes, ordered by id
+func (source QpDataClusterSql) FindNodes(now time.Time) ([]*QpClusterNode, error) {
+	result := []*QpClusterNode{}
+	err := source.db.Select(&result, "SELECT * FROM cluster_nodes WHERE expires > ? ORDER BY id", now)
+	return result, err
+}
+
+// RemoveNode removes the node and releases all of its leases
+func (source QpDataClusterSql) RemoveNode(id string) error {
+	_, err := source.db.Exec("DELETE FROM cluster_leases WHERE node = ?", id)
+	if err != nil {
+		return err
+	}
+
+	_, err = source.db.Exec("DELETE FROM cluster_nodes WHERE id = ?", id)
+	return err
+}
+
+// Claim creates or renews a lease atomically, succeeds only if free, expired or already owned by the node
+func (source QpDataClusterSql) Claim(token string, node string, expires time.Time, now time.Time) (bool, error) {
+	query := `INSERT INTO cluster_leases (token, node, expires) VALUES (?, ?, ?)
+	ON CONFLICT (token) DO UPDATE SET node = excluded.node, expires = excluded.expires
+	WHERE cluster_leases.node = excluded.node OR cluster_leases.expires <= ?`
+	result, err := source.db.Exec(query, token, node, expires, now)
+	if err != nil {
+		return false, err
+	}
+
+	affected, err := result.RowsAffected()
+	return affected > 0, err
+}
+
+func (source QpDataClusterSql) Release(token string, node string) error {
+	_, err := source.db.Exec("DELETE FROM cluster_leases WHERE token = ? AND node = ?", token, node)
+	return err
+}
+
+// FindLease returns nil without error if not found
+func (source QpDataClusterSql) FindLease(token string) (response *QpClusterLease, err error) {
+	var result []QpClusterLease
+	err = source.db.Select(&result, "SELECT * FROM cluster_leases WHERE token = ?", token)
+	if err != nil {
+		return
+	}
+
+	for _, element := range result {
+		response = &element
+		break
+	}
+
+	return
+}
+
+func (source QpDataClusterSql) FindLeases() ([]*QpClusterLease, error) {
+	result := []*QpClusterLease{}
+	err := source.db.Select(&result, "SELECT * FROM cluster_leases")
+	return result, err
+}
@@ -0,0 +1,9 @@
+package models
+
+type QpDataPollsInterface interface {
+	Find(context string, id string) (*QpPoll, error)
+	Add(element *QpPoll) error
+
+	SetVote(element *QpPollVote) error
+	FindVotes(context string, poll string) ([]*QpPollVote, error)
+}
@@ -0,0 +1,53 @@
+package models
+
+import (
+	"github.com/jmoiron/sqlx"
+)
+
+type QpDataPollsSql struct {
+	db *sqlx.DB
+}
+
+// Find returns nil without error if not found
+func (source QpDataPollsSql) Find(context string, id string) (response *QpPoll, err error) {
+	var result []QpPoll
+	err = source.db.Select(&result, "SELECT * FROM polls WHERE context = ? AND id = ?", context, id)
+	if err != nil {
+		return
+	}
+
+	for _, element := range result {
+		response = &element
+		response.ParseExtra()
+		break
+	}
+
+	return
+}
+
+// Add ignores polls already registered, same message may be handled more than once
+func (source QpDataPollsSql) Add(element *QpPoll) error {
+	query := `INSERT OR IGNORE INTO polls (context, id, chatid, question, options, selections, timestamp) VALUES (?, ?, ?, ?, ?, ?, ?)`
+	_, err := source.db.Exec(query, element.Context, element.Id, element.ChatId, element.Question, element.GetExtraText(), element.Selections, element.Timestamp)
+	return err
+}
+
+// SetVote replaces the previous vote of the same voter, unless it is newer (votes may arrive out of order)
+func (source QpDataPollsSql) SetVote(element *QpPollVote) error {
+	query := `INSERT INTO poll_votes (context, poll, voter, options, timestamp) VALUES (?, ?, ?, ?, ?)
+	ON CONFLICT (context, poll, voter) DO UPDATE SET options = excluded.options, timestamp = excluded.timestamp
+	WHERE excluded.timestamp >= poll_votes.timestamp`
+
+	// utc, so stored timestamps compare in order
+	_, err := source.db.Exec(query, element.Context, element.Poll, element.Voter, element.GetExtraText(), element.Timestamp.UTC())
+	return err
+}
+
+func (source QpDataPollsSql) FindVotes(context string, poll string) ([]*QpPollVote, error) {
+	result := []*QpPollVote{}
+	err := source.db.Select(&result, "SELECT * FROM poll_votes WHERE context = ? AND poll = ? ORDER BY timestamp", context, poll)
+	for _, element := range result {
+		element.ParseExtra()
+	}
+	return result, err
+}
@@ -0,0 +1,8 @@
+package models
+
+type QpDataSenderRoutesInterface interface {
+	Find(chatid string) (*QpSenderRoute, error)
+	FindAll() ([]*QpSenderRoute, error)
+	Set(element *QpSenderRoute) error
+	Remove(chatid string) error
+}
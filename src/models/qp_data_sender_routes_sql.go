@@ -0,0 +1,44 @@
+package models
+
+import (
+	"github.com/jmoiron/sqlx"
+)
+
+type QpDataSenderRoutesSql struct {
+	db *sqlx.DB
+}
+
+// Find returns nil without error if not found
+func (source QpDataSenderRoutesSql) Find(chatid string) (response *QpSenderRoute, err error) {
+	var result []QpSenderRoute
+	err = source.db.Select(&result, "SELECT * FROM sender_routes WHERE chatid = ?", chatid)
+	if err != nil {
+		return
+	}
+
+	for _, element := range result {
+		response = &element
+		break
+	}
+
+	return
+}
+
+func (source QpDataSenderRoutesSql) FindAll() ([]*QpSenderRoute, error) {
+	result := []*QpSenderRoute{}
+	err := source.db.Select(&result, "SELECT * FROM sender_routes")
+	return result, err
+}
+
+// Set adds or replaces the route of a recipient
+func (source QpDataSenderRoutesSql) Set(element *QpSenderRoute) error {
+	query := `INSERT OR REPLACE INTO sender_routes (chatid, wid, timestamp) VALUES (?, ?, ?)`
+	_, err := source.db.Exec(query, element.ChatId, element.Wid, element.Timestamp)
+	return err
+}
+
+func (source QpDataSenderRoutesSql) Remove(chatid string) error {
+	query := `DELETE FROM sender_routes WHERE chatid = ?`
+	_, err := source.db.Exec(query, chatid)
+	return err
+}
@@ -0,0 +1,7 @@
+package models
+
+type QpDataSessionImportsInterface interface {
+	Claim(element *QpSessionImport) (bool, error)
+	Find(id string) (*QpSessionImport, error)
+	Remove(id string) error
+}
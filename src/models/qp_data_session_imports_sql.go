@@ -0,0 +1,42 @@
+package models
+
+import (
+	"github.com/jmoiron/sqlx"
+)
+
+type QpDataSessionImportsSql struct {
+	db *sqlx.DB
+}
+
+// Claim records the archive atomically, false if it was imported already
+func (source QpDataSessionImportsSql) Claim(element *QpSessionImport) (bool, error) {
+	query := `INSERT OR IGNORE INTO session_imports (id, token, timestamp) VALUES (?, ?, ?)`
+	result, err := source.db.Exec(query, element.Id, element.Token, element.Timestamp)
+	if err != nil {
+		return false, err
+	}
+
+	affected, err := result.RowsAffected()
+	return affected > 0, err
+}
+
+// Find returns nil without error if not found
+func (source QpDataSessionImportsSql) Find(id string) (response *QpSessionImport, err error) {
+	var result []QpSessionImport
+	err = source.db.Select(&result, "SELECT * FROM session_imports WHERE id = ?", id)
+	if err != nil {
+		return
+	}
+
+	for _, element := range result {
+		response = &element
+		break
+	}
+
+	return
+}
+
+func (source QpDataSessionImportsSql) Remove(id string) error {
+	_, err := source.db.Exec("DELETE FROM session_imports WHERE id = ?", id)
+	return err
+}
@@ -126,3 +126,91 @@ func (source QpDataUserSql) UpdatePassword(username string, password string) (er
 
 	return
 }
+
+func (source QpDataUserSql) FindAll() (result []*QpUser, err error) {
+	result = []*QpUser{}
+	err = source.db.Select(&result, "SELECT * FROM users ORDER BY username")
+	return
+}
+
+// Add creates a user with the given role, regardless of the account setup option
+func (source QpDataUserSql) Add(username string, password string, role string) (result *QpUser, err error) {
+	hashed, err := bcrypt.GenerateFromPassword([]byte(password), 10)
+	if err != nil {
+		return
+	}
+
+	user := &QpUser{
+		Username: username,
+		Password: string(hashed),
+		Role:     role,
+	}
+
+	query := `INSERT INTO users (username, password, role) VALUES (:username, :password, :role)`
+	_, err = source.db.NamedExec(query, user)
+	if err != nil {
+		return
+	}
+
+	result = user
+	return
+}
+
+// Update saves role, disabled and lockout state, password is updated by UpdatePassword
+func (source QpDataUserSql) Update(user *QpUser) (err error) {
+	query := `UPDATE users SET role = :role, disabled = :disabled, failures = :failures, locked = :locked WHERE username = :username`
+	result, err := source.db.NamedExec(query, user)
+	if err != nil {
+		return
+	}
+
+	affected, err := result.RowsAffected()
+	if err != nil {
+		return
+	}
+
+	if affected == 0 {
+		err = fmt.Errorf("user (%s) not found for update", user.Username)
+	}
+
+	return
+}
+
+func (source QpDataUserSql) Delete(username string) (err error) {
+	query := `DELETE FROM users WHERE username = ?`
+	result, err := source.db.Exec(query, username)
+	if err != nil {
+		return
+	}
+
+	affected, err := result.RowsAffected()
+	if err != nil {
+		return
+	}
+
+	if affected == 0 {
+		err = fmt.Errorf("user (%s) not found for delete", username)
+	}
+
+	return
+}
+
+// SetIdentity links the user to an identity provider subject
+func (source QpDataUserSql) SetIdentity(username string, issuer string, subject string) (err error) {
+	query := `UPDATE users SET issuer = ?, subject = ? WHERE username = ?`
+	result, err := source.db.Exec(query, issuer, subject, username)
+	if err != nil {
+		return
+	}
+
+	affected, err := result.RowsAffected()
+	if err != nil {
+		return
+	}
+
+	if affected == 0 {
+		err = fmt.Errorf("user (%s) not found for identity", username)
+	}
+
+	return
+}
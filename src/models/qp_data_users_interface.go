@@ -7,4 +7,10 @@ type QpDataUsersInterface interface {
 	Check(string, string) (*QpUser, error)
 	Create(username string, password string) (*QpUser, error)
 	UpdatePassword(username string, password string) error
+
+	FindAll() ([]*QpUser, error)
+	Add(username string, password string, role string) (*QpUser, error)
+	Update(*QpUser) error
+	SetIdentity(username string, issuer string, subject string) error
+	Delete(username string) error
 }
@@ -27,6 +27,13 @@ type QpDatabase struct {
 	Users       QpDataUsersInterface
 	Servers     QpDataServersInterface
 	Dispatching QpDataDispatchingInterface
+
+	SenderRoutes QpDataSenderRoutesInterface
+	Campaigns    QpDataCampaignsInterface
+	Polls        QpDataPollsInterface
+	Cluster      QpDataClusterInterface
+	Audit        QpDataAuditInterface
+	Imports      QpDataSessionImportsInterface
 }
 
 var (
@@ -70,13 +77,25 @@ func GetDatabase() *QpDatabase {
 	var iusers = QpDataUserSql{db}
 	var iservers = QpDataServerSql{db}
 	var idispatching = QpDataServerDispatchingSql{db}
+	var isenderroutes = QpDataSenderRoutesSql{db}
+	var icampaigns = QpDataCampaignsSql{db}
+	var ipolls = QpDataPollsSql{db}
+	var icluster = QpDataClusterSql{db}
+	var iaudit = QpDataAuditSql{db}
+	var iimports = QpDataSessionImportsSql{db}
 
 	return &QpDatabase{
 		dbParameters,
 		db,
 		iusers,
 		iservers,
-		idispatching}
+		idispatching,
+		isenderroutes,
+		icampaigns,
+		ipolls,
+		icluster,
+		iaudit,
+		iimports}
 }
 
 // MigrateToLatest updates the database to the latest schema
@@ -13,6 +13,7 @@ import (
 	environment "github.com/nocodeleaks/quepasa/environment"
 	library "github.com/nocodeleaks/quepasa/library"
 	rabbitmq "github.com/nocodeleaks/quepasa/rabbitmq"
+	telemetry "github.com/nocodeleaks/quepasa/telemetry"
 	whatsapp "github.com/nocodeleaks/quepasa/whatsapp"
 	log "github.com/sirupsen/logrus"
 )
@@ -163,6 +164,9 @@ func (source *QpDispatching) Dispatch(message *whatsapp.WhatsappMessage) (err er
 func (source *QpDispatching) PostWebhook(message *whatsapp.WhatsappMessage) (err error) {
 	startTime := time.Now()
 
+	ctx, span := telemetry.StartMessageSpan("webhook.post", message, telemetry.SpanKindClient)
+	defer func() { telemetry.EndSpan(span, err) }()
+
 	// updating log
 	logentry := source.LogWithField(LogFields.MessageId, message.Id)
 	logentry.Infof("posting webhook")
@@ -188,9 +192,10 @@ func (source *QpDispatching) PostWebhook(message *whatsapp.WhatsappMessage) (err
 	req.Header.Set("User-Agent", "Quepasa")
 	req.Header.Set("X-QUEPASA-WID", source.Wid)
 	req.Header.Set("Content-Type", "application/json")
+	telemetry.InjectHeaders(ctx, req.Header)
 
 	client := &http.Client{}
-	timeout := time.Duration(environment.Settings.API.WebhookTimeout) * time.Millisecond
+	timeout := environment.Settings.GetWebhookTimeout()
 	client.Timeout = timeout
 	resp, err := client.Do(req)
 
@@ -204,6 +209,7 @@ func (source *QpDispatching) PostWebhook(message *whatsapp.WhatsappMessage) (err
 	var statusCode int
 	if resp != nil {
 		statusCode = resp.StatusCode
+		span.SetAttributes(telemetry.HTTPStatusCode(statusCode))
 		defer resp.Body.Close()
 	}
 
@@ -253,6 +259,9 @@ func (source *QpDispatching) PostWebhook(message *whatsapp.WhatsappMessage) (err
 func (source *QpDispatching) PublishRabbitMQ(message *whatsapp.WhatsappMessage) (err error) {
 	startTime := time.Now()
 
+	ctx, span := telemetry.StartMessageSpan("rabbitmq.publish", message, telemetry.SpanKindProducer)
+	defer func() { telemetry.EndSpan(span, err) }()
+
 	// updating log
 	var messageIdForLog string
 	if message != nil {
@@ -317,7 +326,7 @@ func (source *QpDispatching) PublishRabbitMQ(message *whatsapp.WhatsappMessage)
 
 	// Publish to QuePasa Exchange with routing key
 	// This will cache the message if connection is not ready
-	client.PublishQuePasaMessage(routingKey, payload)
+	client.PublishQuePasaMessageWithHeaders(routingKey, payload, telemetry.InjectMap(ctx))
 
 	// Check if connection is still ready after publish attempt
 	// If not ready, it means message was cached and we should mark as failure
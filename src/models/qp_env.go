@@ -7,6 +7,7 @@ import (
 	"strconv"
 	"strings" // Certifique-se de que "strings" está importado
 
+	environment "github.com/nocodeleaks/quepasa/environment"
 	library "github.com/nocodeleaks/quepasa/library"
 	whatsapp "github.com/nocodeleaks/quepasa/whatsapp"
 	"github.com/sirupsen/logrus"
@@ -177,14 +178,14 @@ func (*Environment) SynopsisLength() uint64 {
 	return getEnvOrDefaultUint64(ENV_SYNOPSISLENGTH, 50)
 }
 
-// CacheLength returns the maximum number of items for the cache. Defaults to 0 (no limit).
+// CacheLength returns the maximum number of items for the cache, reloadable from config file. Defaults to 0 (no limit).
 func (*Environment) CacheLength() uint64 {
-	return getEnvOrDefaultUint64(ENV_CACHELENGTH, 0)
+	return environment.Settings.GetCacheLength()
 }
 
-// CacheDays returns the maximum number of days for cached messages. Defaults to 0 (no limit).
+// CacheDays returns the maximum number of days for cached messages, reloadable from config file. Defaults to 0 (no limit).
 func (*Environment) CacheDays() uint64 {
-	return getEnvOrDefaultUint64(ENV_CACHEDAYS, 0)
+	return environment.Settings.GetCacheDays()
 }
 
 // MasterKey returns the master key for super admin methods. Defaults to an empty string.
@@ -0,0 +1,162 @@
+package models
+
+import (
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	signalr "github.com/nocodeleaks/quepasa/signalr"
+	whatsapp "github.com/nocodeleaks/quepasa/whatsapp"
+	log "github.com/sirupsen/logrus"
+)
+
+// buffered events per subscriber, slow consumers lose newer events
+const QpEventStreamBufferSize = 256
+
+// QpEventStreamEvent is an event delivered to stream (sse / websocket) subscribers
+type QpEventStreamEvent struct {
+	Id      string                    `json:"id"`
+	Type    string                    `json:"type"` // message, receipt, connection, call, group
+	Payload *whatsapp.WhatsappMessage `json:"payload"`
+}
+
+func NewQpEventStreamEvent(payload *whatsapp.WhatsappMessage, from string) *QpEventStreamEvent {
+	return &QpEventStreamEvent{
+		Id:      payload.Id,
+		Type:    signalr.GetSignalRTarget(payload, from),
+		Payload: payload,
+	}
+}
+
+// QpEventStreamSubscriber receives events for a server token, optionally filtered
+type QpEventStreamSubscriber struct {
+	Token  string
+	Types  []string // empty for all types
+	ChatId string   // empty for all chats
+	Events chan *QpEventStreamEvent
+}
+
+// Match checks subscriber filters against the event
+func (source *QpEventStreamSubscriber) Match(event *QpEventStreamEvent) bool {
+	if len(source.Types) > 0 {
+		found := false
+		for _, item := range source.Types {
+			if strings.EqualFold(item, event.Type) {
+				found = true
+				break
+			}
+		}
+
+		if !found {
+			return false
+		}
+	}
+
+	if len(source.ChatId) > 0 && event.Payload != nil && event.Payload.Chat.Id != source.ChatId {
+		return false
+	}
+
+	return true
+}
+
+// QpEventStreamHub distributes triggered events to stream subscribers, thread safe
+type QpEventStreamHub struct {
+	mutex       sync.RWMutex
+	subscribers map[string]map[*QpEventStreamSubscriber]bool // token => subscribers
+}
+
+var EventStreamHub = &QpEventStreamHub{
+	subscribers: map[string]map[*QpEventStreamSubscriber]bool{},
+}
+
+func (source *QpEventStreamHub) Subscribe(token string, types []string, chatid string) *QpEventStreamSubscriber {
+	subscriber := &QpEventStreamSubscriber{
+		Token:  token,
+		Types:  types,
+		ChatId: chatid,
+		Events: make(chan *QpEventStreamEvent, QpEventStreamBufferSize),
+	}
+
+	source.mutex.Lock()
+	defer source.mutex.Unlock()
+
+	if _, ok := source.subscribers[token]; !ok {
+		source.subscribers[token] = map[*QpEventStreamSubscriber]bool{}
+	}
+	source.subscribers[token][subscriber] = true
+	return subscriber
+}
+
+func (source *QpEventStreamHub) Unsubscribe(subscriber *QpEventStreamSubscriber) {
+	source.mutex.Lock()
+	defer source.mutex.Unlock()
+
+	if subscribers, ok := source.subscribers[subscriber.Token]; ok {
+		delete(subscribers, subscriber)
+		if len(subscribers) == 0 {
+			delete(source.subscribers, subscriber.Token)
+		}
+	}
+}
+
+// GetSubscribersCount returns the number of active subscribers for a token
+func (source *QpEventStreamHub) GetSubscribersCount(token string) int {
+	source.mutex.RLock()
+	defer source.mutex.RUnlock()
+	return len(source.subscribers[token])
+}
+
+// Publish delivers the payload to matching subscribers, never blocks
+func (source *QpEventStreamHub) Publish(token string, payload *whatsapp.WhatsappMessage, from string) {
+	if source == nil || payload == nil {
+		return
+	}
+
+	source.mutex.RLock()
+	defer source.mutex.RUnlock()
+
+	subscribers := source.subscribers[token]
+	if len(subscribers) == 0 {
+		return
+	}
+
+	event := NewQpEventStreamEvent(payload, from)
+	for subscriber := range subscribers {
+		if !subscriber.Match(event) {
+			continue
+		}
+
+		select {
+		case subscriber.Events <- event:
+		default:
+			log.Warnf("event stream subscriber buffer full, dropping event: %s", event.Id)
+		}
+	}
+}
+
+// GetMessagesAfter returns cached messages newer than the message with the given id, ordered asc
+// Used to resume streams with Last-Event-ID
+func GetMessagesAfter(server *QpWhatsappServer, id string) (messages []*whatsapp.WhatsappMessage, err error) {
+	last, err := server.Handler.GetById(id)
+	if err != nil {
+		return
+	}
+
+	var ordered []whatsapp.WhatsappMessage
+	for _, item := range server.Handler.GetByTime(last.Timestamp.Add(-time.Nanosecond)) {
+		ordered = append(ordered, *item)
+	}
+	sort.Sort(whatsapp.WhatsappOrderedMessages(ordered))
+
+	found := false
+	for i := range ordered {
+		if found {
+			messages = append(messages, &ordered[i])
+		} else if strings.EqualFold(ordered[i].Id, last.Id) {
+			found = true
+		}
+	}
+
+	return
+}
@@ -2,4 +2,5 @@ package models
 
 type QPFormLoginData struct {
 	PageTitle string
+	OIDC      bool // single sign-on is available
 }
@@ -0,0 +1,10 @@
+package models
+
+type QPFormUsersData struct {
+	PageTitle      string
+	ErrorMessage   string
+	SuccessMessage string
+	User           QpUser
+	Users          []*QpUser
+	Roles          []string
+}
@@ -5,4 +5,5 @@ type QPFormWebHooksData struct {
 	ErrorMessage string
 	Server       *QpWhatsappServer
 	Webhooks     []*QpWebhook
+	ReadOnly     bool // viewers, no token and no changes
 }
@@ -0,0 +1,28 @@
+package models
+
+import (
+	"slices"
+	"strings"
+)
+
+// maximum target chats on a single forward request
+const QpForwardMaxChats = 50
+
+// Request to forward a cached message to one or more chats
+type QpForwardRequest struct {
+	MessageId string   `json:"messageid"`
+	ChatId    string   `json:"chatid,omitempty"`  // single target
+	ChatIds   []string `json:"chatids,omitempty"` // multiple targets
+	TrackId   string   `json:"trackid,omitempty"`
+}
+
+// GetChatIds returns all distinct non empty targets
+func (source *QpForwardRequest) GetChatIds() (chatids []string) {
+	for _, chatid := range append([]string{source.ChatId}, source.ChatIds...) {
+		chatid = strings.TrimSpace(chatid)
+		if len(chatid) > 0 && !slices.Contains(chatids, chatid) {
+			chatids = append(chatids, chatid)
+		}
+	}
+	return
+}
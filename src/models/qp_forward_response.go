@@ -0,0 +1,6 @@
+package models
+
+type QpForwardResponse struct {
+	QpResponse
+	Results []QpForwardResult `json:"results,omitempty"`
+}
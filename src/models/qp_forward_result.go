@@ -0,0 +1,11 @@
+package models
+
+import "time"
+
+// Result of forwarding a message to a single chat
+type QpForwardResult struct {
+	ChatId    string     `json:"chatid"`
+	Id        string     `json:"id,omitempty"`        // new message id, empty on error
+	Timestamp *time.Time `json:"timestamp,omitempty"` // server time of the new message
+	Error     string     `json:"error,omitempty"`
+}
@@ -0,0 +1,11 @@
+package models
+
+import (
+	whatsapp "github.com/nocodeleaks/quepasa/whatsapp"
+)
+
+type QpLiveLocationResponse struct {
+	QpResponse
+	ChatId    string                          `json:"chatid,omitempty"`
+	Locations []whatsapp.WhatsappLiveLocation `json:"locations,omitempty"`
+}
@@ -0,0 +1,132 @@
+package models
+
+import (
+	"sync"
+	"time"
+
+	whatsapp "github.com/nocodeleaks/quepasa/whatsapp"
+)
+
+// interval between sweeps of ended sharings, on updates
+const QpLiveLocationSweepInterval = time.Hour
+
+// qpLiveLocationKey identifies who is sharing on a chat, one active sharing each
+type qpLiveLocationKey struct {
+	chat        string
+	participant string
+}
+
+// QpLiveLocationTracker keeps the latest position of each live location sharing, by sharing message id, thread safe
+type QpLiveLocationTracker struct {
+	mutex    sync.Mutex
+	sessions map[string]*whatsapp.WhatsappLiveLocation // sharing message id => latest position
+	active   map[qpLiveLocationKey]string              // chat and participant => sharing message id
+	swept    time.Time
+}
+
+func NewQpLiveLocationTracker() *QpLiveLocationTracker {
+	return &QpLiveLocationTracker{
+		sessions: map[string]*whatsapp.WhatsappLiveLocation{},
+		active:   map[qpLiveLocationKey]string{},
+	}
+}
+
+// getParticipant returns who is sharing, chat id on direct messages
+func (source *QpLiveLocationTracker) getParticipant(msg *whatsapp.WhatsappMessage) string {
+	if msg.FromMe {
+		return "me"
+	}
+
+	if msg.Participant != nil && len(msg.Participant.Id) > 0 {
+		return msg.Participant.Id
+	}
+
+	return msg.Chat.Id
+}
+
+// Update stores the message position as the latest of its sharing
+// Returns true if it is an update of a sharing already tracked, origin and updates counter are filled.
+// Updates carry the time offset since the sharing start, messages without it start a new sharing.
+func (source *QpLiveLocationTracker) Update(msg *whatsapp.WhatsappMessage) bool {
+	if msg == nil || msg.LiveLocation == nil {
+		return false
+	}
+
+	source.mutex.Lock()
+	defer source.mutex.Unlock()
+
+	if time.Since(source.swept) > QpLiveLocationSweepInterval {
+		source.sweep()
+	}
+
+	position := msg.LiveLocation
+	position.Participant = source.getParticipant(msg)
+	key := qpLiveLocationKey{msg.Chat.Id, position.Participant}
+
+	origin := msg.Id
+	if _, ok := source.sessions[origin]; !ok && position.TimeOffset > 0 {
+		origin = source.active[key]
+	}
+
+	previous, ok := source.sessions[origin]
+	if !ok || previous.IsExpired() {
+
+		// a new sharing replaces the previous one of the same participant
+		delete(source.sessions, source.active[key])
+
+		position.Origin = msg.Id
+		position.Updates = 0
+
+		latest := *position
+		source.sessions[msg.Id] = &latest
+		source.active[key] = msg.Id
+		return false
+	}
+
+	position.Origin = previous.Origin
+	position.Updates = previous.Updates + 1
+
+	// out of order updates are dispatched, but do not replace the latest position
+	if position.Sequence > 0 && position.Sequence < previous.Sequence {
+		position.Updates = previous.Updates
+		return true
+	}
+
+	latest := *position
+	source.sessions[origin] = &latest
+	return true
+}
+
+// sweep removes ended sharings of every chat, without locking, replaced ones are removed on replace
+func (source *QpLiveLocationTracker) sweep() {
+	for key, origin := range source.active {
+		if position, ok := source.sessions[origin]; !ok || position.IsExpired() {
+			delete(source.sessions, origin)
+			delete(source.active, key)
+		}
+	}
+
+	source.swept = time.Now()
+}
+
+// GetLatest returns the latest positions of active sharings on a chat
+func (source *QpLiveLocationTracker) GetLatest(chatId string) (positions []whatsapp.WhatsappLiveLocation) {
+	source.mutex.Lock()
+	defer source.mutex.Unlock()
+
+	for key, origin := range source.active {
+		if key.chat != chatId {
+			continue
+		}
+
+		position, ok := source.sessions[origin]
+		if !ok || position.IsExpired() {
+			delete(source.sessions, origin)
+			delete(source.active, key)
+			continue
+		}
+		positions = append(positions, *position)
+	}
+
+	return
+}
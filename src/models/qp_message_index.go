@@ -0,0 +1,259 @@
+package models
+
+import (
+	"math"
+	"strings"
+	"sync"
+	"unicode"
+
+	whatsapp "github.com/nocodeleaks/quepasa/whatsapp"
+)
+
+// minimum term length to be indexed, shorter terms are ignored on index and queries
+const QpMessageIndexMinTermLength = 2
+
+// QpMessageIndex is an in memory inverted index over cached messages, thread safe
+// Entries follow the message cache lifecycle, removed on cleanups (expired or over length) and when not found on cache
+type QpMessageIndex struct {
+	mutex sync.RWMutex
+
+	terms     map[string]map[string]uint32 // term => message id => term frequency
+	chats     map[string]map[string]bool   // chat id => message ids
+	documents map[string][]string          // message id => indexed terms
+	lengths   map[string]int               // message id => total of terms, used on relevance
+	owners    map[string]string            // message id => chat id
+}
+
+func (source *QpMessageIndex) ensure() {
+	if source.documents == nil {
+		source.terms = map[string]map[string]uint32{}
+		source.chats = map[string]map[string]bool{}
+		source.documents = map[string][]string{}
+		source.lengths = map[string]int{}
+		source.owners = map[string]string{}
+	}
+}
+
+// GetIndexableText returns all searchable text of a message
+func GetIndexableText(msg *whatsapp.WhatsappMessage) string {
+	parts := []string{msg.Text}
+
+	if msg.Attachment != nil && len(msg.Attachment.FileName) > 0 {
+		parts = append(parts, msg.Attachment.FileName)
+	}
+
+	if msg.Poll != nil {
+		parts = append(parts, msg.Poll.Question)
+		parts = append(parts, msg.Poll.Options...)
+	}
+
+	if msg.Location != nil {
+		parts = append(parts, msg.Location.Name, msg.Location.Address)
+	}
+
+	if msg.Contact != nil {
+		parts = append(parts, msg.Contact.Name, msg.Contact.Phone)
+	}
+
+	if msg.Transcription != nil {
+		parts = append(parts, msg.Transcription.Text)
+	}
+
+	return strings.Join(parts, " ")
+}
+
+// Tokenize splits text into lower case terms of letters and digits
+func Tokenize(text string) (terms []string) {
+	fields := strings.FieldsFunc(strings.ToLower(text), func(r rune) bool {
+		return !unicode.IsLetter(r) && !unicode.IsDigit(r)
+	})
+
+	for _, field := range fields {
+		if len([]rune(field)) >= QpMessageIndexMinTermLength {
+			terms = append(terms, field)
+		}
+	}
+	return
+}
+
+// Add indexes (or reindexes) a message
+func (source *QpMessageIndex) Add(msg *whatsapp.WhatsappMessage) {
+	if msg == nil || len(msg.Id) == 0 {
+		return
+	}
+
+	id := strings.ToUpper(msg.Id)
+	terms := Tokenize(GetIndexableText(msg))
+
+	source.mutex.Lock()
+	defer source.mutex.Unlock()
+
+	source.ensure()
+	source.remove(id)
+
+	frequencies := map[string]uint32{}
+	for _, term := range terms {
+		frequencies[term]++
+	}
+
+	unique := make([]string, 0, len(frequencies))
+	for term, frequency := range frequencies {
+		postings, ok := source.terms[term]
+		if !ok {
+			postings = map[string]uint32{}
+			source.terms[term] = postings
+		}
+		postings[id] = frequency
+		unique = append(unique, term)
+	}
+
+	source.documents[id] = unique
+	source.lengths[id] = len(terms)
+
+	chatid := msg.Chat.Id
+	if _, ok := source.chats[chatid]; !ok {
+		source.chats[chatid] = map[string]bool{}
+	}
+	source.chats[chatid][id] = true
+	source.owners[id] = chatid
+}
+
+// Remove a message from index
+func (source *QpMessageIndex) Remove(id string) {
+	source.mutex.Lock()
+	defer source.mutex.Unlock()
+
+	source.ensure()
+	source.remove(strings.ToUpper(id))
+}
+
+// remove without locking, id must be normalized
+func (source *QpMessageIndex) remove(id string) {
+	terms, ok := source.documents[id]
+	if !ok {
+		return
+	}
+
+	for _, term := range terms {
+		if postings, ok := source.terms[term]; ok {
+			delete(postings, id)
+			if len(postings) == 0 {
+				delete(source.terms, term)
+			}
+		}
+	}
+
+	chatid := source.owners[id]
+	if ids, ok := source.chats[chatid]; ok {
+		delete(ids, id)
+		if len(ids) == 0 {
+			delete(source.chats, chatid)
+		}
+	}
+
+	delete(source.documents, id)
+	delete(source.lengths, id)
+	delete(source.owners, id)
+}
+
+// HasChat returns true if the chat has indexed messages
+func (source *QpMessageIndex) HasChat(chatid string) bool {
+	source.mutex.RLock()
+	defer source.mutex.RUnlock()
+
+	_, ok := source.chats[chatid]
+	return ok
+}
+
+// GetChatMessageIds returns all indexed message ids of a chat
+func (source *QpMessageIndex) GetChatMessageIds(chatid string) (ids []string) {
+	source.mutex.RLock()
+	defer source.mutex.RUnlock()
+
+	for id := range source.chats[chatid] {
+		ids = append(ids, id)
+	}
+	return
+}
+
+// Count of indexed messages
+func (source *QpMessageIndex) Count() int {
+	source.mutex.RLock()
+	defer source.mutex.RUnlock()
+	return len(source.documents)
+}
+
+// Search returns message ids containing all query terms, with a relevance score, and the idf of each query term.
+// Idfs pinned from a previous page keep scores comparable while messages are indexed, nil computes them.
+// Empty query returns all ids (of chat if set) with zero score
+func (source *QpMessageIndex) Search(query string, chatid string, pinned []float64) (results map[string]float64, idfs []float64) {
+	source.mutex.RLock()
+	defer source.mutex.RUnlock()
+
+	results = map[string]float64{}
+	if source.documents == nil {
+		return
+	}
+
+	terms := Tokenize(query)
+	if len(terms) == 0 {
+		if len(chatid) > 0 {
+			for id := range source.chats[chatid] {
+				results[id] = 0
+			}
+		} else {
+			for id := range source.documents {
+				results[id] = 0
+			}
+		}
+		return
+	}
+
+	// starting from the rarest term, reduces intersections
+	var rarest map[string]uint32
+	for _, term := range terms {
+		postings := source.terms[term]
+		if len(postings) == 0 {
+			return // a term without matches, nothing found
+		}
+
+		if rarest == nil || len(postings) < len(rarest) {
+			rarest = postings
+		}
+	}
+
+	idfs = pinned
+	if len(idfs) != len(terms) {
+		total := float64(len(source.documents))
+		idfs = make([]float64, len(terms))
+		for index, term := range terms {
+			idfs[index] = 1.0 + math.Log(total/float64(len(source.terms[term])))
+		}
+	}
+
+	for id := range rarest {
+		if len(chatid) > 0 && !source.chats[chatid][id] {
+			continue
+		}
+
+		score := 0.0
+		matched := true
+		for index, term := range terms {
+			frequency, ok := source.terms[term][id]
+			if !ok {
+				matched = false
+				break
+			}
+
+			// tf-idf, normalized by message length
+			tf := float64(frequency) / float64(max(source.lengths[id], 1))
+			score += tf * idfs[index]
+		}
+
+		if matched {
+			results[id] = score
+		}
+	}
+
+	return
+}
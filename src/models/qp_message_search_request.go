@@ -0,0 +1,235 @@
+package models
+
+import (
+	"encoding/base64"
+	"fmt"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	whatsapp "github.com/nocodeleaks/quepasa/whatsapp"
+)
+
+const (
+	QpMessageSearchDefaultLimit = 50
+	QpMessageSearchMaxLimit     = 500
+)
+
+// Message search ordering
+const (
+	QpMessageSearchOrderRelevance = "relevance"
+	QpMessageSearchOrderTime      = "time"
+)
+
+// Request to search on cached messages, backed by the message index
+type QpMessageSearchRequest struct {
+	Query         string `json:"query,omitempty"`         // full text, all terms must match
+	ChatId        string `json:"chatid,omitempty"`        // chat filter
+	Participant   string `json:"participant,omitempty"`   // group participant filter
+	Type          string `json:"type,omitempty"`          // message type (text, image, audio, ...)
+	After         int64  `json:"after,omitempty"`         // unix timestamp, messages after
+	Before        int64  `json:"before,omitempty"`        // unix timestamp, messages before
+	FromMe        *bool  `json:"fromme,omitempty"`        // sent by this whatsapp
+	HasAttachment *bool  `json:"hasattachment,omitempty"` // messages with attachment
+	Order         string `json:"order,omitempty"`         // relevance (default with query) | time (newer first)
+	Limit         int    `json:"limit,omitempty"`         // page size
+	Cursor        string `json:"cursor,omitempty"`        // cursor from previous page
+}
+
+type qpMessageSearchHit struct {
+	score   float64
+	message *whatsapp.WhatsappMessage
+}
+
+// returns the effective ordering
+func (source *QpMessageSearchRequest) GetOrder() string {
+	if strings.EqualFold(source.Order, QpMessageSearchOrderTime) || len(Tokenize(source.Query)) == 0 {
+		return QpMessageSearchOrderTime
+	}
+	return QpMessageSearchOrderRelevance
+}
+
+// returns the effective page size
+func (source *QpMessageSearchRequest) GetLimit() int {
+	if source.Limit <= 0 {
+		return QpMessageSearchDefaultLimit
+	}
+	if source.Limit > QpMessageSearchMaxLimit {
+		return QpMessageSearchMaxLimit
+	}
+	return source.Limit
+}
+
+// Match checks the non text filters
+func (source *QpMessageSearchRequest) Match(msg *whatsapp.WhatsappMessage) bool {
+	if len(source.Participant) > 0 && msg.GetParticipantId() != source.Participant {
+		return false
+	}
+
+	if len(source.Type) > 0 && !strings.EqualFold(msg.Type.String(), source.Type) {
+		return false
+	}
+
+	if source.After > 0 && !msg.Timestamp.After(time.Unix(source.After, 0)) {
+		return false
+	}
+
+	if source.Before > 0 && !msg.Timestamp.Before(time.Unix(source.Before, 0)) {
+		return false
+	}
+
+	if source.FromMe != nil && msg.FromMe != *source.FromMe {
+		return false
+	}
+
+	if source.HasAttachment != nil && msg.HasAttachment() != *source.HasAttachment {
+		return false
+	}
+
+	return true
+}
+
+// Search the messages index and cache, returning a page of results
+func (source *QpMessageSearchRequest) Search(messages *QpWhatsappMessages) (response *QpMessageSearchResponse, err error) {
+	if len(source.ChatId) > 0 {
+		source.ChatId, err = whatsapp.FormatEndpoint(source.ChatId)
+		if err != nil {
+			return
+		}
+	}
+
+	if len(source.Participant) > 0 {
+		source.Participant, err = whatsapp.FormatEndpoint(source.Participant)
+		if err != nil {
+			return
+		}
+	}
+
+	var cursor *qpMessageSearchCursor
+	if len(source.Cursor) > 0 {
+		cursor, err = DecodeMessageSearchCursor(source.Cursor)
+		if err != nil {
+			return
+		}
+	}
+
+	// relevance pages keep the idfs of the first one, scores would change as messages are indexed
+	var pinned []float64
+	if cursor != nil {
+		pinned = cursor.idfs
+	}
+
+	order := source.GetOrder()
+	results, idfs := messages.index.Search(source.Query, source.ChatId, pinned)
+
+	var hits []qpMessageSearchHit
+	for id, score := range results {
+		msg, err := messages.GetById(id)
+		if err != nil {
+			// expired or removed from cache, also dropped from index
+			continue
+		}
+
+		if source.Match(msg) {
+			hits = append(hits, qpMessageSearchHit{score, msg})
+		}
+	}
+
+	sort.Slice(hits, func(i, j int) bool {
+		if order == QpMessageSearchOrderRelevance && hits[i].score != hits[j].score {
+			return hits[i].score > hits[j].score
+		}
+		return qpMessageSearchNewer(hits[i].message, hits[j].message)
+	})
+
+	response = &QpMessageSearchResponse{Total: len(hits)}
+
+	start := 0
+	if cursor != nil {
+		last := &whatsapp.WhatsappMessage{Id: cursor.id, Timestamp: time.Unix(0, cursor.timestamp)}
+		start = sort.Search(len(hits), func(i int) bool {
+			if order == QpMessageSearchOrderRelevance && hits[i].score != cursor.score {
+				return hits[i].score < cursor.score
+			}
+			return qpMessageSearchNewer(last, hits[i].message)
+		})
+	}
+
+	end := start + source.GetLimit()
+	if end > len(hits) {
+		end = len(hits)
+	}
+
+	for _, hit := range hits[start:end] {
+		response.Messages = append(response.Messages, *hit.message)
+	}
+
+	if end < len(hits) {
+		last := hits[end-1]
+		response.Cursor = EncodeMessageSearchCursor(last.score, last.message, idfs)
+	}
+
+	return
+}
+
+// newer first, then by id desc, stable ordering for cursors
+func qpMessageSearchNewer(a *whatsapp.WhatsappMessage, b *whatsapp.WhatsappMessage) bool {
+	if a.Timestamp.Equal(b.Timestamp) {
+		return a.Id > b.Id
+	}
+	return a.Timestamp.After(b.Timestamp)
+}
+
+// position of the last returned message, with the idfs of query terms pinned on the first page
+type qpMessageSearchCursor struct {
+	score     float64
+	timestamp int64
+	id        string
+	idfs      []float64
+}
+
+// cursor holds the position of last returned message: score, timestamp, idfs of query terms and id
+func EncodeMessageSearchCursor(score float64, msg *whatsapp.WhatsappMessage, idfs []float64) string {
+	values := make([]string, len(idfs))
+	for index, idf := range idfs {
+		values[index] = strconv.FormatFloat(idf, 'g', -1, 64)
+	}
+
+	raw := fmt.Sprintf("%s|%d|%s|%s", strconv.FormatFloat(score, 'g', -1, 64), msg.Timestamp.UnixNano(), strings.Join(values, ","), msg.Id)
+	return base64.RawURLEncoding.EncodeToString([]byte(raw))
+}
+
+func DecodeMessageSearchCursor(encoded string) (cursor *qpMessageSearchCursor, err error) {
+	raw, err := base64.RawURLEncoding.DecodeString(encoded)
+	if err != nil {
+		return nil, fmt.Errorf("invalid cursor: %s", err.Error())
+	}
+
+	parts := strings.SplitN(string(raw), "|", 4)
+	if len(parts) != 4 {
+		return nil, fmt.Errorf("invalid cursor format")
+	}
+
+	cursor = &qpMessageSearchCursor{id: parts[3]}
+	cursor.score, err = strconv.ParseFloat(parts[0], 64)
+	if err != nil {
+		return nil, fmt.Errorf("invalid cursor score: %s", err.Error())
+	}
+
+	cursor.timestamp, err = strconv.ParseInt(parts[1], 10, 64)
+	if err != nil {
+		return nil, fmt.Errorf("invalid cursor timestamp: %s", err.Error())
+	}
+
+	if len(parts[2]) > 0 {
+		for _, value := range strings.Split(parts[2], ",") {
+			idf, err := strconv.ParseFloat(value, 64)
+			if err != nil {
+				return nil, fmt.Errorf("invalid cursor idf: %s", err.Error())
+			}
+			cursor.idfs = append(cursor.idfs, idf)
+		}
+	}
+	return
+}
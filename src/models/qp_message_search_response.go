@@ -0,0 +1,12 @@
+package models
+
+import (
+	whatsapp "github.com/nocodeleaks/quepasa/whatsapp"
+)
+
+type QpMessageSearchResponse struct {
+	QpResponse
+	Total    int                        `json:"total"`            // total of matches, all pages
+	Cursor   string                     `json:"cursor,omitempty"` // next page cursor, empty on last page
+	Messages []whatsapp.WhatsappMessage `json:"messages,omitempty"`
+}
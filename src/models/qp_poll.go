@@ -0,0 +1,51 @@
+package models
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"time"
+)
+
+// Poll sent or received by a server, options are persisted to resolve votes
+type QpPoll struct {
+	Context    string    `db:"context" json:"-"`
+	Id         string    `db:"id" json:"id"`
+	ChatId     string    `db:"chatid" json:"chatid"`
+	Question   string    `db:"question" json:"question"`
+	Extra      []byte    `db:"options" json:"-"` // json serialized options
+	Options    []string  `db:"-" json:"options"`
+	Selections uint      `db:"selections" json:"selections"`
+	Timestamp  time.Time `db:"timestamp" json:"timestamp"`
+}
+
+// GetExtraText returns options serialized as json
+func (source *QpPoll) GetExtraText() string {
+	extraJson, err := json.Marshal(source.Options)
+	if err != nil {
+		return ""
+	}
+	return string(extraJson)
+}
+
+// ParseExtra fills options from json serialized column
+func (source *QpPoll) ParseExtra() {
+	if len(source.Extra) > 0 {
+		_ = json.Unmarshal(source.Extra, &source.Options)
+	}
+}
+
+// GetOptionsByHash maps sha256 (hex) of each option name to the option, as whatsapp encodes votes
+func (source *QpPoll) GetOptionsByHash() map[string]string {
+	hashes := map[string]string{}
+	for _, option := range source.Options {
+		hashes[GetPollOptionHash(option)] = option
+	}
+	return hashes
+}
+
+// GetPollOptionHash returns the sha256 (hex) of an option name
+func GetPollOptionHash(option string) string {
+	hash := sha256.Sum256([]byte(option))
+	return hex.EncodeToString(hash[:])
+}
@@ -0,0 +1,156 @@
+package models
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+
+	whatsapp "github.com/nocodeleaks/quepasa/whatsapp"
+	log "github.com/sirupsen/logrus"
+)
+
+var ErrPollNotFound error = errors.New("the requested poll was not found")
+
+// QpPollManager persists polls and votes, aggregating results
+type QpPollManager struct{}
+
+var PollManager = &QpPollManager{}
+
+func (source *QpPollManager) getDB() (QpDataPollsInterface, error) {
+	if WhatsappService == nil || WhatsappService.DB == nil || WhatsappService.DB.Polls == nil {
+		return nil, fmt.Errorf("polls database not ready")
+	}
+	return WhatsappService.DB.Polls, nil
+}
+
+// Track persists a poll sent or received, its options are needed to resolve votes
+func (source *QpPollManager) Track(token string, msg *whatsapp.WhatsappMessage) (*QpPoll, error) {
+	if msg == nil || msg.Poll == nil {
+		return nil, fmt.Errorf("message without poll")
+	}
+
+	db, err := source.getDB()
+	if err != nil {
+		return nil, err
+	}
+
+	timestamp := msg.Timestamp
+	if timestamp.IsZero() {
+		timestamp = time.Now().UTC()
+	}
+
+	selections := msg.Poll.Selections
+	if selections == 0 {
+		selections = 1
+	}
+
+	poll := &QpPoll{
+		Context:    token,
+		Id:         msg.Id,
+		ChatId:     msg.Chat.Id,
+		Question:   msg.Poll.Question,
+		Options:    msg.Poll.Options,
+		Selections: selections,
+		Timestamp:  timestamp,
+	}
+
+	return poll, db.Add(poll)
+}
+
+// Vote resolves selected options names of a decrypted vote and persists it as the voter latest vote
+// Cached poll message is used when the poll was not persisted, created before tracking
+func (source *QpPollManager) Vote(token string, msg *whatsapp.WhatsappMessage, cached *whatsapp.WhatsappMessage) error {
+	if msg == nil || msg.PollVote == nil {
+		return fmt.Errorf("message without poll vote")
+	}
+
+	db, err := source.getDB()
+	if err != nil {
+		return err
+	}
+
+	vote := msg.PollVote
+	poll, err := db.Find(token, vote.PollId)
+	if err != nil {
+		return err
+	}
+
+	if poll == nil && cached != nil && cached.Poll != nil {
+		poll, err = source.Track(token, cached)
+		if err != nil {
+			return err
+		}
+	}
+
+	if poll == nil {
+		return ErrPollNotFound
+	}
+
+	options := poll.GetOptionsByHash()
+	resolved := []string{}
+	for _, hash := range vote.Hashes {
+		if option, ok := options[hash]; ok {
+			resolved = append(resolved, option)
+		}
+	}
+
+	vote.Options = resolved
+	if len(resolved) == len(vote.Hashes) {
+		vote.Hashes = nil
+	}
+
+	// readable text for text only consumers
+	msg.Text = strings.Join(resolved, ", ")
+
+	timestamp := msg.Timestamp
+	if timestamp.IsZero() {
+		timestamp = time.Now().UTC()
+	}
+
+	return db.SetVote(&QpPollVote{
+		Context:   token,
+		Poll:      poll.Id,
+		Voter:     vote.Voter,
+		Options:   resolved,
+		Timestamp: timestamp,
+	})
+}
+
+// Handle persists polls and votes from messages sent or received, never fails the message flow
+func (source *QpPollManager) Handle(token string, msg *whatsapp.WhatsappMessage, cached *whatsapp.WhatsappMessage) {
+	var err error
+	if msg.PollVote != nil {
+		err = source.Vote(token, msg, cached)
+	} else if msg.Poll != nil {
+		_, err = source.Track(token, msg)
+	}
+
+	if err != nil {
+		log.Warnf("failed to persist poll from msg: %s, error: %s", msg.Id, err.Error())
+	}
+}
+
+// GetResults aggregates the latest vote of each voter by option
+func (source *QpPollManager) GetResults(token string, id string) (*QpPollResults, error) {
+	db, err := source.getDB()
+	if err != nil {
+		return nil, err
+	}
+
+	poll, err := db.Find(token, id)
+	if err != nil {
+		return nil, err
+	}
+
+	if poll == nil {
+		return nil, ErrPollNotFound
+	}
+
+	votes, err := db.FindVotes(token, id)
+	if err != nil {
+		return nil, err
+	}
+
+	return NewQpPollResults(poll, votes), nil
+}
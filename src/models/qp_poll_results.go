@@ -0,0 +1,42 @@
+package models
+
+// Poll with aggregated votes, only the latest vote of each voter counts
+type QpPollResults struct {
+	*QpPoll
+	Results []*QpPollOptionResult `json:"results"`
+	Voters  uint                  `json:"voters"` // voters with at least one selected option
+	Votes   []*QpPollVote         `json:"votes,omitempty"`
+}
+
+// Tally of a single poll option
+type QpPollOptionResult struct {
+	Option string   `json:"option"`
+	Count  uint     `json:"count"`
+	Voters []string `json:"voters,omitempty"`
+}
+
+func NewQpPollResults(poll *QpPoll, votes []*QpPollVote) *QpPollResults {
+	results := &QpPollResults{QpPoll: poll, Votes: votes}
+
+	tallies := map[string]*QpPollOptionResult{}
+	for _, option := range poll.Options {
+		tally := &QpPollOptionResult{Option: option}
+		tallies[option] = tally
+		results.Results = append(results.Results, tally)
+	}
+
+	for _, vote := range votes {
+		if len(vote.Options) > 0 {
+			results.Voters++
+		}
+
+		for _, option := range vote.Options {
+			if tally, ok := tallies[option]; ok {
+				tally.Count++
+				tally.Voters = append(tally.Voters, vote.Voter)
+			}
+		}
+	}
+
+	return results
+}
@@ -0,0 +1,6 @@
+package models
+
+type QpPollResultsResponse struct {
+	QpResponse
+	Poll *QpPollResults `json:"poll,omitempty"`
+}
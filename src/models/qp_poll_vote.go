@@ -0,0 +1,32 @@
+package models
+
+import (
+	"encoding/json"
+	"time"
+)
+
+// Latest vote of a voter on a poll
+type QpPollVote struct {
+	Context   string    `db:"context" json:"-"`
+	Poll      string    `db:"poll" json:"-"`
+	Voter     string    `db:"voter" json:"voter"`
+	Extra     []byte    `db:"options" json:"-"` // json serialized selected options
+	Options   []string  `db:"-" json:"options"`
+	Timestamp time.Time `db:"timestamp" json:"timestamp"`
+}
+
+// GetExtraText returns selected options serialized as json
+func (source *QpPollVote) GetExtraText() string {
+	extraJson, err := json.Marshal(source.Options)
+	if err != nil {
+		return ""
+	}
+	return string(extraJson)
+}
+
+// ParseExtra fills selected options from json serialized column
+func (source *QpPollVote) ParseExtra() {
+	if len(source.Extra) > 0 {
+		_ = json.Unmarshal(source.Extra, &source.Options)
+	}
+}
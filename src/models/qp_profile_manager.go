@@ -0,0 +1,90 @@
+package models
+
+import (
+	"fmt"
+
+	media "github.com/nocodeleaks/quepasa/media"
+	whatsapp "github.com/nocodeleaks/quepasa/whatsapp"
+)
+
+// Compile-time check to ensure QpProfileManager implements whatsapp.WhatsappProfileManagerInterface
+var _ whatsapp.WhatsappProfileManagerInterface = (*QpProfileManager)(nil)
+
+// QpProfileManager handles own profile operations for QpWhatsappServer
+// Implements whatsapp.WhatsappProfileManagerInterface interface
+type QpProfileManager struct {
+	*QpWhatsappServer // embedded server for direct access
+}
+
+// NewQpProfileManager creates a new QpProfileManager instance
+func NewQpProfileManager(server *QpWhatsappServer) *QpProfileManager {
+	return &QpProfileManager{
+		QpWhatsappServer: server,
+	}
+}
+
+// getProfileManager is a helper function to get the profile manager from connection
+func (pm *QpProfileManager) getProfileManager() (whatsapp.WhatsappProfileManagerInterface, error) {
+	conn, err := pm.GetValidConnection()
+	if err != nil {
+		return nil, fmt.Errorf("failed to get valid connection: %v", err)
+	}
+
+	profileManager := conn.GetProfileManager()
+	if profileManager == nil {
+		return nil, fmt.Errorf("profile manager not available")
+	}
+
+	return profileManager, nil
+}
+
+// GetProfile returns own push name, about text and profile picture info
+func (pm *QpProfileManager) GetProfile() (*whatsapp.WhatsappProfile, error) {
+	profileManager, err := pm.getProfileManager()
+	if err != nil {
+		return nil, err
+	}
+	return profileManager.GetProfile()
+}
+
+// SetPushName updates own display name
+func (pm *QpProfileManager) SetPushName(name string) error {
+	profileManager, err := pm.getProfileManager()
+	if err != nil {
+		return err
+	}
+	return profileManager.SetPushName(name)
+}
+
+// SetAbout updates own about/status text
+func (pm *QpProfileManager) SetAbout(text string) error {
+	profileManager, err := pm.getProfileManager()
+	if err != nil {
+		return err
+	}
+	return profileManager.SetAbout(text)
+}
+
+// SetProfilePicture crops to a square, resizes and updates own profile picture
+func (pm *QpProfileManager) SetProfilePicture(content []byte) (string, error) {
+	profileManager, err := pm.getProfileManager()
+	if err != nil {
+		return "", err
+	}
+
+	picture, err := media.ConvertToProfilePicture(content)
+	if err != nil {
+		return "", err
+	}
+
+	return profileManager.SetProfilePicture(picture)
+}
+
+// RemoveProfilePicture removes own profile picture
+func (pm *QpProfileManager) RemoveProfilePicture() error {
+	profileManager, err := pm.getProfileManager()
+	if err != nil {
+		return err
+	}
+	return profileManager.RemoveProfilePicture()
+}
@@ -0,0 +1,7 @@
+package models
+
+// Profile update Request Body, only passed fields are updated
+type QpProfilePatchRequest struct {
+	PushName *string `json:"pushname,omitempty" validate:"max=25"` // display name shown to contacts
+	About    *string `json:"about,omitempty" validate:"max=139"`   // about/status text, empty to clear
+}
@@ -0,0 +1,65 @@
+package models
+
+import (
+	"encoding/base64"
+	"fmt"
+	"io"
+	"net/http"
+	"strings"
+	"time"
+
+	media "github.com/nocodeleaks/quepasa/media"
+)
+
+// Profile picture Request Body, from url or BASE64 content
+type QpProfilePictureRequest struct {
+	Url     string `json:"url,omitempty"`
+	Content string `json:"content,omitempty"` // BASE64, data uri prefix is accepted
+}
+
+// GetContent downloads or decodes the picture
+func (source *QpProfilePictureRequest) GetContent() ([]byte, error) {
+	url := strings.TrimSpace(source.Url)
+	if len(url) > 0 {
+		client := &http.Client{Timeout: 30 * time.Second}
+		resp, err := client.Get(url)
+		if err != nil {
+			return nil, fmt.Errorf("failed to download image: %v", err)
+		}
+		defer resp.Body.Close()
+
+		if resp.StatusCode != http.StatusOK {
+			return nil, fmt.Errorf("failed to download image, server returned status: %s", resp.Status)
+		}
+
+		return ReadProfilePicture(resp.Body)
+	}
+
+	content := source.Content
+	if index := strings.Index(content, ";base64,"); index >= 0 {
+		content = content[index+len(";base64,"):]
+	}
+
+	if len(content) == 0 {
+		return nil, fmt.Errorf("url or content is required")
+	}
+
+	if len(content) > base64.StdEncoding.EncodedLen(media.ProfilePictureSourceMaxLength) {
+		return nil, fmt.Errorf("image too large, maximum: %d bytes", media.ProfilePictureSourceMaxLength)
+	}
+
+	return base64.StdEncoding.DecodeString(content)
+}
+
+// ReadProfilePicture reads a source image, up to the maximum length
+func ReadProfilePicture(reader io.Reader) ([]byte, error) {
+	content, err := io.ReadAll(io.LimitReader(reader, media.ProfilePictureSourceMaxLength+1))
+	if err != nil {
+		return nil, err
+	}
+
+	if len(content) > media.ProfilePictureSourceMaxLength {
+		return nil, fmt.Errorf("image too large, maximum: %d bytes", media.ProfilePictureSourceMaxLength)
+	}
+	return content, nil
+}
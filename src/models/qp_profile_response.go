@@ -0,0 +1,8 @@
+package models
+
+import whatsapp "github.com/nocodeleaks/quepasa/whatsapp"
+
+type QpProfileResponse struct {
+	QpResponse
+	Profile *whatsapp.WhatsappProfile `json:"profile,omitempty"`
+}
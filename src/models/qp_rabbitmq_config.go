@@ -7,6 +7,7 @@ import (
 
 	"github.com/nocodeleaks/quepasa/library"
 	rabbitmq "github.com/nocodeleaks/quepasa/rabbitmq"
+	telemetry "github.com/nocodeleaks/quepasa/telemetry"
 	whatsapp "github.com/nocodeleaks/quepasa/whatsapp"
 )
 
@@ -144,7 +145,7 @@ func (source *QpRabbitMQConfig) PublishMessage(message *whatsapp.WhatsappMessage
 
 		// Determine routing key based on message type
 		routingKey := source.DetermineRoutingKey(message)
-		client.PublishQuePasaMessage(routingKey, payload)
+		client.PublishQuePasaMessageWithHeaders(routingKey, payload, telemetry.InjectMap(message.TraceContext))
 
 		// Always increment RabbitMQ messages published counter
 		rabbitmq.MessagesPublished.Inc()
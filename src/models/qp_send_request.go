@@ -28,6 +28,15 @@ type QpSendRequest struct {
 	// Msg in reply of another ? Message ID
 	InReply string `json:"inreply,omitempty"`
 
+	// (Optional) Snapshot of the replied message, used when it is not cached anymore
+	Quoted *whatsapp.WhatsappQuotedMessage `json:"quoted,omitempty"`
+
+	// (Optional) Phones or jids to mention, besides @number tokens found on group texts
+	Mentions []string `json:"mentions,omitempty"`
+
+	// (Optional) Mention all participants, groups only
+	MentionAll bool `json:"mention_all,omitempty"`
+
 	// (Optional) Sugested filename on user download
 	FileName string `json:"filename,omitempty"`
 
@@ -48,6 +57,12 @@ type QpSendRequest struct {
 	Poll     *whatsapp.WhatsappPoll     `json:"poll,omitempty"`     // Poll if exists
 	Location *whatsapp.WhatsappLocation `json:"location,omitempty"` // Location if exists
 	Contact  *whatsapp.WhatsappContact  `json:"contact,omitempty"`  // Contact if exists
+
+	// (Optional) Structured buttons or list, attachment is used as header media
+	Interactive *whatsapp.WhatsappInteractive `json:"interactive,omitempty"`
+
+	// tracing context of the api request, send spans are children of it
+	TraceContext context.Context `json:"-"`
 }
 
 // get default log entry, never nil
@@ -108,10 +123,25 @@ func (source *QpSendRequest) ToWhatsappMessage() (msg *whatsapp.WhatsappMessage,
 		Chat:         chat,
 		FromMe:       true,
 		FromInternal: true,
+		TraceContext: source.TraceContext,
 	}
 
 	msg.Poll = source.Poll
 
+	if source.Interactive != nil {
+		err = source.Interactive.Validate()
+		if err != nil {
+			err = fmt.Errorf("invalid interactive: %s", err.Error())
+			return
+		}
+		msg.Interactive = source.Interactive
+	}
+
+	err = source.FillMentionsAndQuoted(msg)
+	if err != nil {
+		return
+	}
+
 	// Check if this is a contact message
 	if source.Contact != nil {
 		msg.Type = whatsapp.ContactMessageType
@@ -123,6 +153,7 @@ func (source *QpSendRequest) ToWhatsappMessage() (msg *whatsapp.WhatsappMessage,
 	// Check if this is a location message
 	if source.Location != nil {
 		msg.Type = whatsapp.LocationMessageType
+		msg.Location = source.Location
 		// Create attachment with location data
 		msg.Attachment = &whatsapp.WhatsappAttachment{
 			Latitude:  source.Location.Latitude,
@@ -148,6 +179,41 @@ func (source *QpSendRequest) ToWhatsappMessage() (msg *whatsapp.WhatsappMessage,
 	return
 }
 
+// FillMentionsAndQuoted validates and formats mentions and quoted snapshot into message
+func (source *QpSendRequest) FillMentionsAndQuoted(msg *whatsapp.WhatsappMessage) (err error) {
+	if source.MentionAll {
+		if !msg.FromGroup() {
+			return fmt.Errorf("mention_all is only available for groups")
+		}
+		msg.MentionAll = true
+	}
+
+	for _, mention := range source.Mentions {
+		formatted, err := whatsapp.FormatEndpoint(mention)
+		if err != nil {
+			return fmt.Errorf("invalid mention (%s): %s", mention, err.Error())
+		}
+		msg.Mentions = append(msg.Mentions, formatted)
+	}
+
+	if source.Quoted != nil {
+		if len(source.InReply) == 0 {
+			return fmt.Errorf("quoted requires inreply message id")
+		}
+
+		quoted := *source.Quoted
+		if len(quoted.Participant) > 0 {
+			quoted.Participant, err = whatsapp.FormatEndpoint(quoted.Participant)
+			if err != nil {
+				return fmt.Errorf("invalid quoted participant (%s): %s", source.Quoted.Participant, err.Error())
+			}
+		}
+		msg.Quoted = &quoted
+	}
+
+	return
+}
+
 func (source *QpSendRequest) ToWhatsappAttachment() (result QpToWhatsappAttachment) {
 	contentLength := len(source.Content)
 	if contentLength == 0 {
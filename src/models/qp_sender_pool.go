@@ -0,0 +1,191 @@
+package models
+
+import (
+	"errors"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	environment "github.com/nocodeleaks/quepasa/environment"
+	whatsapp "github.com/nocodeleaks/quepasa/whatsapp"
+	log "github.com/sirupsen/logrus"
+)
+
+var ErrSenderPoolUnavailable error = errors.New("no sender available on pool, servers are not ready, not verified or over daily quota")
+
+// QpSenderUsage tracks daily sends of a pool server
+type QpSenderUsage struct {
+	Wid      string    `json:"wid"`
+	Day      string    `json:"day"` // local date, counters reset when it changes
+	Count    uint32    `json:"count"`
+	Pending  uint32    `json:"pending"` // selected, not sent yet
+	LastUsed time.Time `json:"lastused,omitempty"`
+}
+
+// QpSenderPool distributes master key sends between ready servers, thread safe
+// Daily counters are kept in memory, a restart resets them
+type QpSenderPool struct {
+	mutex sync.Mutex
+	next  uint64                    // round robin position
+	usage map[string]*QpSenderUsage // wid => usage
+}
+
+var SenderPool = &QpSenderPool{
+	usage: map[string]*QpSenderUsage{},
+}
+
+// getUsage without locking, resets counters on a new day
+func (source *QpSenderPool) getUsage(wid string) *QpSenderUsage {
+	day := time.Now().Format("2006-01-02")
+
+	usage, ok := source.usage[wid]
+	if !ok {
+		usage = &QpSenderUsage{Wid: wid, Day: day}
+		source.usage[wid] = usage
+	} else if usage.Day != day {
+		usage.Day = day
+		usage.Count = 0
+	}
+
+	return usage
+}
+
+// getCandidates without locking, ready and verified servers under quota, ordered by wid
+// Ready servers over quota are returned as exhausted, by lower case wid
+func (source *QpSenderPool) getCandidates(quota uint32) (candidates []*QpWhatsappServer, exhausted map[string]bool) {
+	exhausted = map[string]bool{}
+	if WhatsappService == nil {
+		return
+	}
+
+	for _, server := range WhatsappService.GetServers() {
+		if server == nil || !server.Verified || server.GetStatus() != whatsapp.Ready {
+			continue
+		}
+
+		if quota > 0 {
+			usage := source.getUsage(server.GetWId())
+			if usage.Count+usage.Pending >= quota {
+				exhausted[strings.ToLower(server.GetWId())] = true
+				continue
+			}
+		}
+
+		candidates = append(candidates, server)
+	}
+
+	sort.Slice(candidates, func(i, j int) bool {
+		return candidates[i].GetWId() < candidates[j].GetWId()
+	})
+
+	return
+}
+
+// choose without locking, applies the selection strategy
+func (source *QpSenderPool) choose(candidates []*QpWhatsappServer, strategy string) *QpWhatsappServer {
+	if strings.EqualFold(strategy, environment.SenderPoolLeastRecentlyUsed) {
+		var chosen *QpWhatsappServer
+		var oldest time.Time
+		for _, server := range candidates {
+			used := source.getUsage(server.GetWId()).LastUsed
+			if chosen == nil || used.Before(oldest) {
+				chosen = server
+				oldest = used
+			}
+		}
+		return chosen
+	}
+
+	chosen := candidates[source.next%uint64(len(candidates))]
+	source.next++
+	return chosen
+}
+
+// getRoute returns the persisted sender wid of a recipient, empty if none
+func (source *QpSenderPool) getRoute(chatid string) string {
+	if WhatsappService == nil || WhatsappService.DB == nil || WhatsappService.DB.SenderRoutes == nil {
+		return ""
+	}
+
+	route, err := WhatsappService.DB.SenderRoutes.Find(chatid)
+	if err != nil {
+		log.Warnf("sender pool, error on finding route for: %s, %s", chatid, err.Error())
+		return ""
+	}
+
+	if route == nil {
+		return ""
+	}
+
+	return route.Wid
+}
+
+func (source *QpSenderPool) setRoute(chatid string, wid string) {
+	if WhatsappService == nil || WhatsappService.DB == nil || WhatsappService.DB.SenderRoutes == nil {
+		return
+	}
+
+	route := &QpSenderRoute{ChatId: chatid, Wid: wid, Timestamp: time.Now().UTC()}
+	err := WhatsappService.DB.SenderRoutes.Set(route)
+	if err != nil {
+		log.Warnf("sender pool, error on saving route for: %s, %s", chatid, err.Error())
+	}
+}
+
+// Select returns the server to send for a recipient, reserved until Release
+// Sticky routes are kept while its server is available, otherwise the recipient is routed again.
+// A sticky server over quota keeps its route, other server sends this message only.
+func (source *QpSenderPool) Select(chatid string) (server *QpWhatsappServer, err error) {
+	settings := environment.Settings.SenderPool
+
+	source.mutex.Lock()
+	defer source.mutex.Unlock()
+
+	candidates, exhausted := source.getCandidates(settings.DailyQuota)
+	if len(candidates) == 0 {
+		err = ErrSenderPoolUnavailable
+		return
+	}
+
+	route := ""
+	sticky := settings.Sticky && len(chatid) > 0
+	if sticky {
+		route = source.getRoute(chatid)
+		if len(route) > 0 {
+			for _, candidate := range candidates {
+				if strings.EqualFold(candidate.GetWId(), route) {
+					server = candidate
+					break
+				}
+			}
+		}
+	}
+
+	if server == nil {
+		server = source.choose(candidates, settings.Strategy)
+		if sticky && !exhausted[strings.ToLower(route)] {
+			source.setRoute(chatid, server.GetWId())
+		}
+	}
+
+	usage := source.getUsage(server.GetWId())
+	usage.Pending++
+	usage.LastUsed = time.Now()
+	return
+}
+
+// Release ends a send reserved by Select, counting it on daily quota only if sent
+func (source *QpSenderPool) Release(server *QpWhatsappServer, sent bool) {
+	source.mutex.Lock()
+	defer source.mutex.Unlock()
+
+	usage := source.getUsage(server.GetWId())
+	if usage.Pending > 0 {
+		usage.Pending--
+	}
+
+	if sent {
+		usage.Count++
+	}
+}
@@ -0,0 +1,10 @@
+package models
+
+import "time"
+
+// QpSenderRoute persists which pool server (wid) is assigned to a recipient
+type QpSenderRoute struct {
+	ChatId    string    `db:"chatid" json:"chatid"`
+	Wid       string    `db:"wid" json:"wid"`
+	Timestamp time.Time `db:"timestamp" json:"timestamp"`
+}
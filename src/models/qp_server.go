@@ -54,6 +54,11 @@ func (source *QpServer) GetLogger() *log.Entry {
 	return logentry
 }
 
+// GetMaskedToken keeps only the edges of the token, shown to users that can not use it
+func (source *QpServer) GetMaskedToken() string {
+	return library.MaskToken(source.Token)
+}
+
 func (source *QpServer) GetWId() string {
 	return source.Wid
 }
@@ -73,6 +78,16 @@ func (source *QpServer) GetLogLevel() log.Level {
 
 //#region VIEW TRICKS
 
+// used for view
+func (source QpServer) IsSetTranscription() bool {
+	return source.Transcription != whatsapp.UnSetBooleanType
+}
+
+// used for view
+func (source QpServer) GetTranscription() bool {
+	return source.Transcription.Boolean()
+}
+
 // used for view
 func (source QpServer) IsSetCalls() bool {
 	return source.Calls != whatsapp.UnSetBooleanType
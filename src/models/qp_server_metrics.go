@@ -0,0 +1,79 @@
+package models
+
+import (
+	"sort"
+	"time"
+
+	metrics "github.com/nocodeleaks/quepasa/metrics"
+	signalr "github.com/nocodeleaks/quepasa/signalr"
+	whatsapp "github.com/nocodeleaks/quepasa/whatsapp"
+)
+
+func init() {
+	metrics.RegisterCollectHook(CollectServerMetrics)
+}
+
+// CollectServerMetrics refreshes per server gauges from current state,
+// servers are labeled by wid only, tokens are credentials and metrics endpoint is not authenticated.
+// On cluster mode only servers owned by this node are reported, the others are reported by their owners.
+func CollectServerMetrics() {
+	if WhatsappService == nil {
+		return
+	}
+
+	var servers []*QpWhatsappServer
+	for _, server := range WhatsappService.GetServers() {
+		if server != nil && len(server.Wid) > 0 && ClusterManager.IsOwner(server.Token) {
+			servers = append(servers, server)
+		}
+	}
+
+	// stable order, so the cardinality guard keeps the same servers between scrapes
+	sort.Slice(servers, func(i, j int) bool { return servers[i].Wid < servers[j].Wid })
+
+	ServerReady.Reset()
+	ServerConnectionState.Reset()
+	ServerLastMessageAge.Reset()
+	ServerCacheSize.Reset()
+	ServerDispatchingFailures.Reset()
+	ServerSignalRConnections.Reset()
+
+	now := time.Now().UTC()
+	disconnected := 0
+	for _, server := range servers {
+		wid := server.Wid
+		state := server.GetStatus()
+
+		ready := 0.0
+		if state == whatsapp.Ready {
+			ready = 1
+		} else if server.Verified {
+			disconnected++
+		}
+
+		ServerReady.WithLabelValues(wid).Set(ready)
+		ServerConnectionState.WithLabelValues(wid, state.String()).Set(1)
+
+		if server.Timestamps.Message != nil {
+			ServerLastMessageAge.WithLabelValues(wid).Set(now.Sub(*server.Timestamps.Message).Seconds())
+		}
+
+		if server.Handler != nil {
+			ServerCacheSize.WithLabelValues(wid).Set(float64(server.Handler.Count()))
+		}
+
+		failing := 0
+		for _, dispatching := range server.QpDataDispatching.Dispatching {
+			if dispatching != nil && dispatching.Failure != nil {
+				failing++
+			}
+		}
+		ServerDispatchingFailures.WithLabelValues(wid).Set(float64(failing))
+
+		connections := signalr.SignalRHub.GetActiveConnections(server.Token)
+		ServerSignalRConnections.WithLabelValues(wid).Set(float64(len(connections)))
+	}
+
+	ServersDisconnected.Set(float64(disconnected))
+	SignalRConnections.Set(float64(signalr.SignalRHub.GetConnectionsCount()))
+}
@@ -0,0 +1,178 @@
+package models
+
+import (
+	"bytes"
+	"compress/gzip"
+	"crypto/aes"
+	"crypto/cipher"
+	"crypto/pbkdf2"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"time"
+
+	whatsmeow "github.com/nocodeleaks/quepasa/whatsmeow"
+)
+
+// archive layout: magic | salt | nonce | aes-256-gcm( gzip( json ) )
+const (
+	SessionArchiveVersion       = 1
+	SessionArchiveMagic         = "QPSESSION1"
+	SessionArchiveMinPassphrase = 8
+
+	sessionArchiveSaltSize   = 16
+	sessionArchiveIterations = 600000
+)
+
+var ErrSessionArchiveInvalid = errors.New("invalid session archive or wrong passphrase")
+
+// Exported session, device keys from whatsmeow store and QuePasa metadata
+type QpSessionArchive struct {
+	Version     int                         `json:"version"`
+	Id          string                      `json:"id"` // unique, handoff archives are imported once
+	Created     time.Time                   `json:"created"`
+	Node        string                      `json:"node,omitempty"` // instance that exported it
+	Handoff     bool                        `json:"handoff"`        // exporting instance stopped serving the session
+	Server      *QpServer                   `json:"server"`         // server record
+	Dispatching []*QpDispatching            `json:"dispatching"`    // webhooks and rabbitmq entries
+	Device      *whatsmeow.WhatsmeowSession `json:"device"`         // store rows
+}
+
+// ValidateSessionPassphrase checks the passphrase before anything is changed
+func ValidateSessionPassphrase(passphrase string) error {
+	if len(passphrase) < SessionArchiveMinPassphrase {
+		return fmt.Errorf("passphrase should have at least %d characters", SessionArchiveMinPassphrase)
+	}
+	return nil
+}
+
+func getSessionArchiveKey(passphrase string, salt []byte) ([]byte, error) {
+	if err := ValidateSessionPassphrase(passphrase); err != nil {
+		return nil, err
+	}
+
+	return pbkdf2.Key(sha256.New, passphrase, salt, sessionArchiveIterations, 32)
+}
+
+// Seal serializes, compresses and encrypts the archive with the passphrase
+func (source *QpSessionArchive) Seal(passphrase string) ([]byte, error) {
+	salt := make([]byte, sessionArchiveSaltSize)
+	_, err := rand.Read(salt)
+	if err != nil {
+		return nil, err
+	}
+
+	key, err := getSessionArchiveKey(passphrase, salt)
+	if err != nil {
+		return nil, err
+	}
+
+	var plain bytes.Buffer
+	writer := gzip.NewWriter(&plain)
+	err = json.NewEncoder(writer).Encode(source)
+	if err != nil {
+		return nil, err
+	}
+
+	err = writer.Close()
+	if err != nil {
+		return nil, err
+	}
+
+	aead, err := newSessionArchiveCipher(key)
+	if err != nil {
+		return nil, err
+	}
+
+	nonce := make([]byte, aead.NonceSize())
+	_, err = rand.Read(nonce)
+	if err != nil {
+		return nil, err
+	}
+
+	header := append([]byte(SessionArchiveMagic), salt...)
+	sealed := append(header, nonce...)
+
+	// header is authenticated as additional data
+	return aead.Seal(sealed, nonce, plain.Bytes(), header), nil
+}
+
+// OpenSessionArchive decrypts and parses an archive generated by Seal
+func OpenSessionArchive(data []byte, passphrase string) (*QpSessionArchive, error) {
+	headerSize := len(SessionArchiveMagic) + sessionArchiveSaltSize
+	if len(data) < headerSize || string(data[:len(SessionArchiveMagic)]) != SessionArchiveMagic {
+		return nil, ErrSessionArchiveInvalid
+	}
+
+	header := data[:headerSize]
+	key, err := getSessionArchiveKey(passphrase, header[len(SessionArchiveMagic):])
+	if err != nil {
+		return nil, err
+	}
+
+	aead, err := newSessionArchiveCipher(key)
+	if err != nil {
+		return nil, err
+	}
+
+	if len(data) < headerSize+aead.NonceSize() {
+		return nil, ErrSessionArchiveInvalid
+	}
+
+	nonce := data[headerSize : headerSize+aead.NonceSize()]
+	plain, err := aead.Open(nil, nonce, data[headerSize+aead.NonceSize():], header)
+	if err != nil {
+		return nil, ErrSessionArchiveInvalid
+	}
+
+	reader, err := gzip.NewReader(bytes.NewReader(plain))
+	if err != nil {
+		return nil, ErrSessionArchiveInvalid
+	}
+	defer reader.Close()
+
+	content, err := io.ReadAll(reader)
+	if err != nil {
+		return nil, ErrSessionArchiveInvalid
+	}
+
+	archive := &QpSessionArchive{}
+	err = json.Unmarshal(content, archive)
+	if err != nil {
+		return nil, fmt.Errorf("invalid session archive content: %s", err.Error())
+	}
+
+	return archive, archive.Validate()
+}
+
+func newSessionArchiveCipher(key []byte) (cipher.AEAD, error) {
+	block, err := aes.NewCipher(key)
+	if err != nil {
+		return nil, err
+	}
+	return cipher.NewGCM(block)
+}
+
+// Validate checks archive consistency, device rows are validated again on import
+func (source *QpSessionArchive) Validate() error {
+	if source.Version != SessionArchiveVersion {
+		return fmt.Errorf("unsupported session archive version: %d", source.Version)
+	}
+
+	if source.Handoff && len(source.Id) == 0 {
+		return fmt.Errorf("session archive without id")
+	}
+
+	if source.Server == nil || len(source.Server.Token) == 0 || len(source.Server.Wid) == 0 {
+		return fmt.Errorf("session archive without server information")
+	}
+
+	if source.Device == nil || source.Device.Jid != source.Server.Wid {
+		return fmt.Errorf("session archive device does not match server wid")
+	}
+
+	return source.Device.Validate()
+}
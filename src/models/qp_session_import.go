@@ -0,0 +1,10 @@
+package models
+
+import "time"
+
+// Handoff archive imported on this instance
+type QpSessionImport struct {
+	Id        string    `db:"id" json:"id"` // archive id
+	Token     string    `db:"token" json:"token"`
+	Timestamp time.Time `db:"timestamp" json:"timestamp"`
+}
@@ -0,0 +1,7 @@
+package models
+
+type QpSessionImportResponse struct {
+	QpResponse
+	Token string `json:"token,omitempty"`
+	Wid   string `json:"wid,omitempty"`
+}
@@ -0,0 +1,262 @@
+package models
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/google/uuid"
+	environment "github.com/nocodeleaks/quepasa/environment"
+	whatsmeow "github.com/nocodeleaks/quepasa/whatsmeow"
+)
+
+// ErrSessionConflict is returned when importing a session that may be running elsewhere
+var ErrSessionConflict = errors.New("session conflict")
+
+// ExportSession generates an encrypted archive of the session.
+// Unless backup, it is a handoff: the session is stopped, its device removed from store and marked unverified here,
+// so it never runs on both instances, not even after a restart.
+// Errors after the device was removed return the archive too, it is the only copy of the session.
+func ExportSession(ctx context.Context, server *QpWhatsappServer, passphrase string, backup bool) ([]byte, error) {
+	if whatsmeow.WhatsmeowService == nil {
+		return nil, fmt.Errorf("whatsmeow service not started")
+	}
+
+	if len(server.Wid) == 0 || !server.Verified {
+		return nil, fmt.Errorf("session not paired, nothing to export")
+	}
+
+	err := ValidateSessionPassphrase(passphrase)
+	if err != nil {
+		return nil, err
+	}
+
+	logentry := server.GetLogger()
+
+	// stopping before reading, store should not change after being exported
+	if !backup {
+		err = server.Stop("session export")
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	data, err := buildSessionArchive(ctx, server, passphrase, !backup)
+	if err != nil {
+		if !backup {
+			logentry.Warnf("session export failed, restarting: %s", err.Error())
+			go server.Initialize()
+		}
+		return nil, err
+	}
+
+	if !backup {
+		// dropping the connection, it holds the device state in memory
+		if server.connection != nil && !server.connection.IsInterfaceNil() {
+			server.connection.Dispose("session export")
+		}
+		server.connection = nil
+
+		err = whatsmeow.WhatsmeowService.DeleteSession(ctx, server.Wid)
+		if err != nil {
+			// device still here, serving it again, the archive is discarded
+			logentry.Warnf("session export failed, restarting: %s", err.Error())
+			go server.Initialize()
+			return nil, fmt.Errorf("error on removing exported device from store: %s", err.Error())
+		}
+
+		server.Verified = false
+		err = server.Save("session exported")
+		if err != nil {
+			return data, fmt.Errorf("session exported, but not marked as unverified: %s", err.Error())
+		}
+	}
+
+	logentry.Infof("session exported, handoff: %v", !backup)
+	return data, nil
+}
+
+func buildSessionArchive(ctx context.Context, server *QpWhatsappServer, passphrase string, handoff bool) ([]byte, error) {
+	device, err := whatsmeow.WhatsmeowService.ExportSession(ctx, server.Wid)
+	if err != nil {
+		return nil, err
+	}
+
+	dispatchings, err := WhatsappService.DB.Dispatching.FindAll(server.Token)
+	if err != nil {
+		return nil, err
+	}
+
+	info := *server.QpServer
+	info.LogEntry = nil
+
+	archive := &QpSessionArchive{
+		Version:     SessionArchiveVersion,
+		Id:          uuid.New().String(),
+		Created:     time.Now().UTC(),
+		Node:        environment.Settings.Cluster.NodeId,
+		Handoff:     handoff,
+		Server:      &info,
+		Dispatching: []*QpDispatching{},
+		Device:      device,
+	}
+
+	for _, element := range dispatchings {
+		if element.QpDispatching != nil {
+			archive.Dispatching = append(archive.Dispatching, element.QpDispatching)
+		}
+	}
+
+	return archive.Seal(passphrase)
+}
+
+// ImportSession restores an archive generated by ExportSession and starts the session.
+// Backup archives, handoff archives already imported or sessions active here are refused, unless forced
+func ImportSession(ctx context.Context, data []byte, passphrase string, user string, force bool) (server *QpWhatsappServer, err error) {
+	if whatsmeow.WhatsmeowService == nil || WhatsappService == nil {
+		return nil, fmt.Errorf("whatsapp service not started")
+	}
+
+	archive, err := OpenSessionArchive(data, passphrase)
+	if err != nil {
+		return nil, err
+	}
+
+	if !archive.Handoff && !force {
+		return nil, fmt.Errorf("%w: backup archive, the session may still be running on %s, stop it there and force the import", ErrSessionConflict, archive.Node)
+	}
+
+	info := archive.Server
+	info.Verified = true
+
+	// a handoff archive is imported once, a copy imported again would run the session twice
+	if archive.Handoff {
+		claimed, err := WhatsappService.DB.Imports.Claim(&QpSessionImport{Id: archive.Id, Token: info.Token, Timestamp: time.Now().UTC()})
+		if err != nil {
+			return nil, err
+		}
+
+		if !claimed && !force {
+			previous, _ := WhatsappService.DB.Imports.Find(archive.Id)
+			if previous != nil {
+				return nil, fmt.Errorf("%w: archive already imported at %v, on token: %s", ErrSessionConflict, previous.Timestamp, previous.Token)
+			}
+			return nil, fmt.Errorf("%w: archive already imported", ErrSessionConflict)
+		}
+
+		// releasing the archive if not imported
+		if claimed {
+			defer func() {
+				if err != nil {
+					WhatsappService.DB.Imports.Remove(archive.Id)
+				}
+			}()
+		}
+	}
+
+	// sessions of the same device already on this instance
+	var conflicts []*QpWhatsappServer
+	for _, server := range WhatsappService.GetServers() {
+		if server.Token == info.Token || server.Wid == info.Wid {
+			conflicts = append(conflicts, server)
+		}
+	}
+
+	for _, server := range conflicts {
+		if server.Verified && !force {
+			return nil, fmt.Errorf("%w: session already active on this instance: %s", ErrSessionConflict, server.Token)
+		}
+	}
+
+	for _, server := range conflicts {
+		err = server.Stop("session import")
+		if err != nil {
+			return nil, err
+		}
+
+		// dropping the connection, it holds the previous device state in memory
+		if server.connection != nil && !server.connection.IsInterfaceNil() {
+			server.connection.Dispose("session import")
+		}
+		server.connection = nil
+
+		if server.Token != info.Token {
+			server.Verified = false
+			err = server.Save("session imported on another token")
+			if err != nil {
+				return nil, err
+			}
+		}
+	}
+
+	// owner should exist here, otherwise the session is left without owner
+	if len(user) > 0 {
+		info.User = user
+	}
+
+	if len(info.User) > 0 {
+		exists, err := WhatsappService.DB.Users.Exists(info.User)
+		if err != nil {
+			return nil, err
+		}
+
+		if !exists {
+			if len(user) > 0 {
+				return nil, fmt.Errorf("user not found: %s", user)
+			}
+			info.User = ""
+		}
+	}
+
+	err = whatsmeow.WhatsmeowService.ImportSession(ctx, archive.Device)
+	if err != nil {
+		return nil, err
+	}
+
+	exists, err := WhatsappService.DB.Servers.Exists(info.Token)
+	if err != nil {
+		return nil, err
+	}
+
+	info.Timestamp = time.Now().UTC()
+	if exists {
+		err = WhatsappService.DB.Servers.Update(info)
+	} else {
+		err = WhatsappService.DB.Servers.Add(info)
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	err = WhatsappService.DB.Dispatching.DispatchingClear(info.Token)
+	if err != nil {
+		return nil, err
+	}
+
+	for _, dispatching := range archive.Dispatching {
+		_, err = WhatsappService.DB.Dispatching.DispatchingAddOrUpdate(info.Token, dispatching)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	server, err = WhatsappService.AppendNewServer(info)
+	if err != nil {
+		return nil, err
+	}
+
+	server.DispatchingFill(server.QpServer, WhatsappService.DB.Dispatching)
+
+	logentry := server.GetLogger()
+	logentry.Infof("session imported, exported at %v by %s", archive.Created, archive.Node)
+
+	// on cluster mode, only the lease owner starts it
+	if ClusterManager.Acquire(server.Token) {
+		go server.Initialize()
+	} else {
+		logentry.Warnf("imported session is leased by another cluster node")
+	}
+
+	return server, nil
+}
@@ -0,0 +1,123 @@
+package models
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+	"time"
+
+	signalr "github.com/nocodeleaks/quepasa/signalr"
+	whatsapp "github.com/nocodeleaks/quepasa/whatsapp"
+)
+
+// QpSignalRCommands implements client invoked signalr hub commands
+type QpSignalRCommands struct{}
+
+func init() {
+	signalr.SignalRHub.SetCommands(&QpSignalRCommands{})
+}
+
+// get a ready server from token
+func (source *QpSignalRCommands) getReadyServer(token string) (*QpWhatsappServer, error) {
+	server, err := GetServerFromToken(token)
+	if err != nil {
+		return nil, err
+	}
+
+	status := server.GetStatus()
+	if status != whatsapp.Ready {
+		return nil, fmt.Errorf("server (%s) not ready, status: %s", server.GetWId(), status)
+	}
+
+	return server, nil
+}
+
+func (source *QpSignalRCommands) Send(token string, request *signalr.SignalRSendRequest) (*signalr.SignalRSendResponse, error) {
+	server, err := source.getReadyServer(token)
+	if err != nil {
+		return nil, err
+	}
+
+	sendRequest := &QpSendAnyRequest{
+		QpSendRequest: QpSendRequest{
+			ChatId:   request.ChatId,
+			Text:     request.Text,
+			InReply:  request.InReply,
+			TrackId:  request.TrackId,
+			FileName: request.FileName,
+		},
+		Url:     strings.TrimSpace(request.Url),
+		Content: request.Content,
+	}
+
+	if len(sendRequest.Url) > 0 {
+		err = sendRequest.GenerateUrlContent()
+	} else if len(sendRequest.Content) > 0 {
+		err = sendRequest.GenerateEmbedContent()
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	att := sendRequest.ToWhatsappAttachment()
+	if att.Attach == nil && len(sendRequest.Text) == 0 {
+		return nil, fmt.Errorf("text not found, do not send empty messages")
+	}
+
+	waMsg, err := sendRequest.ToWhatsappMessage()
+	if err != nil {
+		return nil, err
+	}
+
+	if att.Attach != nil {
+		waMsg.Attachment = att.Attach
+		waMsg.Type = whatsapp.GetMessageType(att.Attach)
+	}
+
+	sendResponse, err := server.SendMessage(waMsg)
+	if err != nil {
+		return nil, err
+	}
+
+	response := &signalr.SignalRSendResponse{
+		Success: true,
+		Status:  "sended with success",
+		Wid:     server.GetWId(),
+		Id:      sendResponse.GetId(),
+		ChatId:  waMsg.Chat.Id,
+		TrackId: waMsg.TrackId,
+	}
+	return response, nil
+}
+
+func (source *QpSignalRCommands) MarkRead(token string, messageid string) error {
+	server, err := source.getReadyServer(token)
+	if err != nil {
+		return err
+	}
+
+	return server.MarkRead(messageid)
+}
+
+func (source *QpSignalRCommands) GetMessages(token string, chatid string, timestamp int64) (messages []whatsapp.WhatsappMessage, err error) {
+	server, err := GetServerFromToken(token)
+	if err != nil {
+		return
+	}
+
+	if len(chatid) > 0 {
+		chatid, err = whatsapp.FormatEndpoint(chatid)
+		if err != nil {
+			return
+		}
+	}
+
+	for _, msg := range server.GetMessages(time.Unix(timestamp, 0)) {
+		if len(chatid) == 0 || msg.Chat.Id == chatid {
+			messages = append(messages, msg)
+		}
+	}
+
+	sort.Sort(sort.Reverse(whatsapp.WhatsappOrderedMessages(messages)))
+	return
+}
@@ -0,0 +1,72 @@
+package models
+
+import (
+	"context"
+	"sync"
+	"time"
+
+	environment "github.com/nocodeleaks/quepasa/environment"
+	media "github.com/nocodeleaks/quepasa/media"
+	whatsapp "github.com/nocodeleaks/quepasa/whatsapp"
+	log "github.com/sirupsen/logrus"
+)
+
+var transcriber media.ITranscriber
+var transcriberOnce sync.Once
+
+// GetTranscriber returns the speech-to-text backend configured on environment, nil if disabled or invalid
+func GetTranscriber() media.ITranscriber {
+	transcriberOnce.Do(func() {
+		settings := environment.Settings.Transcription
+		if !settings.Enabled() {
+			return
+		}
+
+		options := media.TranscriptionOptions{
+			Backend:  settings.Backend,
+			Command:  settings.Command,
+			Url:      settings.Url,
+			Token:    settings.Token,
+			Model:    settings.Model,
+			Language: settings.Language,
+			Timeout:  time.Duration(settings.Timeout) * time.Second,
+		}
+
+		instance, err := media.NewTranscriber(options)
+		if err != nil {
+			log.Errorf("transcription disabled, invalid configuration: %s", err.Error())
+			return
+		}
+
+		log.Infof("transcription enabled, backend: %s", instance.GetBackend())
+		transcriber = instance
+	})
+	return transcriber
+}
+
+// Transcribe downloads the voice note content and attach the speech-to-text result on message
+func Transcribe(connection whatsapp.IWhatsappConnection, msg *whatsapp.WhatsappMessage) error {
+	instance := GetTranscriber()
+	if instance == nil {
+		return nil
+	}
+
+	result := &whatsapp.WhatsappTranscription{Backend: instance.GetBackend()}
+	msg.Transcription = result
+
+	data, err := connection.DownloadData(msg)
+	if err != nil {
+		result.Error = err.Error()
+		return err
+	}
+
+	transcription, err := instance.Transcribe(context.Background(), data, msg.Attachment.Mimetype)
+	if err != nil {
+		result.Error = err.Error()
+		return err
+	}
+
+	result.Text = transcription.Text
+	result.Language = transcription.Language
+	return nil
+}
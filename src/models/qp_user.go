@@ -1,11 +1,84 @@
 package models
 
 import (
+	"strings"
 	"time"
 )
 
+// User roles, from the most to the least privileged
+const (
+	UserRoleAdmin    = "admin"    // manages users and every server
+	UserRoleOperator = "operator" // manages own servers
+	UserRoleViewer   = "viewer"   // read only access to own servers
+)
+
+// UserRoles lists the valid roles ordered by privilege
+var UserRoles = []string{UserRoleAdmin, UserRoleOperator, UserRoleViewer}
+
 type QpUser struct {
-	Username  string    `db:"username" json:"username" validate:"max=255"`
-	Password  string    `db:"password" json:"password" validate:"max=255"`
-	Timestamp time.Time `db:"timestamp" json:"timestamp,omitempty"`
+	Username  string     `db:"username" json:"username" validate:"max=255"`
+	Password  string     `db:"password" json:"-" validate:"max=255"`
+	Role      string     `db:"role" json:"role,omitempty"`
+	Disabled  bool       `db:"disabled" json:"disabled,omitempty"`
+	Failures  uint32     `db:"failures" json:"-"`
+	Locked    *time.Time `db:"locked" json:"locked,omitempty"`
+	Issuer    string     `db:"issuer" json:"issuer,omitempty"` // identity provider, empty for local accounts
+	Subject   string     `db:"subject" json:"-"`               // identity provider subject, set on the first login
+	Timestamp time.Time  `db:"timestamp" json:"timestamp,omitempty"`
+}
+
+// IsExternal checks if the user was created for single sign-on
+func (source QpUser) IsExternal() bool {
+	return len(source.Issuer) > 0
+}
+
+// IsValidUserRole checks if the given role is one of the known roles
+func IsValidUserRole(role string) bool {
+	return GetUserRoleLevel(role) > 0
+}
+
+// GetUserRoleLevel returns the privilege level of a role, 0 for unknown roles
+func GetUserRoleLevel(role string) int {
+	for index, item := range UserRoles {
+		if strings.EqualFold(item, role) {
+			return len(UserRoles) - index
+		}
+	}
+	return 0
+}
+
+// HasRole checks if the user has at least the privileges of the given role
+func (source QpUser) HasRole(role string) bool {
+	if source.Disabled {
+		return false
+	}
+	return GetUserRoleLevel(source.Role) >= GetUserRoleLevel(role)
+}
+
+// IsAdmin checks if the user can manage users and every server
+func (source QpUser) IsAdmin() bool {
+	return source.HasRole(UserRoleAdmin)
+}
+
+// CanWrite checks if the user can change servers, send messages and pair devices
+func (source QpUser) CanWrite() bool {
+	return source.HasRole(UserRoleOperator)
+}
+
+// CanAccess checks if the user can see a server owned by the given username
+func (source QpUser) CanAccess(owner string) bool {
+	if source.Disabled {
+		return false
+	}
+	return source.IsAdmin() || strings.EqualFold(source.Username, owner)
+}
+
+// IsLocked checks if the user is locked out at the given time
+func (source QpUser) IsLocked(now time.Time) bool {
+	return source.Locked != nil && source.Locked.After(now)
+}
+
+// GetLocked checks if the user is locked out now, used by views
+func (source QpUser) GetLocked() bool {
+	return source.IsLocked(time.Now().UTC())
 }
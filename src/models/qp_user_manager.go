@@ -0,0 +1,427 @@
+package models
+
+import (
+	"crypto/rand"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"strings"
+	"sync"
+	"time"
+
+	environment "github.com/nocodeleaks/quepasa/environment"
+	log "github.com/sirupsen/logrus"
+)
+
+var (
+	ErrUserInvalidCredentials = errors.New("invalid username or password")
+	ErrUserDisabled           = errors.New("user is disabled")
+	ErrUserLocked             = errors.New("user is locked after too many failed logins")
+	ErrUserForbidden          = errors.New("user role does not allow this operation")
+)
+
+// QpUserManager applies roles, disabling and login lockout over the users database, thread safe
+type QpUserManager struct {
+	mutex sync.Mutex
+}
+
+var UserManager = &QpUserManager{}
+
+func (source *QpUserManager) getDB() (QpDataUsersInterface, error) {
+	if WhatsappService == nil || WhatsappService.DB == nil || WhatsappService.DB.Users == nil {
+		return nil, fmt.Errorf("users database not ready")
+	}
+	return WhatsappService.DB.Users, nil
+}
+
+// Login checks the credentials, counting failures and locking the account when exceeded
+func (source *QpUserManager) Login(username string, password string) (*QpUser, error) {
+	db, err := source.getDB()
+	if err != nil {
+		return nil, err
+	}
+
+	source.mutex.Lock()
+	defer source.mutex.Unlock()
+
+	user, err := db.Find(username)
+	if err != nil || user == nil {
+		return nil, ErrUserInvalidCredentials
+	}
+
+	if user.Disabled {
+		return nil, ErrUserDisabled
+	}
+
+	now := time.Now().UTC()
+	if user.IsLocked(now) {
+		return nil, fmt.Errorf("%w, until: %s", ErrUserLocked, user.Locked.Format(time.RFC3339))
+	}
+
+	_, err = db.Check(username, password)
+	if err != nil {
+		settings := environment.Settings.Users
+		user.Failures++
+		if settings.LockoutAttempts > 0 && user.Failures >= settings.LockoutAttempts {
+			locked := now.Add(settings.GetLockoutDuration())
+			user.Locked = &locked
+			user.Failures = 0
+			log.Warnf("user (%s) locked until %s after too many failed logins", username, locked.Format(time.RFC3339))
+		}
+
+		if uerr := db.Update(user); uerr != nil {
+			log.Errorf("user (%s) error on saving failed login: %s", username, uerr.Error())
+		}
+		return nil, ErrUserInvalidCredentials
+	}
+
+	if user.Failures > 0 || user.Locked != nil {
+		user.Failures = 0
+		user.Locked = nil
+		if uerr := db.Update(user); uerr != nil {
+			log.Errorf("user (%s) error on resetting failed logins: %s", username, uerr.Error())
+		}
+	}
+
+	return user, nil
+}
+
+// LoginExternal resolves a user authenticated by an identity provider, without local password.
+// Only users created for single sign-on with the same issuer are accepted, linked to the subject on the first login,
+// local accounts with the same username are never linked.
+// Unknown users are created when provision is set, with an unusable random password.
+// When synced, the role comes from the provider and replaces the local one.
+func (source *QpUserManager) LoginExternal(issuer string, subject string, username string, role string, synced bool, provision bool) (*QpUser, error) {
+	db, err := source.getDB()
+	if err != nil {
+		return nil, err
+	}
+
+	if len(username) == 0 || username == DEFAULTEMAIL || len(issuer) == 0 || len(subject) == 0 {
+		return nil, ErrUserInvalidCredentials
+	}
+
+	exists, err := db.Exists(username)
+	if err != nil {
+		return nil, err
+	}
+
+	if !exists {
+		if !provision {
+			return nil, fmt.Errorf("%w: %s not provisioned", ErrUserInvalidCredentials, username)
+		}
+
+		user, err := source.CreateExternal(username, role, issuer, subject)
+		if err != nil {
+			return nil, err
+		}
+
+		log.Infof("user (%s) provisioned by identity provider with role: %s", username, user.Role)
+		return user, nil
+	}
+
+	user, err := db.Find(username)
+	if err != nil {
+		return nil, err
+	}
+
+	if user.Issuer != issuer || (len(user.Subject) > 0 && user.Subject != subject) {
+		log.Warnf("user (%s) refused from identity provider, local account or linked to another identity", username)
+		return nil, fmt.Errorf("%w: %s not linked to this identity", ErrUserInvalidCredentials, username)
+	}
+
+	if user.Disabled {
+		return nil, ErrUserDisabled
+	}
+
+	// created by an admin for single sign-on, linked on the first login
+	if len(user.Subject) == 0 {
+		err = db.SetIdentity(username, issuer, subject)
+		if err != nil {
+			return nil, err
+		}
+
+		user.Subject = subject
+		log.Infof("user (%s) linked to identity provider subject", username)
+	}
+
+	if synced && !strings.EqualFold(user.Role, role) {
+		updated, err := source.SetRole(username, role)
+		if err != nil {
+			log.Warnf("user (%s) role not synced from identity provider: %s", username, err.Error())
+		} else {
+			log.Infof("user (%s) role synced from identity provider: %s => %s", username, user.Role, updated.Role)
+			user = updated
+		}
+	}
+
+	return user, nil
+}
+
+// CreateExternal creates a user for single sign-on, with an unusable random password.
+// Without subject, it is linked on the first login from the issuer
+func (source *QpUserManager) CreateExternal(username string, role string, issuer string, subject string) (*QpUser, error) {
+	db, err := source.getDB()
+	if err != nil {
+		return nil, err
+	}
+
+	if len(issuer) == 0 {
+		return nil, fmt.Errorf("missing identity provider issuer")
+	}
+
+	secret := make([]byte, 32)
+	if _, err = rand.Read(secret); err != nil {
+		return nil, err
+	}
+
+	user, err := source.Create(username, hex.EncodeToString(secret), role)
+	if err != nil {
+		return nil, err
+	}
+
+	err = db.SetIdentity(username, issuer, subject)
+	if err != nil {
+		return nil, err
+	}
+
+	user.Issuer = issuer
+	user.Subject = subject
+	return user, nil
+}
+
+// Find returns an enabled user, used when acting on behalf of a user
+func (source *QpUserManager) Find(username string) (*QpUser, error) {
+	db, err := source.getDB()
+	if err != nil {
+		return nil, err
+	}
+
+	user, err := db.Find(username)
+	if err != nil {
+		return nil, err
+	}
+
+	if user.Disabled {
+		return nil, fmt.Errorf("%w: %s", ErrUserDisabled, username)
+	}
+
+	return user, nil
+}
+
+// List returns all users, except the seeded default account
+func (source *QpUserManager) List() ([]*QpUser, error) {
+	db, err := source.getDB()
+	if err != nil {
+		return nil, err
+	}
+
+	users, err := db.FindAll()
+	if err != nil {
+		return nil, err
+	}
+
+	result := []*QpUser{}
+	for _, user := range users {
+		if user.Username != DEFAULTEMAIL {
+			result = append(result, user)
+		}
+	}
+	return result, nil
+}
+
+// Create adds a new user with the given role, the password strength must be checked by the caller
+func (source *QpUserManager) Create(username string, password string, role string) (*QpUser, error) {
+	db, err := source.getDB()
+	if err != nil {
+		return nil, err
+	}
+
+	if len(username) == 0 || len(password) == 0 {
+		return nil, fmt.Errorf("missing username or password")
+	}
+
+	if len(role) == 0 {
+		role = UserRoleOperator
+	}
+
+	role = strings.ToLower(role)
+	if !IsValidUserRole(role) {
+		return nil, fmt.Errorf("invalid role: %s, valid roles: %s", role, strings.Join(UserRoles, ", "))
+	}
+
+	source.mutex.Lock()
+	defer source.mutex.Unlock()
+
+	exists, err := db.Exists(username)
+	if err != nil {
+		return nil, err
+	}
+
+	if exists {
+		return nil, fmt.Errorf("user already exists: %s", username)
+	}
+
+	return db.Add(username, password, role)
+}
+
+// EnsureAdmin promotes the given user when there is no enabled admin yet, used by the first account setup
+func (source *QpUserManager) EnsureAdmin(username string) error {
+	db, err := source.getDB()
+	if err != nil {
+		return err
+	}
+
+	source.mutex.Lock()
+	defer source.mutex.Unlock()
+
+	users, err := db.FindAll()
+	if err != nil {
+		return err
+	}
+
+	var found *QpUser
+	for _, user := range users {
+		if user.IsAdmin() {
+			return nil
+		}
+
+		if user.Username == username {
+			found = user
+		}
+	}
+
+	if found == nil {
+		return fmt.Errorf("user not found: %s", username)
+	}
+
+	found.Role = UserRoleAdmin
+	log.Infof("user (%s) promoted to admin, no other admin found", username)
+	return db.Update(found)
+}
+
+// SetRole changes the role of a user, the last enabled admin can not be demoted
+func (source *QpUserManager) SetRole(username string, role string) (*QpUser, error) {
+	role = strings.ToLower(role)
+	if !IsValidUserRole(role) {
+		return nil, fmt.Errorf("invalid role: %s, valid roles: %s", role, strings.Join(UserRoles, ", "))
+	}
+
+	return source.update(username, func(user *QpUser) {
+		user.Role = role
+	})
+}
+
+// SetDisabled enables or disables a user, the last enabled admin can not be disabled
+func (source *QpUserManager) SetDisabled(username string, disabled bool) (*QpUser, error) {
+	return source.update(username, func(user *QpUser) {
+		user.Disabled = disabled
+	})
+}
+
+// Unlock clears failed logins and lockout of a user
+func (source *QpUserManager) Unlock(username string) (*QpUser, error) {
+	return source.update(username, func(user *QpUser) {
+		user.Failures = 0
+		user.Locked = nil
+	})
+}
+
+// ResetPassword sets a new password and clears the lockout, the password strength must be checked by the caller
+func (source *QpUserManager) ResetPassword(username string, password string) error {
+	db, err := source.getDB()
+	if err != nil {
+		return err
+	}
+
+	if len(password) == 0 {
+		return fmt.Errorf("missing password")
+	}
+
+	err = db.UpdatePassword(username, password)
+	if err != nil {
+		return err
+	}
+
+	_, err = source.Unlock(username)
+	return err
+}
+
+// Delete removes a user that owns no servers, the last enabled admin and the default account are kept
+func (source *QpUserManager) Delete(username string) error {
+	db, err := source.getDB()
+	if err != nil {
+		return err
+	}
+
+	if username == DEFAULTEMAIL {
+		return fmt.Errorf("default user can not be deleted")
+	}
+
+	servers := WhatsappService.GetServersForUser(username)
+	if len(servers) > 0 {
+		return fmt.Errorf("user (%s) owns %d server(s), move or delete them first", username, len(servers))
+	}
+
+	source.mutex.Lock()
+	defer source.mutex.Unlock()
+
+	user, err := db.Find(username)
+	if err != nil {
+		return err
+	}
+
+	if user.IsAdmin() {
+		if err = source.ensureAnotherAdmin(db, username); err != nil {
+			return err
+		}
+	}
+
+	return db.Delete(username)
+}
+
+func (source *QpUserManager) update(username string, change func(*QpUser)) (*QpUser, error) {
+	db, err := source.getDB()
+	if err != nil {
+		return nil, err
+	}
+
+	source.mutex.Lock()
+	defer source.mutex.Unlock()
+
+	user, err := db.Find(username)
+	if err != nil {
+		return nil, err
+	}
+
+	wasAdmin := user.IsAdmin()
+	change(user)
+	if wasAdmin && !user.IsAdmin() {
+		if err = source.ensureAnotherAdmin(db, username); err != nil {
+			return nil, err
+		}
+	}
+
+	err = db.Update(user)
+	if err != nil {
+		return nil, err
+	}
+
+	return user, nil
+}
+
+// ensureAnotherAdmin fails if no other enabled admin exists besides the given username
+func (source *QpUserManager) ensureAnotherAdmin(db QpDataUsersInterface, username string) error {
+	users, err := db.FindAll()
+	if err != nil {
+		return err
+	}
+
+	for _, user := range users {
+		if user.Username != username && user.IsAdmin() {
+			return nil
+		}
+	}
+
+	return fmt.Errorf("user (%s) is the last enabled admin", username)
+}
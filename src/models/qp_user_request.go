@@ -0,0 +1,11 @@
+package models
+
+// QpUserRequest creates or updates a user through the api, empty fields are not changed
+type QpUserRequest struct {
+	Username string `json:"username"`
+	Password string `json:"password,omitempty"`
+	Role     string `json:"role,omitempty"`
+	Disabled *bool  `json:"disabled,omitempty"`
+	Unlock   bool   `json:"unlock,omitempty"`
+	External bool   `json:"external,omitempty"` // created for single sign-on, without password, linked on the first login
+}
@@ -0,0 +1,7 @@
+package models
+
+type QpUsersResponse struct {
+	QpResponse
+	Users []*QpUser `json:"users,omitempty"`
+	User  *QpUser   `json:"user,omitempty"`
+}
@@ -0,0 +1,322 @@
+package models
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"sync"
+	"time"
+
+	"github.com/google/uuid"
+	environment "github.com/nocodeleaks/quepasa/environment"
+	library "github.com/nocodeleaks/quepasa/library"
+	whatsapp "github.com/nocodeleaks/quepasa/whatsapp"
+	log "github.com/sirupsen/logrus"
+)
+
+// QpWatchdog periodically checks sessions health, restarts the unhealthy ones with backoff
+// and emits state change events, thread safe
+type QpWatchdog struct {
+	mutex   sync.Mutex
+	entries map[string]*qpWatchdogEntry // by token
+	stop    chan struct{}
+}
+
+// watched state of a single session
+type qpWatchdogEntry struct {
+	Health     string    // last detected health, one of watchdog events
+	Since      time.Time // when the current health was detected
+	Attempts   uint      // restarts since last connected
+	Next       time.Time // restarts are not attempted before it
+	Restarting bool
+}
+
+var Watchdog = &QpWatchdog{
+	entries: map[string]*qpWatchdogEntry{},
+}
+
+// Enabled returns true if the watchdog is configured to run
+func (source *QpWatchdog) Enabled() bool {
+	return environment.Settings.Watchdog.Enabled
+}
+
+// Initialize starts the periodic checks
+func (source *QpWatchdog) Initialize() {
+	if !source.Enabled() {
+		return
+	}
+
+	settings := environment.Settings.Watchdog
+	log.Infof("watchdog enabled, interval: %v, down: %vs, idle: %vs", settings.GetInterval(), settings.Down, settings.Idle)
+
+	source.mutex.Lock()
+	defer source.mutex.Unlock()
+
+	if source.stop == nil {
+		source.stop = make(chan struct{})
+		go source.run(source.stop, settings.GetInterval())
+	}
+}
+
+func (source *QpWatchdog) run(stop chan struct{}, interval time.Duration) {
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-stop:
+			return
+		case <-ticker.C:
+			source.CheckAll()
+		}
+	}
+}
+
+// Shutdown stops the periodic checks
+func (source *QpWatchdog) Shutdown() {
+	source.mutex.Lock()
+	defer source.mutex.Unlock()
+
+	if source.stop != nil {
+		close(source.stop)
+		source.stop = nil
+	}
+}
+
+// CheckAll checks every session on service, forgetting the removed ones
+func (source *QpWatchdog) CheckAll() {
+	if WhatsappService == nil {
+		return
+	}
+
+	servers := map[string]*QpWhatsappServer{}
+	for _, server := range WhatsappService.GetServers() {
+		if server.QpServer != nil {
+			servers[server.Token] = server
+		}
+	}
+
+	now := time.Now().UTC()
+
+	source.mutex.Lock()
+	for token := range source.entries {
+		if _, ok := servers[token]; !ok {
+			delete(source.entries, token)
+		}
+	}
+	source.mutex.Unlock()
+
+	for _, server := range servers {
+		source.Check(server, now)
+	}
+}
+
+// Check detects the session health, emits an event on change and restarts it if needed
+func (source *QpWatchdog) Check(server *QpWhatsappServer, now time.Time) {
+	if server == nil || server.QpServer == nil {
+		return
+	}
+
+	token := server.Token
+
+	source.mutex.Lock()
+	defer source.mutex.Unlock()
+
+	entry, ok := source.entries[token]
+	if ok && entry.Restarting {
+		return
+	}
+
+	// stopped by request or served by another node, nothing to watch
+	if server.StopRequested || !ClusterManager.IsOwner(token) {
+		delete(source.entries, token)
+		return
+	}
+
+	state := server.GetStatus()
+	health := source.GetHealth(server, state, now)
+	if len(health) == 0 {
+		// transient state (connecting, reconnecting, ...), awaiting
+		return
+	}
+
+	if !ok {
+		// first observation, just a baseline
+		source.entries[token] = &qpWatchdogEntry{Health: health, Since: now}
+		return
+	}
+
+	if entry.Health != health {
+		previous := entry.Health
+		entry.Health = health
+		entry.Since = now
+		if health == WatchdogConnected {
+			entry.Attempts = 0
+			entry.Next = time.Time{}
+		}
+
+		source.emit(server, &QpWatchdogEvent{
+			Token:     token,
+			Wid:       server.Wid,
+			Event:     health,
+			Previous:  previous,
+			State:     state.String(),
+			Attempts:  entry.Attempts,
+			Reason:    source.GetReason(server, health, state, now),
+			Timestamp: now,
+		})
+	}
+
+	switch health {
+	case WatchdogDisconnected:
+		down := time.Duration(environment.Settings.Watchdog.Down) * time.Second
+		if now.Sub(entry.Since) >= down {
+			source.restart(server, entry, now, fmt.Sprintf("%s for %v", state, now.Sub(entry.Since).Truncate(time.Second)))
+		}
+	case WatchdogStuck:
+		source.restart(server, entry, now, "no events received")
+	}
+}
+
+// GetHealth classifies the session, empty for transient states
+func (source *QpWatchdog) GetHealth(server *QpWhatsappServer, state whatsapp.WhatsappConnectionState, now time.Time) string {
+	if !server.Verified || state == whatsapp.UnVerified {
+		return WatchdogLoggedOut
+	}
+
+	switch state {
+	case whatsapp.Disconnected, whatsapp.Failed, whatsapp.UnPrepared:
+		return WatchdogDisconnected
+	case whatsapp.Ready:
+		idle := time.Duration(environment.Settings.Watchdog.Idle) * time.Second
+		if idle > 0 && now.Sub(GetLastActivity(server)) > idle {
+			return WatchdogStuck
+		}
+		return WatchdogConnected
+	default:
+		return ""
+	}
+}
+
+// GetReason describes why the session got the health, for events
+func (source *QpWatchdog) GetReason(server *QpWhatsappServer, health string, state whatsapp.WhatsappConnectionState, now time.Time) string {
+	switch health {
+	case WatchdogLoggedOut:
+		return "session not verified, a new pairing is required"
+	case WatchdogDisconnected:
+		return fmt.Sprintf("connection state: %s", state)
+	case WatchdogStuck:
+		return fmt.Sprintf("no events received for %v", now.Sub(GetLastActivity(server)).Truncate(time.Second))
+	default:
+		return "session connected"
+	}
+}
+
+// GetLastActivity returns the latest of received message, event or start timestamps
+func GetLastActivity(server *QpWhatsappServer) time.Time {
+	last := server.Timestamps.Start
+	if server.Timestamps.Message != nil && server.Timestamps.Message.After(last) {
+		last = *server.Timestamps.Message
+	}
+	if server.Timestamps.Event != nil && server.Timestamps.Event.After(last) {
+		last = *server.Timestamps.Event
+	}
+	return last
+}
+
+// restart without locking, respecting backoff, logged out sessions never reach here
+func (source *QpWatchdog) restart(server *QpWhatsappServer, entry *qpWatchdogEntry, now time.Time, reason string) {
+	if now.Before(entry.Next) {
+		return
+	}
+
+	entry.Next = now.Add(environment.Settings.Watchdog.GetBackoff(entry.Attempts))
+	entry.Attempts++
+	entry.Restarting = true
+
+	WatchdogRestarts.WithLabelValues(entry.Health).Inc()
+
+	logentry := server.GetLogger()
+	logentry.Warnf("watchdog, restarting server, attempt: %d, reason: %s, next attempt not before: %v", entry.Attempts, reason, entry.Next)
+
+	go func() {
+		err := server.Restart()
+		if err != nil {
+			logentry.Errorf("watchdog, error on restarting server: %s", err.Error())
+		}
+
+		source.mutex.Lock()
+		entry.Restarting = false
+		source.mutex.Unlock()
+	}()
+}
+
+// emit dispatches the event as system message and posts it to alert webhook, if any
+func (source *QpWatchdog) emit(server *QpWhatsappServer, event *QpWatchdogEvent) {
+	WatchdogEvents.WithLabelValues(event.Event).Inc()
+
+	logentry := server.GetLogger()
+	if event.Event == WatchdogConnected {
+		logentry.Infof("watchdog, session %s, previous: %s", event.Event, event.Previous)
+	} else {
+		logentry.Warnf("watchdog, session %s, previous: %s, state: %s", event.Event, event.Previous, event.State)
+	}
+
+	message := &whatsapp.WhatsappMessage{
+		Id:        uuid.New().String(),
+		Timestamp: event.Timestamp,
+		Type:      whatsapp.SystemMessageType,
+		Chat:      whatsapp.WASYSTEMCHAT,
+		Text:      event.Event,
+		Info:      event,
+	}
+
+	if server.Handler != nil {
+
+		// dispatching handler is detached after connection errors, alerts should reach it anyway
+		if !server.Handler.IsAttached() && server.DispatchingHandler != nil {
+			server.Handler.Register(server.DispatchingHandler)
+		}
+
+		go server.Handler.Trigger(message, "watchdog")
+	}
+
+	url := environment.Settings.Watchdog.Webhook
+	if len(url) > 0 {
+		go PostWatchdogAlert(url, event)
+	}
+}
+
+// PostWatchdogAlert posts the event as json to the alert webhook, with the token masked
+func PostWatchdogAlert(url string, event *QpWatchdogEvent) {
+	alert := *event
+	alert.Token = library.MaskToken(event.Token)
+
+	payload, err := json.Marshal(alert)
+	if err != nil {
+		log.Errorf("watchdog, error on serializing alert: %s", err.Error())
+		return
+	}
+
+	req, err := http.NewRequest("POST", url, bytes.NewBuffer(payload))
+	if err != nil {
+		log.Errorf("watchdog, error on creating alert request: %s", err.Error())
+		return
+	}
+
+	req.Header.Set("User-Agent", "Quepasa")
+	req.Header.Set("Content-Type", "application/json")
+
+	client := &http.Client{Timeout: 10 * time.Second}
+	resp, err := client.Do(req)
+	if err != nil {
+		log.Warnf("watchdog, error on posting alert: %s", err.Error())
+		return
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode < 200 || resp.StatusCode > 299 {
+		log.Warnf("watchdog, alert webhook responded with status: %d", resp.StatusCode)
+	}
+}
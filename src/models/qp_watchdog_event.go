@@ -0,0 +1,23 @@
+package models
+
+import "time"
+
+// Watchdog state change events
+const (
+	WatchdogConnected    = "connected"
+	WatchdogDisconnected = "disconnected"
+	WatchdogLoggedOut    = "logged_out"
+	WatchdogStuck        = "stuck"
+)
+
+// Session state change detected by the watchdog, dispatched as system message info and posted to alert webhook
+type QpWatchdogEvent struct {
+	Token     string    `json:"token"`
+	Wid       string    `json:"wid,omitempty"`
+	Event     string    `json:"event"`
+	Previous  string    `json:"previous,omitempty"`
+	State     string    `json:"state"`
+	Attempts  uint      `json:"attempts,omitempty"` // restarts since the session was last connected
+	Reason    string    `json:"reason,omitempty"`
+	Timestamp time.Time `json:"timestamp"`
+}
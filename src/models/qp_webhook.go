@@ -13,6 +13,7 @@ import (
 	environment "github.com/nocodeleaks/quepasa/environment"
 	library "github.com/nocodeleaks/quepasa/library"
     rabbitmq "github.com/nocodeleaks/quepasa/rabbitmq"
+    telemetry "github.com/nocodeleaks/quepasa/telemetry"
 	whatsapp "github.com/nocodeleaks/quepasa/whatsapp"
 	log "github.com/sirupsen/logrus"
 )
@@ -128,9 +129,10 @@ func (source *QpWebhook) Post(message *whatsapp.WhatsappMessage, from string) (e
         req.Header.Set("User-Agent", "Quepasa")
         req.Header.Set("X-QUEPASA-WID", source.Wid)
         req.Header.Set("Content-Type", "application/json")
+        telemetry.InjectHeaders(message.TraceContext, req.Header)
 
         client := &http.Client{}
-        timeout := time.Duration(environment.Settings.API.WebhookTimeout) * time.Millisecond
+        timeout := environment.Settings.GetWebhookTimeout()
 		client.Timeout = timeout
 
 		logentry.Debugf("executing HTTP request to: %s, timeout: %v", source.Url, timeout)
@@ -85,7 +85,7 @@ func EnsureServerOnCache(currentUserID string, wid string, connection whatsapp.I
 }
 
 func GetDownloadPrefixFromToken(token string) (path string, err error) {
-	server, ok := WhatsappService.Servers[token]
+	server, ok := WhatsappService.GetServer(token)
 	if !ok {
 		err = fmt.Errorf("server not found: %s", token)
 		return
@@ -300,4 +300,20 @@ func ToggleCalls(source whatsapp.IWhatsappOptions) error {
 	return source.Save(reason)
 }
 
+func ToggleTranscription(source whatsapp.IWhatsappOptions) error {
+	options := source.GetOptions()
+
+	switch options.Transcription {
+	case whatsapp.UnSetBooleanType:
+		options.Transcription = whatsapp.TrueBooleanType
+	case whatsapp.TrueBooleanType:
+		options.Transcription = whatsapp.FalseBooleanType
+	default:
+		options.Transcription = whatsapp.UnSetBooleanType
+	}
+
+	reason := fmt.Sprintf("toggle transcription: %s", options.Transcription)
+	return source.Save(reason)
+}
+
 //#endregion
@@ -7,9 +7,11 @@ import (
 	"time"
 
 	"github.com/google/uuid"
+	environment "github.com/nocodeleaks/quepasa/environment"
 	library "github.com/nocodeleaks/quepasa/library"
 	rabbitmq "github.com/nocodeleaks/quepasa/rabbitmq"
 	signalr "github.com/nocodeleaks/quepasa/signalr"
+	telemetry "github.com/nocodeleaks/quepasa/telemetry"
 	whatsapp "github.com/nocodeleaks/quepasa/whatsapp"
 )
 
@@ -22,6 +24,9 @@ type QPWhatsappHandlers struct {
 
 	syncRegister *sync.Mutex
 
+	// latest positions of live location sharings
+	LiveLocations *QpLiveLocationTracker
+
 	// Appended events handler
 	aeh []QpDispatchingHandlerInterface
 }
@@ -56,6 +61,19 @@ func (source *QPWhatsappHandlers) HandleBroadcasts() bool {
 	return global.HandleBroadcasts(local)
 }
 
+// should transcribe incoming voice notes, resolving server option against environment default
+func (source *QPWhatsappHandlers) HandleTranscription() bool {
+	if source.server == nil || GetTranscriber() == nil {
+		return false
+	}
+
+	if source.server.Transcription != whatsapp.UnSetBooleanType {
+		return source.server.Transcription.Boolean()
+	}
+
+	return environment.Settings.Transcription.Default
+}
+
 //#region EVENTS FROM WHATSAPP SERVICE
 
 // Process messages received from whatsapp service
@@ -99,12 +117,44 @@ func (source *QPWhatsappHandlers) Message(msg *whatsapp.WhatsappMessage, from st
 	logentry = logentry.WithField(LogFields.ChatId, msg.Chat.Id)
 	logentry.Level = loglevel
 
+	// speech-to-text for incoming live voice notes, before caching and dispatching, history sync ones are skipped
+	if !msg.FromMe && !msg.FromHistory && msg.Type == whatsapp.AudioMessageType && msg.Attachment != nil && msg.Attachment.IsPTTCompatible() && source.HandleTranscription() {
+		err := Transcribe(source.server.GetConnection(), msg)
+		if err != nil {
+			logentry.Warnf("error on transcribe voice note: %s", err.Error())
+		}
+	}
+
+	// live location position updates are dispatched as events, without caching, history sync ones are only cached
+	if msg.LiveLocation != nil && !msg.FromHistory && source.LiveLocations != nil && source.LiveLocations.Update(msg) {
+		msg.InReply = msg.LiveLocation.Origin
+		logentry.Tracef("live location update: %d, origin: %s", msg.LiveLocation.Updates, msg.LiveLocation.Origin)
+		source.Trigger(msg, "livelocation")
+		return
+	}
+
+	// persisting polls options and resolving votes, before caching and dispatching
+	if msg.PollVote != nil || msg.Poll != nil {
+		var cached *whatsapp.WhatsappMessage
+		if msg.PollVote != nil {
+			cached, _ = source.QpWhatsappMessages.GetById(msg.PollVote.PollId)
+		}
+		PollManager.Handle(source.server.Token, msg, cached)
+	}
+
 	logentry.Debugf("appending message to cache, from: %s", from)
 	source.appendMsgToCache(msg, from)
 }
 
 // region STATUS AND RECEIPTS
 
+// updates cached message status and campaign recipients
+func (source *QPWhatsappHandlers) MessageStatusUpdate(id string, status whatsapp.WhatsappMessageStatus) bool {
+	updated := source.QpWhatsappMessages.MessageStatusUpdate(id, status)
+	CampaignManager.StatusUpdate(id, status)
+	return updated
+}
+
 // does not cache msg, only update status and webhook dispatch
 func (source *QPWhatsappHandlers) Receipt(msg *whatsapp.WhatsappMessage) {
 	// should implement a better method for that !!!!
@@ -117,6 +167,11 @@ func (source *QPWhatsappHandlers) Receipt(msg *whatsapp.WhatsappMessage) {
 	source.Trigger(msg, "receipt")
 }
 
+// does not cache msg, only dispatch chat management actions made on other devices
+func (source *QPWhatsappHandlers) ChatAction(msg *whatsapp.WhatsappMessage) {
+	source.Trigger(msg, "chataction")
+}
+
 //endregion
 
 /*
@@ -251,7 +306,16 @@ func (source *QPWhatsappHandlers) Trigger(payload *whatsapp.WhatsappMessage, fro
 
 	if source.server != nil {
 		payload.Wid = source.GetWId()
-		go signalr.SignalRHub.Dispatch(source.server.Token, payload)
+	}
+
+	// dispatching spans (webhooks, rabbitmq) are children of this one
+	ctx, span := telemetry.StartMessageSpan("quepasa.trigger", payload, telemetry.SpanKindInternal, telemetry.From(from))
+	payload.TraceContext = ctx
+	defer span.End()
+
+	if source.server != nil {
+		go signalr.SignalRHub.Dispatch(source.server.Token, payload, from)
+		EventStreamHub.Publish(source.server.Token, payload, from)
 	}
 
     for _, handler := range source.aeh {
@@ -3,6 +3,8 @@ package models
 import (
 	"fmt"
 	"strings"
+	"sync"
+	"sync/atomic"
 	"time"
 
 	whatsapp "github.com/nocodeleaks/quepasa/whatsapp"
@@ -10,10 +12,22 @@ import (
 
 const DEFAULTEXPIRATION time.Duration = time.Duration(124 * time.Hour)
 
+// interval between purges of expired messages, checked on cleanups
+const QpCacheExpiredPurgeInterval = time.Minute
+
 type QpWhatsappMessages struct {
 	QpCache
 
 	statuses QpCache
+
+	// full text index over cached messages
+	index QpMessageIndex
+
+	// chat id => time of last read, used for unread counts
+	reads sync.Map
+
+	// unix time of last expired purge
+	purged atomic.Int64
 }
 
 func GetCacheExpiration() time.Time {
@@ -37,7 +51,16 @@ func (source *QpWhatsappMessages) Append(value *whatsapp.WhatsappMessage, from s
 
 	expiration := GetCacheExpiration()
 	item := QpCacheItem{normalizedId, value, expiration}
-	return source.SetCacheItem(item, "message-"+from)
+	valid := source.SetCacheItem(item, "message-"+from)
+	if valid {
+		source.index.Add(value)
+
+		// replying a chat means it was read
+		if value.FromMe {
+			source.SetChatRead(value.Chat.Id, value.Timestamp)
+		}
+	}
+	return valid
 }
 
 func (source *QpWhatsappMessages) GetSlice() (items []*whatsapp.WhatsappMessage) {
@@ -55,6 +78,9 @@ func (source *QpWhatsappMessages) GetById(id string) (msg *whatsapp.WhatsappMess
 
 	cached, found := source.GetAny(normalizedId)
 	if !found {
+		// expired or removed from cache
+		source.index.Remove(normalizedId)
+
 		err = fmt.Errorf("message not present on cache, id: %s", normalizedId)
 		return
 	}
@@ -106,6 +132,33 @@ func (source *QpWhatsappMessages) GetByPrefix(id string) (messages []*whatsapp.W
 	return
 }
 
+// CleanUp removes expired messages, at most once per interval, and the oldest ones over max, keeping the index in sync
+func (source *QpWhatsappMessages) CleanUp(max uint64) {
+	var removed []string
+
+	now := time.Now()
+	last := source.purged.Load()
+	if now.Sub(time.Unix(last, 0)) > QpCacheExpiredPurgeInterval && source.purged.CompareAndSwap(last, now.Unix()) {
+		removed = source.QpCache.CleanUpExpired()
+		source.statuses.CleanUpExpired()
+	}
+
+	removed = append(removed, source.QpCache.CleanUp(max)...)
+	for _, key := range removed {
+		source.index.Remove(key)
+	}
+
+	// read marks of chats without cached messages
+	if len(removed) > 0 {
+		source.reads.Range(func(key, value any) bool {
+			if !source.index.HasChat(key.(string)) {
+				source.reads.Delete(key)
+			}
+			return true
+		})
+	}
+}
+
 //#endregion
 //#region STATUS
 
@@ -149,3 +202,74 @@ func (source *QpWhatsappMessages) MessageStatusUpdate(id string, status whatsapp
 }
 
 //#endregion
+
+//#region CHATS
+
+// GetGroupedByChat returns valid cached messages by chat id, in a single pass over the cache
+func (source *QpWhatsappMessages) GetGroupedByChat() map[string][]*whatsapp.WhatsappMessage {
+	now := time.Now()
+	chats := map[string][]*whatsapp.WhatsappMessage{}
+	for _, item := range source.GetSliceOfCachedItems() {
+		if now.After(item.Expiration) {
+			continue
+		}
+
+		msg, ok := item.Value.(*whatsapp.WhatsappMessage)
+		if !ok || msg == nil {
+			continue
+		}
+
+		chats[msg.Chat.Id] = append(chats[msg.Chat.Id], msg)
+	}
+	return chats
+}
+
+// GetByChat returns all cached messages of a chat, unordered
+func (source *QpWhatsappMessages) GetByChat(chatid string) (messages []*whatsapp.WhatsappMessage) {
+	for _, id := range source.index.GetChatMessageIds(chatid) {
+		msg, err := source.GetById(id)
+		if err != nil {
+			continue
+		}
+
+		messages = append(messages, msg)
+	}
+
+	return
+}
+
+// SetChatRead marks a chat as read until timestamp, never moves backwards
+func (source *QpWhatsappMessages) SetChatRead(chatid string, timestamp time.Time) {
+	if timestamp.IsZero() {
+		timestamp = time.Now()
+	}
+
+	current := source.GetChatRead(chatid)
+	if timestamp.After(current) {
+		source.reads.Store(chatid, timestamp)
+	}
+}
+
+// SetChatUnread moves the read mark before the last incoming message of the chat
+func (source *QpWhatsappMessages) SetChatUnread(chatid string) {
+	var last *whatsapp.WhatsappMessage
+	for _, msg := range source.GetByChat(chatid) {
+		if !msg.FromMe && (last == nil || msg.Timestamp.After(last.Timestamp)) {
+			last = msg
+		}
+	}
+
+	if last != nil {
+		source.reads.Store(chatid, last.Timestamp.Add(-time.Nanosecond))
+	}
+}
+
+// GetChatRead returns the time of last read of a chat, zero if never read
+func (source *QpWhatsappMessages) GetChatRead(chatid string) time.Time {
+	if value, ok := source.reads.Load(chatid); ok {
+		return value.(time.Time)
+	}
+	return time.Time{}
+}
+
+//#endregion
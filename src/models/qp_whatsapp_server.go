@@ -12,6 +12,7 @@ import (
 	library "github.com/nocodeleaks/quepasa/library"
 	rabbitmq "github.com/nocodeleaks/quepasa/rabbitmq"
 	signalr "github.com/nocodeleaks/quepasa/signalr"
+	telemetry "github.com/nocodeleaks/quepasa/telemetry"
 	whatsapp "github.com/nocodeleaks/quepasa/whatsapp"
 )
 
@@ -33,6 +34,9 @@ type QpWhatsappServer struct {
 	GroupManager       *QpGroupManager       `json:"-"` // composition for group operations
 	StatusManager      *QpStatusManager      `json:"-"` // composition for status operations
 	ContactManager     *QpContactManager     `json:"-"` // composition for contact operations
+	ProfileManager     *QpProfileManager     `json:"-"` // composition for own profile operations
+	BusinessManager    *QpBusinessManager    `json:"-"` // composition for business profile and catalog operations
+	ChatManager        *QpChatManager        `json:"-"` // composition for chat management operations
 
 	// Stop request token
 	StopRequested bool                   `json:"-"`
@@ -118,8 +122,9 @@ func (server *QpWhatsappServer) HandlerEnsure() {
 
 	if server.Handler == nil {
 		handler := &QPWhatsappHandlers{
-			server:       server,
-			syncRegister: &sync.Mutex{},
+			server:        server,
+			syncRegister:  &sync.Mutex{},
+			LiveLocations: NewQpLiveLocationTracker(),
 		}
 
 		logentry := server.GetLogger()
@@ -133,6 +138,15 @@ func (server *QpWhatsappServer) HandlerEnsure() {
 	}
 }
 
+// GetLiveLocations returns the latest positions of active live location sharings on a chat
+func (server *QpWhatsappServer) GetLiveLocations(chatId string) []whatsapp.WhatsappLiveLocation {
+	if server == nil || server.Handler == nil || server.Handler.LiveLocations == nil {
+		return nil
+	}
+
+	return server.Handler.LiveLocations.GetLatest(chatId)
+}
+
 func (server *QpWhatsappServer) HasSignalRActiveConnections() bool {
 	if server == nil {
 		return false // invalid state
@@ -255,13 +269,71 @@ func (source *QpWhatsappServer) Edit(id string, newContent string) (err error) {
 	return source.connection.Edit(msg, newContent)
 }
 
+// Forward sends a cached message to each chat, results follow the chat ids order
+func (source *QpWhatsappServer) Forward(id string, chatids []string, trackid string) (results []QpForwardResult, err error) {
+	msg, err := source.Handler.GetById(id)
+	if err != nil {
+		return
+	}
+
+	conn, err := source.GetValidConnection()
+	if err != nil {
+		return
+	}
+
+	for _, chatid := range chatids {
+		result := QpForwardResult{ChatId: chatid}
+
+		forwarded, err := conn.Forward(msg, chatid)
+		if err != nil {
+			result.Error = err.Error()
+		} else {
+			forwarded.TrackId = trackid
+			source.Handler.Message(forwarded, "server forward")
+
+			result.ChatId = forwarded.Chat.Id
+			result.Id = forwarded.Id
+			result.Timestamp = &forwarded.Timestamp
+		}
+
+		results = append(results, result)
+	}
+
+	source.GetLogger().Infof("forwarded msg %s to %d chats", id, len(chatids))
+	return
+}
+
+func (source *QpWhatsappServer) Star(id string, starred bool) (err error) {
+	msg, err := source.Handler.GetById(id)
+	if err != nil {
+		return
+	}
+
+	source.GetLogger().Infof("starring msg %s: %v", id, starred)
+	return source.GetChatManager().StarMessage(msg, starred)
+}
+
+func (source *QpWhatsappServer) DeleteForMe(id string, deleteMedia bool) (err error) {
+	msg, err := source.Handler.GetById(id)
+	if err != nil {
+		return
+	}
+
+	source.GetLogger().Infof("deleting msg for me %s", id)
+	return source.GetChatManager().DeleteMessageForMe(msg, deleteMedia)
+}
+
 func (source *QpWhatsappServer) MarkRead(id string) (err error) {
 	msg, err := source.Handler.GetById(id)
 	if err != nil {
 		return
 	}
 	source.GetLogger().Infof("marking msg %s as read", id)
-	return source.connection.MarkRead(msg)
+	err = source.connection.MarkRead(msg)
+	if err == nil {
+		source.Handler.SetChatRead(msg.Chat.Id, msg.Timestamp)
+	}
+	return
 }
 
 //endregion
@@ -384,6 +456,7 @@ func (source *QpWhatsappServer) Start() (err error) {
 
 	// reset stop requested token
 	source.StopRequested = false
+	source.Timestamps.Start = time.Now().UTC()
 
 	if !source.Handler.IsAttached() {
 
@@ -433,6 +506,7 @@ func (source *QpWhatsappServer) EnsureReady() (err error) {
 
 	// reset stop requested token
 	source.StopRequested = false
+	source.Timestamps.Start = time.Now().UTC()
 
 	if !source.Handler.IsAttached() {
 		logger.Info("attaching handlers")
@@ -715,6 +789,10 @@ func (source *QpWhatsappServer) SendMessage(msg *whatsapp.WhatsappMessage) (resp
 	logger := source.GetLogger()
 	logger.Debugf("sending msg to: %s", msg.Chat.Id)
 
+	ctx, span := telemetry.StartMessageSpan("quepasa.send", msg, telemetry.SpanKindInternal, telemetry.Wid(source.Wid))
+	msg.TraceContext = ctx
+	defer func() { telemetry.EndSpan(span, err) }()
+
 	conn, err := source.GetValidConnection()
 	if err != nil {
 		return
@@ -847,6 +925,30 @@ func (server *QpWhatsappServer) GetContactManager() whatsapp.WhatsappContactMana
 	return server.ContactManager
 }
 
+// GetProfileManager returns the profile manager instance with lazy initialization
+func (server *QpWhatsappServer) GetProfileManager() whatsapp.WhatsappProfileManagerInterface {
+	if server.ProfileManager == nil {
+		server.ProfileManager = NewQpProfileManager(server)
+	}
+	return server.ProfileManager
+}
+
+// GetBusinessManager returns the business manager instance with lazy initialization
+func (server *QpWhatsappServer) GetBusinessManager() whatsapp.WhatsappBusinessManagerInterface {
+	if server.BusinessManager == nil {
+		server.BusinessManager = NewQpBusinessManager(server)
+	}
+	return server.BusinessManager
+}
+
+// GetChatManager returns the chat manager instance with lazy initialization
+func (server *QpWhatsappServer) GetChatManager() whatsapp.WhatsappChatManagerInterface {
+	if server.ChatManager == nil {
+		server.ChatManager = NewQpChatManager(server)
+	}
+	return server.ChatManager
+}
+
 //#endregion
 
 func (server *QpWhatsappServer) SendChatPresence(chatId string, presenceType whatsapp.WhatsappChatPresenceType) error {
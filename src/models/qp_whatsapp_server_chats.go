@@ -0,0 +1,147 @@
+package models
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+
+	whatsapp "github.com/nocodeleaks/quepasa/whatsapp"
+)
+
+const (
+	QpChatMessagesDefaultLimit = 50
+	QpChatMessagesMaxLimit     = 500
+)
+
+// GetChats returns a summary for each chat with cached messages, most recent first
+// Follows the message cache, chats whose messages expired or were cleaned up are not listed
+func (source *QpWhatsappServer) GetChats() (chats []QpChatSummary) {
+	if source.Handler == nil {
+		return
+	}
+
+	for chatid, messages := range source.Handler.GetGroupedByChat() {
+		chat := QpChatSummary{Total: len(messages)}
+		chat.Id = chatid
+
+		read := source.Handler.GetChatRead(chatid)
+		for _, msg := range messages {
+			if chat.LastMessage == nil || qpMessageSearchNewer(msg, chat.LastMessage) {
+				chat.LastMessage = msg
+			}
+
+			if !msg.FromMe && msg.Type != whatsapp.SystemMessageType && msg.Timestamp.After(read) {
+				chat.Unread++
+			}
+
+			// reusing chat info from messages
+			if len(chat.Title) == 0 && len(msg.Chat.Title) > 0 {
+				chat.WhatsappChat = msg.Chat
+			}
+		}
+
+		if len(chat.Title) == 0 {
+			chat.Title = source.GetChatTitle(chatid)
+		}
+
+		if source.connection != nil && !source.connection.IsInterfaceNil() {
+			settings, err := source.connection.GetChatSettings(chatid)
+			if err == nil && settings.Found {
+				chat.Archived = settings.Archived
+				chat.Pinned = settings.Pinned
+				chat.MutedUntil = settings.MutedUntil
+			}
+		}
+
+		chats = append(chats, chat)
+	}
+
+	sort.Slice(chats, func(i, j int) bool {
+		return qpMessageSearchNewer(chats[i].LastMessage, chats[j].LastMessage)
+	})
+
+	return
+}
+
+// GetChatMessages returns a page of a chat conversation, newer first
+// before: message id, returns older messages | after: message id, returns newer messages
+func (source *QpWhatsappServer) GetChatMessages(chatid string, before string, after string, limit int) (response *QpChatMessagesResponse, err error) {
+	if source.Handler == nil {
+		err = fmt.Errorf("messages handler not ready")
+		return
+	}
+
+	if len(before) > 0 && len(after) > 0 {
+		err = fmt.Errorf("use only one cursor, before or after")
+		return
+	}
+
+	if limit <= 0 {
+		limit = QpChatMessagesDefaultLimit
+	} else if limit > QpChatMessagesMaxLimit {
+		limit = QpChatMessagesMaxLimit
+	}
+
+	messages := source.Handler.GetByChat(chatid)
+
+	// newer first
+	sort.Slice(messages, func(i, j int) bool {
+		return qpMessageSearchNewer(messages[i], messages[j])
+	})
+
+	findCursor := func(id string) (int, error) {
+		for i, msg := range messages {
+			if strings.EqualFold(msg.Id, id) {
+				return i, nil
+			}
+		}
+		return -1, fmt.Errorf("cursor message not present on cache, id: %s", id)
+	}
+
+	start := 0
+	end := len(messages)
+	if len(before) > 0 {
+		index, cursorErr := findCursor(before)
+		if cursorErr != nil {
+			err = cursorErr
+			return
+		}
+		start = index + 1
+	} else if len(after) > 0 {
+		index, cursorErr := findCursor(after)
+		if cursorErr != nil {
+			err = cursorErr
+			return
+		}
+
+		// closest newer messages to the cursor
+		end = index
+		start = end - limit
+		if start < 0 {
+			start = 0
+		}
+	}
+
+	if start+limit < end {
+		end = start + limit
+	}
+
+	response = &QpChatMessagesResponse{}
+	response.Chat = whatsapp.WhatsappChat{Id: chatid, Title: source.GetChatTitle(chatid)}
+
+	for _, msg := range messages[start:end] {
+		response.Messages = append(response.Messages, *msg)
+	}
+
+	if end > start {
+		if end < len(messages) {
+			response.Before = messages[end-1].Id
+		}
+
+		if start > 0 {
+			response.After = messages[start].Id
+		}
+	}
+
+	return
+}
@@ -63,7 +63,7 @@ func PostToDispatchingFromServer(server *QpWhatsappServer, message *whatsapp.Wha
 var ErrServerNotFound error = errors.New("the requested whatsapp server was not found")
 
 func GetServerFromID(source string) (server *QpWhatsappServer, err error) {
-	server, ok := WhatsappService.Servers[source]
+	server, ok := WhatsappService.GetServer(source)
 	if !ok {
 		err = ErrServerNotFound
 		return
@@ -77,7 +77,7 @@ func GetServerFromBot(source QPBot) (server *QpWhatsappServer, err error) {
 
 // insecure
 func GetServerFirstAvailable() (server *QpWhatsappServer, err error) {
-	for _, item := range WhatsappService.Servers {
+	for _, item := range WhatsappService.GetServers() {
 		if item != nil && item.GetStatus() == whatsapp.Ready {
 			server = item
 			break
@@ -92,7 +92,7 @@ func GetServerFirstAvailable() (server *QpWhatsappServer, err error) {
 }
 
 func GetServerFromToken(token string) (server *QpWhatsappServer, err error) {
-	for _, item := range WhatsappService.Servers {
+	for _, item := range WhatsappService.GetServers() {
 		if item != nil && strings.EqualFold(item.Token, token) {
 			server = item
 			break
@@ -7,6 +7,7 @@ import (
 	"time"
 
 	"github.com/google/uuid"
+	environment "github.com/nocodeleaks/quepasa/environment"
 	library "github.com/nocodeleaks/quepasa/library"
 	whatsapp "github.com/nocodeleaks/quepasa/whatsapp"
 	log "github.com/sirupsen/logrus"
@@ -19,7 +20,7 @@ type QPWhatsappService struct {
 	Initialized bool                         `json:"-"`
 
 	initlock   *sync.Mutex `json:"-"`
-	appendlock *sync.Mutex `json:"-"`
+	appendlock *sync.Mutex `json:"-"` // guards servers cache
 
 	library.LogStruct
 }
@@ -49,7 +50,32 @@ func QPWhatsappStart(logentry *log.Entry) error {
 			return err
 		}
 		// iniciando servidores e cada bot individualmente
-		return WhatsappService.Initialize()
+		err = WhatsappService.Initialize()
+		if err != nil {
+			return err
+		}
+
+		// claiming sessions for this node, if running on cluster mode
+		err = ClusterManager.Initialize()
+		if err != nil {
+			return err
+		}
+
+		// applying reloaded settings and server overrides of the config file
+		environment.ConfigFile.OnReload(OnConfigReload)
+
+		// watching sessions health, restarting the unhealthy ones
+		Watchdog.Initialize()
+
+		// recording administrative and sending actions
+		AuditManager.Initialize()
+
+		// resuming campaigns, servers not ready yet are awaited by runners
+		err = CampaignManager.Initialize()
+		if err != nil {
+			logentry.Errorf("error on initializing campaigns: %s", err.Error())
+		}
+		return nil
 	} else {
 		logentry.Debug("attempt to start whatsapp service, already started ...")
 	}
@@ -62,6 +88,9 @@ func QPWhatsappStart(logentry *log.Entry) error {
 func (source *QPWhatsappService) AppendNewServer(info *QpServer) (server *QpWhatsappServer, err error) {
 	logentry := source.GetLogger()
 
+	source.appendlock.Lock()
+	defer source.appendlock.Unlock()
+
 	// checking if it is cached already
 	server, ok := source.Servers[info.Token]
 	if !ok {
@@ -75,6 +104,13 @@ func (source *QPWhatsappService) AppendNewServer(info *QpServer) (server *QpWhat
 			return
 		}
 
+		// before starting, so overrides of the config file are used
+		err = ApplyConfigServer(server)
+		if err != nil {
+			logentry.Errorf("config file, overrides not applied for server: %s, :: %s", info.Wid, err.Error())
+			err = nil
+		}
+
 		source.Servers[info.Token] = server
 	} else {
 		// updating cached item
@@ -88,6 +124,20 @@ func (source *QPWhatsappService) AppendNewServer(info *QpServer) (server *QpWhat
 func (source *QPWhatsappService) AppendPaired(paired *QpWhatsappPairing) (server *QpWhatsappServer, err error) {
 	logger := source.GetLogger()
 
+	// paired here, so served here, refused if running on another cluster node
+	if !ClusterManager.Acquire(paired.Token) {
+		if paired.conn != nil && !paired.conn.IsInterfaceNil() {
+			if derr := paired.conn.Delete(); derr != nil {
+				logger.Errorf("error on removing refused pairing: %s, %s", paired.Token, derr.Error())
+			}
+		}
+
+		err = fmt.Errorf("server: %s, is leased by another cluster node, pairing refused", paired.Token)
+		return
+	}
+
+	source.appendlock.Lock()
+
 	// checking if it is cached already
 	server, ok := source.Servers[paired.Token]
 	if !ok {
@@ -99,6 +149,7 @@ func (source *QPWhatsappService) AppendPaired(paired *QpWhatsappPairing) (server
 		// Creating a new instance
 		server, err = source.NewQpWhatsappServer(info)
 		if err != nil {
+			source.appendlock.Unlock()
 			logger.Errorf("error on append new server: %s, :: %s", info.Wid, err.Error())
 			return
 		}
@@ -112,6 +163,8 @@ func (source *QPWhatsappService) AppendPaired(paired *QpWhatsappPairing) (server
 		logger.Infof("updating paired server on cache: %s, old wid: %s, new wid: %s", server.Token, server.Wid, paired.Wid)
 	}
 
+	source.appendlock.Unlock()
+
 	server.connection = paired.conn
 	server.Verified = true
 
@@ -183,7 +236,7 @@ func (source *QPWhatsappService) GetOrCreateServerFromToken(token string) (serve
 	logger := source.GetLogger()
 	logger.Debugf("locating server: %s", token)
 
-	server, ok := source.Servers[token]
+	server, ok := source.GetServer(token)
 	if !ok {
 		logger.Debugf("server: %s, not in cache, looking up database", token)
 		exists, err := source.DB.Servers.Exists(token)
@@ -265,10 +318,33 @@ func (service *QPWhatsappService) Delete(server *QpWhatsappServer) (err error) {
 		return
 	}
 
+	service.appendlock.Lock()
 	delete(service.Servers, server.Token)
+	service.appendlock.Unlock()
 	return
 }
 
+// GetServers returns a copy of cached servers, safe to iterate while servers are appended or deleted
+func (source *QPWhatsappService) GetServers() []*QpWhatsappServer {
+	source.appendlock.Lock()
+	defer source.appendlock.Unlock()
+
+	servers := make([]*QpWhatsappServer, 0, len(source.Servers))
+	for _, server := range source.Servers {
+		servers = append(servers, server)
+	}
+	return servers
+}
+
+// GetServer returns the cached server for the exact token
+func (source *QPWhatsappService) GetServer(token string) (*QpWhatsappServer, bool) {
+	source.appendlock.Lock()
+	defer source.appendlock.Unlock()
+
+	server, ok := source.Servers[token]
+	return server, ok
+}
+
 // method that will initiate all servers from database
 func (source *QPWhatsappService) Initialize() (err error) {
 
@@ -286,6 +362,11 @@ func (source *QPWhatsappService) Initialize() (err error) {
 
 			logentry := source.GetLogger()
 
+			// on cluster mode, servers are started only when claimed by this node
+			if ClusterManager.Enabled() {
+				continue
+			}
+
 			state := server.GetStatus()
 			if state == whatsapp.UnPrepared || IsValidToStart(state) {
 
@@ -306,7 +387,7 @@ func (source *QPWhatsappService) Initialize() (err error) {
 // Função privada que irá iniciar todos os servidores apartir do banco de dados
 func (service *QPWhatsappService) GetServersForUser(username string) (servers map[string]*QpWhatsappServer) {
 	servers = make(map[string]*QpWhatsappServer)
-	for _, server := range service.Servers {
+	for _, server := range service.GetServers() {
 		if server.GetOwnerID() == username {
 			servers[strings.ToLower(server.Token)] = server
 		}
@@ -316,7 +397,7 @@ func (service *QPWhatsappService) GetServersForUser(username string) (servers ma
 
 // Case insensitive
 func (service *QPWhatsappService) FindByToken(token string) (*QpWhatsappServer, error) {
-	for _, server := range service.Servers {
+	for _, server := range service.GetServers() {
 		if strings.EqualFold(server.Token, token) {
 			return server, nil
 		}
@@ -329,13 +410,13 @@ func (service *QPWhatsappService) FindByToken(token string) (*QpWhatsappServer,
 func (source *QPWhatsappService) GetUser(username string, password string) (user *QpUser, err error) {
 	logger := source.GetLogger()
 	logger.Debugf("finding user: %s", username)
-	return source.DB.Users.Check(username, password)
+	return UserManager.Login(username, password)
 }
 
 //region CONTROLLER - HEALTH
 
 func (source *QPWhatsappService) GetHealth() (items []QpHealthResponseItem) {
-	for _, server := range source.Servers {
+	for _, server := range source.GetServers() {
 		item := ToHealthReponseItem(server)
 		items = append(items, item)
 	}
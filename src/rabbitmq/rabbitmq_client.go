@@ -231,6 +231,7 @@ func (r *RabbitMQClient) processCache() {
 						false,          // immediate
 						amqp.Publishing{
 							ContentType:  "application/json",
+							Headers:      msg.GetHeaders(),
 							Body:         body,
 							DeliveryMode: amqp.Persistent,
 						})
@@ -289,12 +290,18 @@ func (r *RabbitMQClient) Close() {
 // It accepts any Go type as messageContent, which will be marshaled into the 'payload' field of RabbitMQMessage.
 // If the connection is unavailable, it caches the message. This method provides exchange-based routing.
 func (r *RabbitMQClient) PublishMessageToExchange(exchangeName, routingKey string, messageContent any) {
+	r.PublishMessageToExchangeWithHeaders(exchangeName, routingKey, messageContent, nil)
+}
+
+// PublishMessageToExchangeWithHeaders publishes like PublishMessageToExchange, with AMQP headers (kept while cached)
+func (r *RabbitMQClient) PublishMessageToExchangeWithHeaders(exchangeName, routingKey string, messageContent any, headers map[string]string) {
 	msg := RabbitMQMessage{
 		ID:         fmt.Sprintf("msg-%d", time.Now().UnixNano()),
 		Payload:    messageContent,
 		Timestamp:  time.Now(),
 		Exchange:   exchangeName,
 		RoutingKey: routingKey,
+		Headers:    headers,
 	}
 
 	ch := r.channel
@@ -332,6 +339,7 @@ func (r *RabbitMQClient) PublishMessageToExchange(exchangeName, routingKey strin
 		false,        // immediate
 		amqp.Publishing{
 			ContentType:  "application/json",
+			Headers:      msg.GetHeaders(),
 			Body:         body,
 			DeliveryMode: amqp.Persistent,
 		})
@@ -453,6 +461,11 @@ func (r *RabbitMQClient) PublishQuePasaMessage(routingKey string, messageContent
 	r.PublishMessageToExchange(QuePasaExchangeName, routingKey, messageContent)
 }
 
+// PublishQuePasaMessageWithHeaders publishes to the standard QuePasa Exchange with AMQP headers, ex: trace context
+func (r *RabbitMQClient) PublishQuePasaMessageWithHeaders(routingKey string, messageContent any, headers map[string]string) {
+	r.PublishMessageToExchangeWithHeaders(QuePasaExchangeName, routingKey, messageContent, headers)
+}
+
 // IsConnectionReady checks if the RabbitMQ connection and channel are ready
 func (r *RabbitMQClient) IsConnectionReady() bool {
 	r.mu.RLock()
@@ -1,6 +1,10 @@
 package rabbitmq
 
-import "time"
+import (
+	"time"
+
+	amqp "github.com/rabbitmq/amqp091-go"
+)
 
 // RabbitMQMessage represents the structure of the JSON to be sent to RabbitMQ Exchange.
 // QuePasa always uses exchange-based routing, never direct queue publishing.
@@ -10,4 +14,19 @@ type RabbitMQMessage struct {
 	Timestamp  time.Time `json:"timestamp"`
 	Exchange   string    `json:"exchange"`    // Exchange name for routing
 	RoutingKey string    `json:"routing_key"` // Routing key for exchange routing
+
+	Headers map[string]string `json:"-"` // AMQP headers, ex: trace context (traceparent)
+}
+
+// GetHeaders returns headers as AMQP table, nil if empty
+func (source RabbitMQMessage) GetHeaders() amqp.Table {
+	if len(source.Headers) == 0 {
+		return nil
+	}
+
+	table := amqp.Table{}
+	for key, value := range source.Headers {
+		table[key] = value
+	}
+	return table
 }
@@ -28,7 +28,8 @@ func ServeSignalR(r chi.Router) {
 	r.Group(func(r chi.Router) {
 		log.Debug("starting signalr service")
 
-		factory := signalr.UseHub(SignalRHub)
+		// new hub instance per invocation, connection state is kept on SignalRHub
+		factory := signalr.HubFactory(SignalRHubFactory)
 		//keepalive := signalr.KeepAliveInterval(2 * time.Second)
 		//timeout := signalr.ChanReceiveTimeout(1 * time.Hour)
 
@@ -45,8 +46,12 @@ func ServeSignalR(r chi.Router) {
 		server, err := signalr.NewServer(ctx, factory, slogger)
 		if err != nil {
 			logentry.Errorf("error on set signalr server: %s", err.Error())
+			return
 		}
 
+		// allows server side code to push events
+		SignalRHub.SetServer(server)
+
 		mappable := WithChiRouter(r)
 		server.MapHTTP(mappable, "/signalr")
 	})
@@ -0,0 +1,50 @@
+package signalr
+
+import whatsapp "github.com/nocodeleaks/quepasa/whatsapp"
+
+// ISignalRCommands is implemented outside this package (models), avoiding circular dependencies
+// All methods act on the server identified by the connection token
+type ISignalRCommands interface {
+	// Send a message, text and/or attachment from url or base64 content
+	Send(token string, request *SignalRSendRequest) (*SignalRSendResponse, error)
+
+	// Mark a message as read
+	MarkRead(token string, messageid string) error
+
+	// Cached messages, optional chat filter, newer than timestamp (unix seconds), ordered desc
+	GetMessages(token string, chatid string, timestamp int64) ([]whatsapp.WhatsappMessage, error)
+}
+
+// SignalRSendRequest is the client invoked send command argument
+type SignalRSendRequest struct {
+	ChatId   string `json:"chatid"`
+	Text     string `json:"text,omitempty"`
+	InReply  string `json:"inreply,omitempty"`
+	TrackId  string `json:"trackid,omitempty"`
+	Url      string `json:"url,omitempty"`      // attachment public url
+	Content  string `json:"content,omitempty"`  // attachment base64 content
+	FileName string `json:"filename,omitempty"` // attachment file name
+}
+
+// SignalRSendResponse is the client invoked send command result
+type SignalRSendResponse struct {
+	Success bool   `json:"success"`
+	Status  string `json:"status,omitempty"`
+	Wid     string `json:"wid,omitempty"`
+	Id      string `json:"id,omitempty"`
+	ChatId  string `json:"chatid,omitempty"`
+	TrackId string `json:"trackid,omitempty"`
+}
+
+// SignalRMessagesResponse is the client invoked history command result
+type SignalRMessagesResponse struct {
+	Success  bool                       `json:"success"`
+	Status   string                     `json:"status,omitempty"`
+	Messages []whatsapp.WhatsappMessage `json:"messages,omitempty"`
+}
+
+// SignalRCommandResponse is a generic client invoked command result
+type SignalRCommandResponse struct {
+	Success bool   `json:"success"`
+	Status  string `json:"status,omitempty"`
+}
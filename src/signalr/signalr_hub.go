@@ -1,97 +1,164 @@
 package signalr
 
 import (
-	"fmt"
 	"strings"
+	"sync"
 
 	"github.com/nocodeleaks/quepasa/environment"
 	whatsapp "github.com/nocodeleaks/quepasa/whatsapp"
 	signalr "github.com/philippseith/signalr"
 )
 
+// signalr group that receives events from all servers, joined by master key connections
+const SignalRMasterGroup = "master"
+
+// QpSignalRHub tracks active connections and their tokens, thread safe
+// Events are pushed from server side code using per token groups
 type QpSignalRHub struct {
-	signalr.Hub
-	tokens map[string]string
-	proxy  map[string]signalr.ClientProxy
+	mutex    sync.RWMutex
+	tokens   map[string]string // connection id => token
+	server   signalr.Server    // used to reach clients from non hub code
+	commands ISignalRCommands  // client invoked commands implementation
 }
 
 var SignalRHub = &QpSignalRHub{
 	tokens: map[string]string{},
-	proxy:  map[string]signalr.ClientProxy{},
 }
 
-// not used
+// Creates a new hub instance for each invocation, all sharing the global connection manager
 func SignalRHubFactory() signalr.HubInterface {
-	return SignalRHub
+	return &QpSignalRHubConnection{manager: SignalRHub}
 }
 
 func (source *QpSignalRHub) IsInterfaceNil() bool {
 	return source == nil
 }
 
-func (source *QpSignalRHub) OnConnected(ConnectionId string) {
-	info, _ := source.Logger()
-	info.Log("connection", ConnectionId, "status", "connected")
+func (source *QpSignalRHub) SetServer(server signalr.Server) {
+	source.mutex.Lock()
+	defer source.mutex.Unlock()
+	source.server = server
+}
+
+// SetCommands registers the implementation for client invoked commands (send, mark read, history)
+func (source *QpSignalRHub) SetCommands(commands ISignalRCommands) {
+	source.mutex.Lock()
+	defer source.mutex.Unlock()
+	source.commands = commands
+}
+
+func (source *QpSignalRHub) GetCommands() ISignalRCommands {
+	source.mutex.RLock()
+	defer source.mutex.RUnlock()
+	return source.commands
+}
+
+// GetGroupName returns the signalr group for a server token, master key connections use a common group
+func (source *QpSignalRHub) GetGroupName(token string) string {
+	masterkey := environment.Settings.API.MasterKey
+	if len(masterkey) > 0 && strings.EqualFold(masterkey, token) {
+		return SignalRMasterGroup
+	}
+	return "token:" + token
+}
+
+// register a token for the connection, returning the previous one if exists
+func (source *QpSignalRHub) register(ConnectionId string, token string) (previous string) {
+	source.mutex.Lock()
+	defer source.mutex.Unlock()
 
-	source.proxy[ConnectionId] = source.Clients().Caller()
+	previous = source.tokens[ConnectionId]
+	source.tokens[ConnectionId] = token
+	return
 }
 
-func (source *QpSignalRHub) OnDisconnected(ConnectionId string) {
-	info, _ := source.Logger()
-	info.Log("connection", ConnectionId, "status", "disconnected")
+// unregister the connection, returning its token if exists
+func (source *QpSignalRHub) unregister(ConnectionId string) (token string) {
+	source.mutex.Lock()
+	defer source.mutex.Unlock()
 
+	token = source.tokens[ConnectionId]
 	delete(source.tokens, ConnectionId)
-	delete(source.proxy, ConnectionId)
+	return
 }
 
-func (source *QpSignalRHub) TrySend(ConnectionId string, target string, args ...interface{}) {
+func (source *QpSignalRHub) GetToken(ConnectionId string) string {
+	source.mutex.RLock()
+	defer source.mutex.RUnlock()
+	return source.tokens[ConnectionId]
+}
+
+func (source *QpSignalRHub) GetActiveConnections(token string) (active []string) {
 	if source == nil {
 		return
 	}
 
-	proxy := source.proxy[ConnectionId]
-	if proxy != nil {
-		proxy.Send(target, args...)
+	masterkey := environment.Settings.API.MasterKey
+
+	source.mutex.RLock()
+	defer source.mutex.RUnlock()
+
+	for ConnectionId, _token := range source.tokens {
+		if (len(masterkey) > 0 && strings.EqualFold(masterkey, _token)) || _token == token {
+			active = append(active, ConnectionId)
+		}
 	}
+
+	return
 }
 
-func (source *QpSignalRHub) GetToken() string {
-	ConnectionId := source.ConnectionID()
-	token := source.tokens[ConnectionId]
+func (source *QpSignalRHub) HasActiveConnections(token string) bool {
+	connections := source.GetActiveConnections(token)
+	return len(connections) > 0
+}
 
-	message := fmt.Sprintf("connection id: %s, token: %s", ConnectionId, token)
-	source.Clients().Caller().Send(ConnectionId, "system", message)
-	return token
+// GetConnectionsCount returns the number of connections with a registered token
+func (source *QpSignalRHub) GetConnectionsCount() int {
+	source.mutex.RLock()
+	defer source.mutex.RUnlock()
+	return len(source.tokens)
 }
 
-func (source *QpSignalRHub) Token(token string) {
-	ConnectionId := source.ConnectionID()
-	source.tokens[ConnectionId] = token
+// TrySend pushes an event to a specific connection
+func (source *QpSignalRHub) TrySend(ConnectionId string, target string, args ...interface{}) {
+	if source == nil {
+		return
+	}
+
+	source.mutex.RLock()
+	server := source.server
+	source.mutex.RUnlock()
 
-	info, _ := source.Logger()
-	info.Log("connection", ConnectionId, "token", token)
+	if server != nil {
+		server.HubClients().Client(ConnectionId).Send(target, args...)
+	}
 }
 
-func (source *QpSignalRHub) GetActiveConnections(token string) (active []string) {
-	if source != nil {
-		masterkey := environment.Settings.API.MasterKey
-		for ConnectionId, _token := range source.tokens {
-			if strings.EqualFold(masterkey, _token) || _token == token {
-				active = append(active, ConnectionId)
-			}
-		}
+// DispatchTo pushes an event on a specific target for all connections of the token and master key connections
+func (source *QpSignalRHub) DispatchTo(token string, target string, args ...interface{}) {
+	if source == nil {
+		return
 	}
 
-	return
-}
+	source.mutex.RLock()
+	server := source.server
+	source.mutex.RUnlock()
 
-func (source *QpSignalRHub) Dispatch(token string, payload *whatsapp.WhatsappMessage) {
-	for _, ConnectionId := range source.GetActiveConnections(token) {
-		source.TrySend(ConnectionId, "message", payload)
+	if server == nil {
+		return
+	}
+
+	clients := server.HubClients()
+	group := source.GetGroupName(token)
+	clients.Group(group).Send(target, args...)
+
+	if group != SignalRMasterGroup {
+		clients.Group(SignalRMasterGroup).Send(target, args...)
 	}
 }
 
-func (source *QpSignalRHub) HasActiveConnections(token string) bool {
-	connections := source.GetActiveConnections(token)
-	return len(connections) > 0
+// Dispatch pushes the payload on the typed target, based on its origin
+func (source *QpSignalRHub) Dispatch(token string, payload *whatsapp.WhatsappMessage, from string) {
+	target := GetSignalRTarget(payload, from)
+	source.DispatchTo(token, target, payload)
 }
@@ -0,0 +1,123 @@
+package signalr
+
+import (
+	"fmt"
+
+	signalr "github.com/philippseith/signalr"
+)
+
+// QpSignalRHubConnection is instantiated for each hub invocation
+// Public methods are invoked by clients, state lives on the shared manager
+type QpSignalRHubConnection struct {
+	signalr.Hub
+	manager *QpSignalRHub
+}
+
+func (source *QpSignalRHubConnection) OnConnected(ConnectionId string) {
+	info, _ := source.Logger()
+	info.Log("connection", ConnectionId, "status", "connected")
+}
+
+func (source *QpSignalRHubConnection) OnDisconnected(ConnectionId string) {
+	info, _ := source.Logger()
+	info.Log("connection", ConnectionId, "status", "disconnected")
+
+	token := source.manager.unregister(ConnectionId)
+	if len(token) > 0 {
+		source.Groups().RemoveFromGroup(source.manager.GetGroupName(token), ConnectionId)
+	}
+}
+
+//#region CLIENT INVOKED METHODS
+
+// Token registers the server token for this connection, joining its group
+func (source *QpSignalRHubConnection) Token(token string) {
+	ConnectionId := source.ConnectionID()
+
+	previous := source.manager.register(ConnectionId, token)
+	if len(previous) > 0 && previous != token {
+		source.Groups().RemoveFromGroup(source.manager.GetGroupName(previous), ConnectionId)
+	}
+
+	source.Groups().AddToGroup(source.manager.GetGroupName(token), ConnectionId)
+
+	info, _ := source.Logger()
+	info.Log("connection", ConnectionId, "token", token)
+}
+
+func (source *QpSignalRHubConnection) GetToken() string {
+	ConnectionId := source.ConnectionID()
+	token := source.manager.GetToken(ConnectionId)
+
+	message := fmt.Sprintf("connection id: %s, token: %s", ConnectionId, token)
+	source.Clients().Caller().Send(SignalRTargetSystem, message)
+	return token
+}
+
+// Send a message using the connection token server
+func (source *QpSignalRHubConnection) Send(request SignalRSendRequest) *SignalRSendResponse {
+	token, commands, err := source.getCommandContext()
+	if err != nil {
+		return &SignalRSendResponse{Status: err.Error()}
+	}
+
+	response, err := commands.Send(token, &request)
+	if err != nil {
+		return &SignalRSendResponse{Status: err.Error()}
+	}
+
+	return response
+}
+
+// MarkRead marks a message as read using the connection token server
+func (source *QpSignalRHubConnection) MarkRead(messageid string) *SignalRCommandResponse {
+	token, commands, err := source.getCommandContext()
+	if err != nil {
+		return &SignalRCommandResponse{Status: err.Error()}
+	}
+
+	err = commands.MarkRead(token, messageid)
+	if err != nil {
+		return &SignalRCommandResponse{Status: err.Error()}
+	}
+
+	return &SignalRCommandResponse{Success: true, Status: "marked as read"}
+}
+
+// GetMessages fetches history from cache, optional chat filter, newer than timestamp (unix seconds)
+func (source *QpSignalRHubConnection) GetMessages(chatid string, timestamp int64) *SignalRMessagesResponse {
+	token, commands, err := source.getCommandContext()
+	if err != nil {
+		return &SignalRMessagesResponse{Status: err.Error()}
+	}
+
+	messages, err := commands.GetMessages(token, chatid, timestamp)
+	if err != nil {
+		return &SignalRMessagesResponse{Status: err.Error()}
+	}
+
+	status := fmt.Sprintf("%d messages", len(messages))
+	return &SignalRMessagesResponse{Success: true, Status: status, Messages: messages}
+}
+
+//#endregion
+
+// getCommandContext validates that this connection can invoke server commands
+func (source *QpSignalRHubConnection) getCommandContext() (token string, commands ISignalRCommands, err error) {
+	token = source.manager.GetToken(source.ConnectionID())
+	if len(token) == 0 {
+		err = fmt.Errorf("token not registered for this connection, invoke Token first")
+		return
+	}
+
+	if source.manager.GetGroupName(token) == SignalRMasterGroup {
+		err = fmt.Errorf("commands are not available for master key connections")
+		return
+	}
+
+	commands = source.manager.GetCommands()
+	if commands == nil {
+		err = fmt.Errorf("commands not available")
+	}
+	return
+}
@@ -0,0 +1,39 @@
+package signalr
+
+import whatsapp "github.com/nocodeleaks/quepasa/whatsapp"
+
+// Client side targets (methods) that receive server pushed events
+const (
+	SignalRTargetMessage    = "message"    // messages received or sent
+	SignalRTargetReceipt    = "receipt"    // read receipts and message status updates
+	SignalRTargetConnection = "connection" // connection state changes (connected, disconnected, logged out)
+	SignalRTargetCall       = "call"       // incoming calls
+	SignalRTargetGroup      = "group"      // group events (joined, participants, info changes)
+	SignalRTargetSystem     = "system"     // hub information for the caller
+)
+
+// GetSignalRTarget returns the typed client target for a payload, based on its origin
+func GetSignalRTarget(payload *whatsapp.WhatsappMessage, from string) string {
+	switch from {
+	case "receipt":
+		return SignalRTargetReceipt
+	case "connection", "logout":
+		return SignalRTargetConnection
+	case "call":
+		return SignalRTargetCall
+	case "group":
+		return SignalRTargetGroup
+	}
+
+	if payload != nil {
+		if payload.Type == whatsapp.CallMessageType {
+			return SignalRTargetCall
+		}
+
+		if payload.Id == "readreceipt" {
+			return SignalRTargetReceipt
+		}
+	}
+
+	return SignalRTargetMessage
+}
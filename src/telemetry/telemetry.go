@@ -0,0 +1,83 @@
+package telemetry
+
+import (
+	"context"
+	"time"
+
+	environment "github.com/nocodeleaks/quepasa/environment"
+	log "github.com/sirupsen/logrus"
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/exporters/otlp/otlptrace/otlptracehttp"
+	"go.opentelemetry.io/otel/propagation"
+	"go.opentelemetry.io/otel/sdk/resource"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+	semconv "go.opentelemetry.io/otel/semconv/v1.26.0"
+	"go.opentelemetry.io/otel/trace"
+)
+
+// instrumentation scope of all quepasa spans
+const TracerName = "github.com/nocodeleaks/quepasa"
+
+var provider *sdktrace.TracerProvider
+
+// Propagator is always set, so trace context is forwarded (webhooks, amqp) even with export disabled
+func init() {
+	otel.SetTextMapPropagator(propagation.NewCompositeTextMapPropagator(propagation.TraceContext{}, propagation.Baggage{}))
+}
+
+// Start configures the OTLP exporter and the global tracer provider, if enabled on settings
+func Start(logentry *log.Entry) error {
+	settings := environment.Settings.Telemetry
+	if !settings.Enabled {
+		logentry.Debug("telemetry disabled")
+		return nil
+	}
+
+	options := []otlptracehttp.Option{}
+	if len(settings.Endpoint) > 0 {
+		options = append(options, otlptracehttp.WithEndpointURL(settings.Endpoint))
+	}
+
+	exporter, err := otlptracehttp.New(context.Background(), options...)
+	if err != nil {
+		return err
+	}
+
+	res, err := resource.Merge(resource.Default(), resource.NewWithAttributes(semconv.SchemaURL,
+		semconv.ServiceName(settings.ServiceName),
+	))
+	if err != nil {
+		return err
+	}
+
+	ratio := float64(settings.SamplePercent) / 100
+	provider = sdktrace.NewTracerProvider(
+		sdktrace.WithBatcher(exporter),
+		sdktrace.WithResource(res),
+		sdktrace.WithSampler(sdktrace.ParentBased(sdktrace.TraceIDRatioBased(ratio))),
+	)
+
+	otel.SetTracerProvider(provider)
+	logentry.Infof("telemetry started, service: %s, sample: %d%%", settings.ServiceName, settings.SamplePercent)
+	return nil
+}
+
+// Shutdown flushes pending spans
+func Shutdown() {
+	if provider == nil {
+		return
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+
+	err := provider.Shutdown(ctx)
+	if err != nil {
+		log.Errorf("telemetry, error on shutdown: %s", err.Error())
+	}
+}
+
+// Tracer returns the quepasa tracer, no-op while telemetry is not started
+func Tracer() trace.Tracer {
+	return otel.Tracer(TracerName)
+}
@@ -0,0 +1,32 @@
+package telemetry
+
+import (
+	"context"
+	"net/http"
+
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/propagation"
+)
+
+// InjectHeaders writes trace context (traceparent) on outgoing http headers
+func InjectHeaders(ctx context.Context, header http.Header) {
+	if ctx == nil {
+		return
+	}
+	otel.GetTextMapPropagator().Inject(ctx, propagation.HeaderCarrier(header))
+}
+
+// InjectMap returns trace context as a map, used on AMQP headers
+func InjectMap(ctx context.Context) map[string]string {
+	carrier := propagation.MapCarrier{}
+	if ctx == nil {
+		return carrier
+	}
+	otel.GetTextMapPropagator().Inject(ctx, carrier)
+	return carrier
+}
+
+// ExtractHeaders returns a context with trace context of incoming http headers
+func ExtractHeaders(ctx context.Context, header http.Header) context.Context {
+	return otel.GetTextMapPropagator().Extract(ctx, propagation.HeaderCarrier(header))
+}
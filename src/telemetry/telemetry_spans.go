@@ -0,0 +1,90 @@
+package telemetry
+
+import (
+	"context"
+
+	whatsapp "github.com/nocodeleaks/quepasa/whatsapp"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/codes"
+	"go.opentelemetry.io/otel/trace"
+)
+
+// span attributes
+const (
+	AttributeMessageId   = attribute.Key("quepasa.message.id")
+	AttributeMessageType = attribute.Key("quepasa.message.type")
+	AttributeTrackId     = attribute.Key("quepasa.message.trackid")
+	AttributeWid         = attribute.Key("quepasa.wid")
+	AttributeChatId      = attribute.Key("quepasa.chat.id")
+	AttributeFrom        = attribute.Key("quepasa.from")
+)
+
+// span kinds, so callers do not depend on otel packages
+const (
+	SpanKindInternal = trace.SpanKindInternal
+	SpanKindServer   = trace.SpanKindServer
+	SpanKindClient   = trace.SpanKindClient
+	SpanKindProducer = trace.SpanKindProducer
+	SpanKindConsumer = trace.SpanKindConsumer
+)
+
+// Wid attribute, for spans where the message does not carry it yet
+func Wid(wid string) attribute.KeyValue {
+	return AttributeWid.String(wid)
+}
+
+// From attribute, origin of the message inside quepasa (ex: whatsmeow, server send)
+func From(from string) attribute.KeyValue {
+	return AttributeFrom.String(from)
+}
+
+// HTTPStatusCode attribute, for http client spans (ex: webhooks)
+func HTTPStatusCode(code int) attribute.KeyValue {
+	return attribute.Int("http.response.status_code", code)
+}
+
+// StartSpan starts an internal span
+func StartSpan(ctx context.Context, name string, attributes ...attribute.KeyValue) (context.Context, trace.Span) {
+	if ctx == nil {
+		ctx = context.Background()
+	}
+	return Tracer().Start(ctx, name, trace.WithAttributes(attributes...))
+}
+
+// StartMessageSpan starts a span child of the message trace context, with message id, wid and chat attributes
+func StartMessageSpan(name string, msg *whatsapp.WhatsappMessage, kind trace.SpanKind, attributes ...attribute.KeyValue) (context.Context, trace.Span) {
+	ctx := context.Background()
+	if msg != nil {
+		if msg.TraceContext != nil {
+			ctx = msg.TraceContext
+		}
+
+		attributes = append(attributes,
+			AttributeMessageId.String(msg.Id),
+			AttributeMessageType.String(msg.Type.String()),
+		)
+
+		if len(msg.Wid) > 0 {
+			attributes = append(attributes, AttributeWid.String(msg.Wid))
+		}
+
+		if len(msg.TrackId) > 0 {
+			attributes = append(attributes, AttributeTrackId.String(msg.TrackId))
+		}
+
+		if msg.Chat.Id != "" {
+			attributes = append(attributes, AttributeChatId.String(msg.Chat.Id))
+		}
+	}
+
+	return Tracer().Start(ctx, name, trace.WithSpanKind(kind), trace.WithAttributes(attributes...))
+}
+
+// EndSpan records the error (if any) and ends the span
+func EndSpan(span trace.Span, err error) {
+	if err != nil {
+		span.RecordError(err)
+		span.SetStatus(codes.Error, err.Error())
+	}
+	span.End()
+}
@@ -1,10 +1,13 @@
 package webserver
 
 import (
+	"context"
 	"net/http"
 	"strings"
 )
 
+type peerAddressContextKey struct{}
+
 func MiddlewareForNormalizePaths(next http.Handler) http.Handler {
 	fn := func(w http.ResponseWriter, r *http.Request) {
 		if r.URL.Path != "" {
@@ -14,3 +17,19 @@ func MiddlewareForNormalizePaths(next http.Handler) http.Handler {
 	}
 	return http.HandlerFunc(fn)
 }
+
+// MiddlewareForPeerAddress keeps the connection address, before RealIP rewrites it from forwarded headers
+func MiddlewareForPeerAddress(next http.Handler) http.Handler {
+	fn := func(w http.ResponseWriter, r *http.Request) {
+		next.ServeHTTP(w, r.WithContext(context.WithValue(r.Context(), peerAddressContextKey{}, r.RemoteAddr)))
+	}
+	return http.HandlerFunc(fn)
+}
+
+// GetPeerAddress returns the address of the connection, ignoring forwarded headers
+func GetPeerAddress(r *http.Request) string {
+	if address, ok := r.Context().Value(peerAddressContextKey{}).(string); ok {
+		return address
+	}
+	return r.RemoteAddr
+}
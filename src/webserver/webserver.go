@@ -1,11 +1,15 @@
 package webserver
 
 import (
+	"context"
+	"errors"
 	"fmt"
 	"net/http"
 	"os"
+	"os/signal"
 	"path/filepath"
 	"strings"
+	"syscall"
 	"time"
 
 	environment "github.com/nocodeleaks/quepasa/environment"
@@ -28,6 +32,9 @@ func RegisterRouterConfigurator(configurator RouterConfigurator) {
 	configurators = append(configurators, configurator)
 }
 
+// maximum time waiting for active requests on shutdown
+const ShutdownTimeout = 10 * time.Second
+
 func WebServerStart(logentry *log.Entry) error {
 	r := newRouter()
 	webAPIPort := environment.Settings.WebServer.Port
@@ -41,13 +48,35 @@ func WebServerStart(logentry *log.Entry) error {
 		Handler:      r,
 	}
 
-	logentry.Infof("starting web server on port: %d", webAPIPort)
-	err := server.ListenAndServe()
-	if err != nil {
-		log.Fatal(err)
+	// stops on SIGINT or SIGTERM, so shutdown hooks can run after
+	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt, syscall.SIGTERM)
+	defer stop()
+
+	failed := make(chan error, 1)
+	go func() {
+		logentry.Infof("starting web server on port: %d", webAPIPort)
+		failed <- server.ListenAndServe()
+	}()
+
+	select {
+	case err := <-failed:
+		logentry.Errorf("web server error: %s", err.Error())
+		return err
+	case <-ctx.Done():
+		logentry.Info("shutting down web server")
+	}
+
+	// waiting for active requests, long lived streams are cut at deadline
+	shutdown, cancel := context.WithTimeout(context.Background(), ShutdownTimeout)
+	defer cancel()
+
+	err := server.Shutdown(shutdown)
+	if err != nil && !errors.Is(err, http.ErrServerClosed) {
+		logentry.Warnf("web server shutdown error: %s", err.Error())
+		server.Close()
 	}
 
-	return err
+	return nil
 }
 
 func newRouter() chi.Router {
@@ -56,6 +85,7 @@ func newRouter() chi.Router {
 	r.Use(MiddlewareForNormalizePaths)
 	r.Use(middleware.StripSlashes)
 	r.Use(middleware.RequestID)
+	r.Use(MiddlewareForPeerAddress)
 	r.Use(middleware.RealIP)
 
 	if environment.Settings.WebServer.Logs {
@@ -0,0 +1,7 @@
+package whatsapp
+
+// WhatsappBusinessCatalog is one page of products of a business catalog
+type WhatsappBusinessCatalog struct {
+	Products []WhatsappBusinessProduct `json:"products"`
+	Cursor   string                    `json:"cursor,omitempty"` // pass on next request to get the following page, empty on last page
+}
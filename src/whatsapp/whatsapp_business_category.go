@@ -0,0 +1,7 @@
+package whatsapp
+
+// WhatsappBusinessCategory is a category of a business profile, like "Shopping & retail"
+type WhatsappBusinessCategory struct {
+	Id   string `json:"id,omitempty"`
+	Name string `json:"name,omitempty"`
+}
@@ -0,0 +1,9 @@
+package whatsapp
+
+// WhatsappBusinessCollection is a named group of products of a business catalog
+type WhatsappBusinessCollection struct {
+	Id       string                    `json:"id"`
+	Name     string                    `json:"name,omitempty"`
+	Status   string                    `json:"status,omitempty"`
+	Products []WhatsappBusinessProduct `json:"products,omitempty"`
+}
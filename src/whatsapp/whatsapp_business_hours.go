@@ -0,0 +1,7 @@
+package whatsapp
+
+// WhatsappBusinessHours is the opening schedule of a business profile
+type WhatsappBusinessHours struct {
+	TimeZone string                     `json:"timezone,omitempty"`
+	Days     []WhatsappBusinessHoursDay `json:"days,omitempty"`
+}
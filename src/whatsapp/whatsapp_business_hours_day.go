@@ -0,0 +1,9 @@
+package whatsapp
+
+// WhatsappBusinessHoursDay is the schedule of one week day of a business profile
+type WhatsappBusinessHoursDay struct {
+	DayOfWeek string `json:"day"`             // sun, mon, tue, wed, thu, fri, sat
+	Mode      string `json:"mode"`            // specific_hours, open_24h or appointment_only
+	OpenTime  int    `json:"open,omitempty"`  // minutes from midnight, only for specific_hours
+	CloseTime int    `json:"close,omitempty"` // minutes from midnight, only for specific_hours
+}
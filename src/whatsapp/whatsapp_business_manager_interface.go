@@ -0,0 +1,14 @@
+package whatsapp
+
+// WhatsappBusinessManagerInterface defines the interface for business profile and catalog operations
+// This interface should be implemented by the business manager in the whatsmeow package
+type WhatsappBusinessManagerInterface interface {
+	// Get the business profile of a contact, or own profile if wid is empty
+	GetBusinessProfile(wid string) (*WhatsappBusinessProfile, error)
+
+	// Get one page of own catalog products, cursor is empty for the first page
+	GetCatalog(limit int, cursor string) (*WhatsappBusinessCatalog, error)
+
+	// Get own catalog collections, with up to limit products each
+	GetCollections(limit int) ([]WhatsappBusinessCollection, error)
+}
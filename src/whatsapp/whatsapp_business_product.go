@@ -0,0 +1,15 @@
+package whatsapp
+
+// WhatsappBusinessProduct is a product of a business catalog
+type WhatsappBusinessProduct struct {
+	Id          string   `json:"id"`
+	RetailerId  string   `json:"retailerid,omitempty"` // seller own sku
+	Name        string   `json:"name,omitempty"`
+	Description string   `json:"description,omitempty"`
+	Url         string   `json:"url,omitempty"`
+	Currency    string   `json:"currency,omitempty"`
+	Price       int64    `json:"price,omitempty"` // amount multiplied by 1000, as whatsapp sends it
+	Hidden      bool     `json:"hidden,omitempty"`
+	Status      string   `json:"status,omitempty"` // review status, like APPROVED or REJECTED
+	Images      []string `json:"images,omitempty"` // image urls
+}
@@ -0,0 +1,12 @@
+package whatsapp
+
+// WhatsappBusinessProfile is the public profile of a whatsapp business account
+type WhatsappBusinessProfile struct {
+	Wid         string                     `json:"wid,omitempty"`
+	Description string                     `json:"description,omitempty"`
+	Categories  []WhatsappBusinessCategory `json:"categories,omitempty"`
+	Address     string                     `json:"address,omitempty"`
+	Email       string                     `json:"email,omitempty"`
+	Websites    []string                   `json:"websites,omitempty"`
+	Hours       *WhatsappBusinessHours     `json:"hours,omitempty"` // nil if not informed
+}
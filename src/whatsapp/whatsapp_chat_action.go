@@ -0,0 +1,22 @@
+package whatsapp
+
+import "time"
+
+// chat actions synchronized from other devices, dispatched as system messages
+const (
+	ChatActionPin         = "pin"
+	ChatActionMute        = "mute"
+	ChatActionStar        = "star"
+	ChatActionDeleteForMe = "deleteforme"
+	ChatActionClear       = "clear"
+)
+
+// WhatsappChatAction is the info of a chat management action made on another device
+type WhatsappChatAction struct {
+	Action      string     `json:"action"`                // pin, mute, star, deleteforme or clear
+	Value       bool       `json:"value"`                 // pinned, muted or starred; false when undone
+	MessageId   string     `json:"messageid,omitempty"`   // target message on star and deleteforme
+	FromMe      bool       `json:"fromme,omitempty"`      // target message was sent by us
+	Participant string     `json:"participant,omitempty"` // target message sender on groups
+	MutedUntil  *time.Time `json:"muteduntil,omitempty"`  // nil when muted forever
+}
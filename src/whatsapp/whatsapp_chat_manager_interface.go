@@ -0,0 +1,25 @@
+package whatsapp
+
+import "time"
+
+// WhatsappChatManagerInterface defines the interface for chat management operations
+// This interface should be implemented by the chat manager in the whatsmeow package
+type WhatsappChatManagerInterface interface {
+	// Pin or unpin a chat
+	PinChat(chatId string, pin bool) error
+
+	// Mute or unmute a chat, zero duration mutes forever
+	MuteChat(chatId string, mute bool, duration time.Duration) error
+
+	// Star or unstar a message
+	StarMessage(msg *WhatsappMessage, starred bool) error
+
+	// Delete a message only for the connected account
+	DeleteMessageForMe(msg *WhatsappMessage, deleteMedia bool) error
+
+	// Clear all messages of a chat
+	ClearChat(chatId string) error
+
+	// Set the disappearing messages timer of a chat, zero disables
+	SetDisappearingTimer(chatId string, timer time.Duration) error
+}
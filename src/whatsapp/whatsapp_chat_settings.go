@@ -0,0 +1,11 @@
+package whatsapp
+
+import "time"
+
+// Local settings of a chat, synchronized from whatsapp app state
+type WhatsappChatSettings struct {
+	Found      bool       `json:"-"`                    // has settings stored for this chat
+	Archived   bool       `json:"archived,omitempty"`   // chat is archived
+	Pinned     bool       `json:"pinned,omitempty"`     // chat is pinned on top
+	MutedUntil *time.Time `json:"muteduntil,omitempty"` // chat notifications muted until
+}
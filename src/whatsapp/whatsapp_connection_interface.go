@@ -37,10 +37,16 @@ type IWhatsappConnection interface {
 	// Default send message method
 	Send(*WhatsappMessage) (IWhatsappSendResponse, error)
 
+	// Forward a cached message to a chat, reusing original content and media
+	Forward(*WhatsappMessage, string) (*WhatsappMessage, error)
+
 	// Useful to check if is a member of a group before send a msg.
 	// Indicates if has an open or archived chat.
 	HasChat(string) bool
 
+	// Local chat settings (archived, pinned, muted), synchronized from app state
+	GetChatSettings(string) (*WhatsappChatSettings, error)
+
 	GetLogger() *log.Entry
 	/*
 		<summary>
@@ -81,6 +87,15 @@ type IWhatsappConnection interface {
 	// GetContactManager returns the contact manager for contact operations
 	GetContactManager() WhatsappContactManagerInterface
 
+	// GetProfileManager returns the profile manager for own profile operations
+	GetProfileManager() WhatsappProfileManagerInterface
+
+	// GetBusinessManager returns the business manager for business profile and catalog operations
+	GetBusinessManager() WhatsappBusinessManagerInterface
+
+	// GetChatManager returns the chat manager for pin, mute, star, clear and disappearing operations
+	GetChatManager() WhatsappChatManagerInterface
+
 	// GetResume returns detailed connection status information
 	// This consolidates all status management functionality in a single method
 	GetResume() *WhatsappConnectionStatus
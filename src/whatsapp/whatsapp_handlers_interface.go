@@ -12,6 +12,9 @@ type IWhatsappHandlers interface {
 	// Update read receipt status
 	Receipt(*WhatsappMessage)
 
+	// Chat management action made on another device
+	ChatAction(*WhatsappMessage)
+
 	// Event
 	LoggedOut(string)
 
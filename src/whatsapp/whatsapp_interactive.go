@@ -0,0 +1,54 @@
+package whatsapp
+
+import "fmt"
+
+// limits accepted by whatsapp clients for native interactive messages
+const (
+	InteractiveMaxButtons  = 3
+	InteractiveMaxListRows = 10
+)
+
+// WhatsappInteractive is a structured native flow message, body text comes from message text
+// Header media comes from message attachment (image, video or document)
+type WhatsappInteractive struct {
+	Header   string                      `json:"header,omitempty"`   // Optional: header title
+	Subtitle string                      `json:"subtitle,omitempty"` // Optional: header subtitle
+	Footer   string                      `json:"footer,omitempty"`   // Optional: footer text
+	Buttons  []WhatsappInteractiveButton `json:"buttons,omitempty"`  // Reply or CTA buttons
+	List     *WhatsappInteractiveList    `json:"list,omitempty"`     // Single select list, alternative to buttons
+}
+
+// Validate checks required fields and whatsapp limits
+func (source *WhatsappInteractive) Validate() error {
+	if len(source.Buttons) == 0 && source.List == nil {
+		return fmt.Errorf("interactive requires buttons or list")
+	}
+
+	if len(source.Buttons) > 0 && source.List != nil {
+		return fmt.Errorf("interactive accepts buttons or list, not both")
+	}
+
+	if len(source.Buttons) > InteractiveMaxButtons {
+		return fmt.Errorf("interactive accepts at most %d buttons", InteractiveMaxButtons)
+	}
+
+	ids := map[string]bool{}
+	for i, button := range source.Buttons {
+		if err := button.Validate(); err != nil {
+			return fmt.Errorf("invalid button (%d): %s", i, err.Error())
+		}
+
+		if button.GetType() == InteractiveButtonReply {
+			if ids[button.Id] {
+				return fmt.Errorf("duplicated button id: %s", button.Id)
+			}
+			ids[button.Id] = true
+		}
+	}
+
+	if source.List != nil {
+		return source.List.Validate()
+	}
+
+	return nil
+}
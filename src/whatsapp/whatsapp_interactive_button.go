@@ -0,0 +1,58 @@
+package whatsapp
+
+import "fmt"
+
+// interactive button types
+const (
+	InteractiveButtonReply = "reply" // quick reply, returns its id
+	InteractiveButtonUrl   = "url"   // opens an url
+	InteractiveButtonCall  = "call"  // calls a phone number
+	InteractiveButtonCopy  = "copy"  // copies a code to clipboard
+)
+
+type WhatsappInteractiveButton struct {
+	Type  string `json:"type,omitempty"`  // Optional: reply (default), url, call or copy
+	Id    string `json:"id,omitempty"`    // Required for reply: id returned when selected
+	Text  string `json:"text"`            // Required: button display text
+	Url   string `json:"url,omitempty"`   // Required for url
+	Phone string `json:"phone,omitempty"` // Required for call
+	Code  string `json:"code,omitempty"`  // Required for copy
+}
+
+// GetType returns the button type, defaults to reply
+func (source WhatsappInteractiveButton) GetType() string {
+	if len(source.Type) == 0 {
+		return InteractiveButtonReply
+	}
+	return source.Type
+}
+
+// Validate checks required fields for each button type
+func (source WhatsappInteractiveButton) Validate() error {
+	if len(source.Text) == 0 {
+		return fmt.Errorf("text is required")
+	}
+
+	switch source.GetType() {
+	case InteractiveButtonReply:
+		if len(source.Id) == 0 {
+			return fmt.Errorf("id is required for reply buttons")
+		}
+	case InteractiveButtonUrl:
+		if len(source.Url) == 0 {
+			return fmt.Errorf("url is required for url buttons")
+		}
+	case InteractiveButtonCall:
+		if len(source.Phone) == 0 {
+			return fmt.Errorf("phone is required for call buttons")
+		}
+	case InteractiveButtonCopy:
+		if len(source.Code) == 0 {
+			return fmt.Errorf("code is required for copy buttons")
+		}
+	default:
+		return fmt.Errorf("unknown button type: %s", source.Type)
+	}
+
+	return nil
+}
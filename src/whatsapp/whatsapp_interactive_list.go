@@ -0,0 +1,43 @@
+package whatsapp
+
+import "fmt"
+
+type WhatsappInteractiveList struct {
+	ButtonText string                           `json:"buttontext"` // Required: text of the button that opens the list
+	Sections   []WhatsappInteractiveListSection `json:"sections"`   // Required: at least one section with rows
+}
+
+// Validate checks required fields, rows limit and unique row ids
+func (source *WhatsappInteractiveList) Validate() error {
+	if len(source.ButtonText) == 0 {
+		return fmt.Errorf("list buttontext is required")
+	}
+
+	if len(source.Sections) == 0 {
+		return fmt.Errorf("list requires at least one section")
+	}
+
+	ids := map[string]bool{}
+	for _, section := range source.Sections {
+		if len(section.Rows) == 0 {
+			return fmt.Errorf("list section without rows: %s", section.Title)
+		}
+
+		for _, row := range section.Rows {
+			if len(row.Id) == 0 || len(row.Title) == 0 {
+				return fmt.Errorf("list rows require id and title")
+			}
+
+			if ids[row.Id] {
+				return fmt.Errorf("duplicated list row id: %s", row.Id)
+			}
+			ids[row.Id] = true
+		}
+	}
+
+	if len(ids) > InteractiveMaxListRows {
+		return fmt.Errorf("list accepts at most %d rows", InteractiveMaxListRows)
+	}
+
+	return nil
+}
@@ -0,0 +1,7 @@
+package whatsapp
+
+type WhatsappInteractiveListRow struct {
+	Id          string `json:"id"`                    // Required: id returned when selected
+	Title       string `json:"title"`                 // Required: row title
+	Description string `json:"description,omitempty"` // Optional: row description
+}
@@ -0,0 +1,6 @@
+package whatsapp
+
+type WhatsappInteractiveListSection struct {
+	Title string                       `json:"title,omitempty"` // Optional: section title
+	Rows  []WhatsappInteractiveListRow `json:"rows"`            // Required: selectable rows
+}
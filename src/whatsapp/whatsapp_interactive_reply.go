@@ -0,0 +1,15 @@
+package whatsapp
+
+// interactive reply types, native flow responses are normalized to button or list
+const (
+	InteractiveReplyButton   = "button"
+	InteractiveReplyList     = "list"
+	InteractiveReplyTemplate = "template"
+)
+
+// WhatsappInteractiveReply is the selection made by a user on buttons, lists or templates
+type WhatsappInteractiveReply struct {
+	Type string `json:"type"`           // button, list or template
+	Id   string `json:"id"`             // selected button or row id
+	Text string `json:"text,omitempty"` // selected display text, if available
+}
@@ -0,0 +1,27 @@
+package whatsapp
+
+import "time"
+
+// whatsapp clients allow sharing live location for at most 8 hours
+const WhatsappLiveLocationMaxDuration = 8 * time.Hour
+
+// WhatsappLiveLocation is a position of a live location sharing, updates are grouped by the original message
+type WhatsappLiveLocation struct {
+	Origin      string    `json:"origin"`                // original live location message id
+	Participant string    `json:"participant,omitempty"` // who is sharing
+	Latitude    float64   `json:"latitude"`
+	Longitude   float64   `json:"longitude"`
+	Accuracy    uint32    `json:"accuracy,omitempty"` // meters
+	Speed       float32   `json:"speed,omitempty"`    // meters per second
+	Heading     uint32    `json:"heading,omitempty"`  // degrees clockwise from magnetic north
+	Caption     string    `json:"caption,omitempty"`
+	Sequence    int64     `json:"sequence,omitempty"`
+	TimeOffset  uint32    `json:"timeoffset,omitempty"` // seconds since the sharing started, 0 on the original message
+	Timestamp   time.Time `json:"timestamp"`
+	Updates     uint      `json:"updates"` // position updates received after the original message
+}
+
+// IsExpired returns true if no update was received within the max sharing duration
+func (source *WhatsappLiveLocation) IsExpired() bool {
+	return time.Since(source.Timestamp) > WhatsappLiveLocationMaxDuration
+}
@@ -5,4 +5,5 @@ type WhatsappLocation struct {
 	Longitude float64 `json:"longitude"`         // Required: Location longitude in degrees
 	Name      string  `json:"name,omitempty"`    // Optional: Location name/description
 	Address   string  `json:"address,omitempty"` // Optional: Location full address
+	Url       string  `json:"url,omitempty"`     // Optional: Venue url
 }
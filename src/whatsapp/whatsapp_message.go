@@ -1,6 +1,7 @@
 package whatsapp
 
 import (
+	"context"
 	"strings"
 	"time"
 )
@@ -14,6 +15,9 @@ type WhatsappMessage struct {
 
 	InfoForHistory any `json:"-"`
 
+	// tracing context, links receive, dispatch and send spans of this message
+	TraceContext context.Context `json:"-"`
+
 	Id      string `json:"id"`                // Upper text msg id
 	TrackId string `json:"trackid,omitempty"` // Optional id of the system that send that message
 
@@ -56,6 +60,15 @@ type WhatsappMessage struct {
 	// Msg in reply preview
 	Synopsis string `json:"synopsis,omitempty"`
 
+	// Quoted message snapshot, used on reply when the original is not cached
+	Quoted *WhatsappQuotedMessage `json:"quoted,omitempty"`
+
+	// Contacts ids explicitly mentioned on send
+	Mentions []string `json:"mentions,omitempty"`
+
+	// Mention all participants of the group on send
+	MentionAll bool `json:"mentionall,omitempty"`
+
 	// Delivered, Read, Imported statuses
 	Status WhatsappMessageStatus `json:"status,omitempty"`
 
@@ -71,9 +84,22 @@ type WhatsappMessage struct {
 	Info any `json:"info,omitempty"`
 
 	Poll     *WhatsappPoll     `json:"poll,omitempty"`     // Poll if exists
+	PollVote *WhatsappPollVote `json:"pollvote,omitempty"` // Poll vote if exists
 	Location *WhatsappLocation `json:"location,omitempty"` // Location if exists
 	Contact  *WhatsappContact  `json:"contact,omitempty"`  // Contact if exists
 
+	// Live location position, grouped by the original message
+	LiveLocation *WhatsappLiveLocation `json:"livelocation,omitempty"`
+
+	// Structured buttons or list to send
+	Interactive *WhatsappInteractive `json:"interactive,omitempty"`
+
+	// Selected button or list row, when this message is a reply to interactive content
+	InteractiveReply *WhatsappInteractiveReply `json:"interactivereply,omitempty"`
+
+	// Speech-to-text result for voice notes (ptt), when enabled
+	Transcription *WhatsappTranscription `json:"transcription,omitempty"`
+
 	// Debug information for debug events
 	Debug *WhatsappMessageDebug `json:"debug,omitempty"`
 
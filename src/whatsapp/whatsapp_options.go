@@ -14,4 +14,7 @@ type WhatsappOptions struct {
 
 	// should handle calls
 	Calls WhatsappBoolean `db:"calls" json:"calls,omitempty"`
+
+	// should transcribe incoming voice notes (ptt)
+	Transcription WhatsappBoolean `db:"transcription" json:"transcription,omitempty"`
 }
@@ -0,0 +1,9 @@
+package whatsapp
+
+// WhatsappPollVote is a decrypted vote on a poll, each new vote replaces the previous one of the same voter
+type WhatsappPollVote struct {
+	PollId  string   `json:"pollid"`           // poll creation message id
+	Voter   string   `json:"voter"`            // who voted
+	Options []string `json:"options"`          // selected option names, empty when the vote was removed
+	Hashes  []string `json:"hashes,omitempty"` // sha256 (hex) of selected option names, used when names can not be resolved
+}
@@ -0,0 +1,9 @@
+package whatsapp
+
+// WhatsappProfile is the own profile of the connected whatsapp account
+type WhatsappProfile struct {
+	Wid      string                  `json:"wid,omitempty"`
+	PushName string                  `json:"pushname,omitempty"` // display name shown to contacts
+	About    string                  `json:"about,omitempty"`    // about/status text
+	Picture  *WhatsappProfilePicture `json:"picture,omitempty"`  // nil if not set
+}
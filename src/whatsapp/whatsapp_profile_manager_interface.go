@@ -0,0 +1,20 @@
+package whatsapp
+
+// WhatsappProfileManagerInterface defines the interface for own profile operations
+// This interface should be implemented by the profile manager in the whatsmeow package
+type WhatsappProfileManagerInterface interface {
+	// Get own push name, about text and profile picture info
+	GetProfile() (*WhatsappProfile, error)
+
+	// Update own push name (display name)
+	SetPushName(name string) error
+
+	// Update own about/status text
+	SetAbout(text string) error
+
+	// Update own profile picture, expects a square jpeg, returns the new picture id
+	SetProfilePicture(jpeg []byte) (string, error)
+
+	// Remove own profile picture
+	RemoveProfilePicture() error
+}
@@ -0,0 +1,7 @@
+package whatsapp
+
+// WhatsappQuotedMessage is a snapshot of a replied message, used when the original is not cached anymore
+type WhatsappQuotedMessage struct {
+	Text        string `json:"text,omitempty"`
+	Participant string `json:"participant,omitempty"` // quoted message sender, defaults to chat on direct messages
+}
@@ -0,0 +1,8 @@
+package whatsapp
+
+type WhatsappTranscription struct {
+	Text     string `json:"text"`               // Recognized text
+	Language string `json:"language,omitempty"` // Optional: Language hint or detected language
+	Backend  string `json:"backend,omitempty"`  // Speech-to-text backend used (exec, http)
+	Error    string `json:"error,omitempty"`    // Optional: Reason of failure, text will be empty
+}
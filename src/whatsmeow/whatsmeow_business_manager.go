@@ -0,0 +1,230 @@
+package whatsmeow
+
+import (
+	"fmt"
+	"strconv"
+
+	whatsapp "github.com/nocodeleaks/quepasa/whatsapp"
+	whatsmeow "go.mau.fi/whatsmeow"
+	waBinary "go.mau.fi/whatsmeow/binary"
+	types "go.mau.fi/whatsmeow/types"
+)
+
+// Compile-time interface check
+var _ whatsapp.WhatsappBusinessManagerInterface = (*WhatsmeowBusinessManager)(nil)
+
+// WhatsmeowBusinessManager handles business profile and catalog operations for WhatsmeowConnection
+// Whatsmeow does not parse description, websites or catalogs, so the queries are sent directly
+type WhatsmeowBusinessManager struct {
+	*WhatsmeowConnection // embedded connection for direct access
+}
+
+// NewWhatsmeowBusinessManager creates a new WhatsmeowBusinessManager instance
+func NewWhatsmeowBusinessManager(conn *WhatsmeowConnection) *WhatsmeowBusinessManager {
+	return &WhatsmeowBusinessManager{
+		WhatsmeowConnection: conn,
+	}
+}
+
+// sendIQ sends a get query to whatsapp server
+func (bm *WhatsmeowBusinessManager) sendIQ(query whatsmeow.DangerousInfoQuery) (*waBinary.Node, error) {
+	query.Type = whatsmeow.DangerousInfoQueryType("get")
+	query.To = types.ServerJID
+	return bm.Client.DangerousInternals().SendIQ(query)
+}
+
+// GetBusinessProfile returns the business profile of a contact, or own profile if wid is empty
+func (bm *WhatsmeowBusinessManager) GetBusinessProfile(wid string) (*whatsapp.WhatsappBusinessProfile, error) {
+	jid, err := bm.getOwnJID()
+	if err != nil {
+		return nil, err
+	}
+
+	if len(wid) > 0 {
+		jid, err = types.ParseJID(wid)
+		if err != nil {
+			return nil, fmt.Errorf("invalid wid: %v", err)
+		}
+	}
+
+	response, err := bm.sendIQ(whatsmeow.DangerousInfoQuery{
+		Namespace: "w:biz",
+		Content: []waBinary.Node{{
+			Tag:   "business_profile",
+			Attrs: waBinary.Attrs{"v": "244"},
+			Content: []waBinary.Node{{
+				Tag:   "profile",
+				Attrs: waBinary.Attrs{"jid": jid},
+			}},
+		}},
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to get business profile: %v", err)
+	}
+
+	node, ok := response.GetOptionalChildByTag("business_profile", "profile")
+	if !ok {
+		return nil, fmt.Errorf("business profile not found for: %s", jid.String())
+	}
+
+	profile := &whatsapp.WhatsappBusinessProfile{
+		Wid:         jid.String(),
+		Description: getChildString(&node, "description"),
+		Address:     getChildString(&node, "address"),
+		Email:       getChildString(&node, "email"),
+	}
+
+	for _, website := range node.GetChildrenByTag("website") {
+		if url, ok := website.Content.([]byte); ok && len(url) > 0 {
+			profile.Websites = append(profile.Websites, string(url))
+		}
+	}
+
+	categories := node.GetChildByTag("categories")
+	for _, category := range categories.GetChildrenByTag("category") {
+		name, _ := category.Content.([]byte)
+		profile.Categories = append(profile.Categories, whatsapp.WhatsappBusinessCategory{
+			Id:   category.AttrGetter().OptionalString("id"),
+			Name: string(name),
+		})
+	}
+
+	if hours, ok := node.GetOptionalChildByTag("business_hours"); ok {
+		profile.Hours = &whatsapp.WhatsappBusinessHours{
+			TimeZone: hours.AttrGetter().OptionalString("timezone"),
+		}
+
+		for _, config := range hours.GetChildrenByTag("business_hours_config") {
+			attrs := config.AttrGetter()
+			openTime, _ := strconv.Atoi(attrs.OptionalString("open_time"))
+			closeTime, _ := strconv.Atoi(attrs.OptionalString("close_time"))
+			profile.Hours.Days = append(profile.Hours.Days, whatsapp.WhatsappBusinessHoursDay{
+				DayOfWeek: attrs.OptionalString("day_of_week"),
+				Mode:      attrs.OptionalString("mode"),
+				OpenTime:  openTime,
+				CloseTime: closeTime,
+			})
+		}
+	}
+
+	return profile, nil
+}
+
+// GetCatalog returns one page of own catalog products
+func (bm *WhatsmeowBusinessManager) GetCatalog(limit int, cursor string) (*whatsapp.WhatsappBusinessCatalog, error) {
+	jid, err := bm.getOwnJID()
+	if err != nil {
+		return nil, err
+	}
+
+	content := []waBinary.Node{
+		{Tag: "limit", Content: []byte(strconv.Itoa(limit))},
+		{Tag: "width", Content: []byte("100")},
+		{Tag: "height", Content: []byte("100")},
+	}
+
+	if len(cursor) > 0 {
+		content = append(content, waBinary.Node{Tag: "after", Content: []byte(cursor)})
+	}
+
+	response, err := bm.sendIQ(whatsmeow.DangerousInfoQuery{
+		Namespace: "w:biz:catalog",
+		Content: []waBinary.Node{{
+			Tag:     "product_catalog",
+			Attrs:   waBinary.Attrs{"jid": jid, "allow_shop_source": "true"},
+			Content: content,
+		}},
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to get catalog: %v", err)
+	}
+
+	node := response.GetChildByTag("product_catalog")
+	catalog := &whatsapp.WhatsappBusinessCatalog{
+		Products: parseBusinessProducts(&node),
+		Cursor:   getChildString(&node, "paging", "after"),
+	}
+
+	return catalog, nil
+}
+
+// GetCollections returns own catalog collections, with up to limit products each
+func (bm *WhatsmeowBusinessManager) GetCollections(limit int) ([]whatsapp.WhatsappBusinessCollection, error) {
+	jid, err := bm.getOwnJID()
+	if err != nil {
+		return nil, err
+	}
+
+	response, err := bm.sendIQ(whatsmeow.DangerousInfoQuery{
+		Namespace: "w:biz:catalog",
+		SMaxID:    "35",
+		Content: []waBinary.Node{{
+			Tag:   "collections",
+			Attrs: waBinary.Attrs{"biz_jid": jid},
+			Content: []waBinary.Node{
+				{Tag: "collection_limit", Content: []byte("51")},
+				{Tag: "item_limit", Content: []byte(strconv.Itoa(limit))},
+				{Tag: "width", Content: []byte("100")},
+				{Tag: "height", Content: []byte("100")},
+			},
+		}},
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to get collections: %v", err)
+	}
+
+	node := response.GetChildByTag("collections")
+	collections := []whatsapp.WhatsappBusinessCollection{}
+	for _, child := range node.GetChildrenByTag("collection") {
+		collections = append(collections, whatsapp.WhatsappBusinessCollection{
+			Id:       getChildString(&child, "id"),
+			Name:     getChildString(&child, "name"),
+			Status:   getChildString(&child, "status_info", "status"),
+			Products: parseBusinessProducts(&child),
+		})
+	}
+
+	return collections, nil
+}
+
+// parseBusinessProducts reads all product children of a catalog or collection node
+func parseBusinessProducts(node *waBinary.Node) []whatsapp.WhatsappBusinessProduct {
+	products := []whatsapp.WhatsappBusinessProduct{}
+	for _, child := range node.GetChildrenByTag("product") {
+		price, _ := strconv.ParseInt(getChildString(&child, "price"), 10, 64)
+		product := whatsapp.WhatsappBusinessProduct{
+			Id:          getChildString(&child, "id"),
+			RetailerId:  getChildString(&child, "retailer_id"),
+			Name:        getChildString(&child, "name"),
+			Description: getChildString(&child, "description"),
+			Url:         getChildString(&child, "url"),
+			Currency:    getChildString(&child, "currency"),
+			Price:       price,
+			Hidden:      child.AttrGetter().OptionalString("is_hidden") == "true",
+			Status:      getChildString(&child, "status_info", "status"),
+		}
+
+		media := child.GetChildByTag("media")
+		for _, image := range media.GetChildrenByTag("image") {
+			if url := getChildString(&image, "original_image_url"); len(url) > 0 {
+				product.Images = append(product.Images, url)
+			} else if url := getChildString(&image, "request_image_url"); len(url) > 0 {
+				product.Images = append(product.Images, url)
+			}
+		}
+
+		products = append(products, product)
+	}
+	return products
+}
+
+// getChildString returns the text content of the child found by tags path, empty if missing
+func getChildString(node *waBinary.Node, tags ...string) string {
+	child, ok := node.GetOptionalChildByTag(tags...)
+	if !ok {
+		return ""
+	}
+
+	content, _ := child.Content.([]byte)
+	return string(content)
+}
@@ -0,0 +1,173 @@
+package whatsmeow
+
+import (
+	"fmt"
+	"time"
+
+	whatsapp "github.com/nocodeleaks/quepasa/whatsapp"
+	appstate "go.mau.fi/whatsmeow/appstate"
+	waSyncAction "go.mau.fi/whatsmeow/proto/waSyncAction"
+	types "go.mau.fi/whatsmeow/types"
+	"google.golang.org/protobuf/proto"
+)
+
+// Compile-time interface check
+var _ whatsapp.WhatsappChatManagerInterface = (*WhatsmeowChatManager)(nil)
+
+// WhatsmeowChatManager handles chat management operations for WhatsmeowConnection
+// Changes are sent as app state patches, so they are synchronized with all linked devices
+type WhatsmeowChatManager struct {
+	*WhatsmeowConnection // embedded connection for direct access
+}
+
+// NewWhatsmeowChatManager creates a new WhatsmeowChatManager instance
+func NewWhatsmeowChatManager(conn *WhatsmeowConnection) *WhatsmeowChatManager {
+	return &WhatsmeowChatManager{
+		WhatsmeowConnection: conn,
+	}
+}
+
+// getChatJID validates the client and parses the chat id
+func (cm *WhatsmeowChatManager) getChatJID(chatId string) (types.JID, error) {
+	if cm.Client == nil {
+		return types.EmptyJID, fmt.Errorf("client not defined")
+	}
+
+	jid, err := types.ParseJID(chatId)
+	if err != nil {
+		return types.EmptyJID, fmt.Errorf("invalid chat id format: %v", err)
+	}
+
+	return jid, nil
+}
+
+// getMessageIndex returns the app state index parameters of a message: chat, id, from me and sender
+// Sender is "0" unless the message was sent by someone else on a group
+func (cm *WhatsmeowChatManager) getMessageIndex(msg *whatsapp.WhatsappMessage) ([]string, error) {
+	if msg == nil {
+		return nil, fmt.Errorf("message not defined")
+	}
+
+	jid, err := cm.getChatJID(msg.Chat.Id)
+	if err != nil {
+		return nil, err
+	}
+
+	fromMe, sender := "0", "0"
+	if msg.FromMe {
+		fromMe = "1"
+	} else if msg.FromGroup() {
+		participant, err := types.ParseJID(msg.GetParticipantId())
+		if err != nil {
+			return nil, fmt.Errorf("invalid participant id format: %v", err)
+		}
+		sender = participant.String()
+	}
+
+	return []string{jid.String(), msg.Id, fromMe, sender}, nil
+}
+
+// PinChat pins or unpins a chat
+func (cm *WhatsmeowChatManager) PinChat(chatId string, pin bool) error {
+	jid, err := cm.getChatJID(chatId)
+	if err != nil {
+		return err
+	}
+
+	return sendAppState(cm.WhatsmeowConnection, appstate.BuildPin(jid, pin))
+}
+
+// MuteChat mutes or unmutes a chat, zero duration mutes forever
+func (cm *WhatsmeowChatManager) MuteChat(chatId string, mute bool, duration time.Duration) error {
+	jid, err := cm.getChatJID(chatId)
+	if err != nil {
+		return err
+	}
+
+	return sendAppState(cm.WhatsmeowConnection, appstate.BuildMute(jid, mute, duration))
+}
+
+// StarMessage stars or unstars a message
+func (cm *WhatsmeowChatManager) StarMessage(msg *whatsapp.WhatsappMessage, starred bool) error {
+	index, err := cm.getMessageIndex(msg)
+	if err != nil {
+		return err
+	}
+
+	patch := appstate.PatchInfo{
+		Type: appstate.WAPatchRegularHigh,
+		Mutations: []appstate.MutationInfo{{
+			Index:   append([]string{appstate.IndexStar}, index...),
+			Version: 2,
+			Value: &waSyncAction.SyncActionValue{
+				StarAction: &waSyncAction.StarAction{Starred: proto.Bool(starred)},
+			},
+		}},
+	}
+
+	return sendAppState(cm.WhatsmeowConnection, patch)
+}
+
+// DeleteMessageForMe deletes a message only for the connected account
+func (cm *WhatsmeowChatManager) DeleteMessageForMe(msg *whatsapp.WhatsappMessage, deleteMedia bool) error {
+	index, err := cm.getMessageIndex(msg)
+	if err != nil {
+		return err
+	}
+
+	patch := appstate.PatchInfo{
+		Type: appstate.WAPatchRegularHigh,
+		Mutations: []appstate.MutationInfo{{
+			Index:   append([]string{appstate.IndexDeleteMessageForMe}, index...),
+			Version: 3,
+			Value: &waSyncAction.SyncActionValue{
+				DeleteMessageForMeAction: &waSyncAction.DeleteMessageForMeAction{
+					DeleteMedia:      proto.Bool(deleteMedia),
+					MessageTimestamp: proto.Int64(msg.Timestamp.Unix()),
+				},
+			},
+		}},
+	}
+
+	return sendAppState(cm.WhatsmeowConnection, patch)
+}
+
+// ClearChat clears all messages of a chat
+func (cm *WhatsmeowChatManager) ClearChat(chatId string) error {
+	jid, err := cm.getChatJID(chatId)
+	if err != nil {
+		return err
+	}
+
+	patch := appstate.PatchInfo{
+		Type: appstate.WAPatchRegularHigh,
+		Mutations: []appstate.MutationInfo{{
+			Index:   []string{appstate.IndexClearChat, jid.String(), "1", "0"}, // same flags sent by whatsapp web
+			Version: 6,
+			Value: &waSyncAction.SyncActionValue{
+				ClearChatAction: &waSyncAction.ClearChatAction{
+					MessageRange: &waSyncAction.SyncActionMessageRange{
+						LastMessageTimestamp: proto.Int64(time.Now().Unix()),
+					},
+				},
+			},
+		}},
+	}
+
+	return sendAppState(cm.WhatsmeowConnection, patch)
+}
+
+// SetDisappearingTimer sets the disappearing messages timer of a chat, zero disables
+func (cm *WhatsmeowChatManager) SetDisappearingTimer(chatId string, timer time.Duration) error {
+	jid, err := cm.getChatJID(chatId)
+	if err != nil {
+		return err
+	}
+
+	err = cm.Client.SetDisappearingTimer(jid, timer, time.Now())
+	if err != nil {
+		return fmt.Errorf("failed to set disappearing timer: %v", err)
+	}
+
+	return nil
+}
@@ -5,6 +5,7 @@ import (
 	"errors"
 	"fmt"
 	"reflect"
+	"slices"
 	"strings"
 	"sync"
 	"time"
@@ -14,6 +15,7 @@ import (
 	"google.golang.org/protobuf/proto"
 
 	library "github.com/nocodeleaks/quepasa/library"
+	telemetry "github.com/nocodeleaks/quepasa/telemetry"
 	whatsapp "github.com/nocodeleaks/quepasa/whatsapp"
 	whatsmeow "go.mau.fi/whatsmeow"
 	"go.mau.fi/whatsmeow/appstate"
@@ -26,10 +28,13 @@ type WhatsmeowConnection struct {
 	library.LogStruct // logging
 	Client            *whatsmeow.Client
 
-	Handlers       *WhatsmeowHandlers       // composition for handlers
-	GroupManager   *WhatsmeowGroupManager   // composition for group operations
-	StatusManager  *WhatsmeowStatusManager  // composition for status operations
-	ContactManager *WhatsmeowContactManager // composition for contact operations
+	Handlers        *WhatsmeowHandlers        // composition for handlers
+	GroupManager    *WhatsmeowGroupManager    // composition for group operations
+	StatusManager   *WhatsmeowStatusManager   // composition for status operations
+	ContactManager  *WhatsmeowContactManager  // composition for contact operations
+	ProfileManager  *WhatsmeowProfileManager  // composition for own profile operations
+	BusinessManager *WhatsmeowBusinessManager // composition for business profile and catalog operations
+	ChatManager     *WhatsmeowChatManager     // composition for chat management operations
 	// call managers intentionally omitted per request (do not include CallManager / SIPCallManager)
 
 	failedToken  bool
@@ -210,6 +215,57 @@ func (source *WhatsmeowConnection) Edit(msg whatsapp.IWhatsappMessage, newConten
 	return nil
 }
 
+// Forward sends a copy of a cached message to another chat, marked as forwarded and reusing original media
+func (source *WhatsmeowConnection) Forward(msg *whatsapp.WhatsappMessage, chatId string) (*whatsapp.WhatsappMessage, error) {
+	logentry := source.GetLogger().WithField(LogFields.MessageId, msg.Id)
+
+	original, _ := msg.Content.(*waE2E.Message)
+	newMessage, score, err := GenerateForwardMessage(original)
+	if err != nil {
+		return nil, err
+	}
+
+	formattedDestination, err := whatsapp.FormatEndpoint(chatId)
+	if err != nil {
+		return nil, err
+	}
+
+	jid, err := types.ParseJID(formattedDestination)
+	if err != nil {
+		return nil, err
+	}
+
+	forwarded := &whatsapp.WhatsappMessage{
+		Content:         newMessage,
+		Id:              source.Client.GenerateMessageID(),
+		Type:            msg.Type,
+		Chat:            whatsapp.WhatsappChat{Id: jid.String()},
+		Text:            msg.Text,
+		FromMe:          true,
+		FromInternal:    true,
+		ForwardingScore: score,
+		Url:             msg.Url,
+		Poll:            msg.Poll,
+		Location:        msg.Location,
+		Contact:         msg.Contact,
+	}
+
+	if msg.Attachment != nil {
+		attachment := *msg.Attachment
+		forwarded.Attachment = &attachment
+	}
+
+	resp, err := source.Client.SendMessage(context.Background(), jid, newMessage, whatsmeow.SendRequestExtra{ID: forwarded.Id})
+	if err != nil {
+		logentry.Errorf("whatsmeow connection forward error: %s", err)
+		return nil, err
+	}
+
+	forwarded.Timestamp = resp.Timestamp
+	logentry.Infof("forward success, to: %s, new id: %s", forwarded.Chat.Id, forwarded.Id)
+	return forwarded, nil
+}
+
 // MarkRead sends a read receipt for the given message via Whatsmeow handlers
 func (source *WhatsmeowConnection) MarkRead(imsg whatsapp.IWhatsappMessage) error {
 	if imsg == nil {
@@ -252,17 +308,14 @@ func (source *WhatsmeowConnection) GetContextInfo(msg whatsapp.WhatsappMessage)
 	}
 
 	// mentions ---------------------------------------
-	if msg.FromGroup() {
-		messageText := msg.GetText()
-		mentions := GetMentions(messageText)
-		if len(mentions) > 0 {
+	mentions := source.GetMentionedJIDs(msg)
+	if len(mentions) > 0 {
 
-			if contextInfo == nil {
-				contextInfo = &waE2E.ContextInfo{}
-			}
-
-			contextInfo.MentionedJID = mentions
+		if contextInfo == nil {
+			contextInfo = &waE2E.ContextInfo{}
 		}
+
+		contextInfo.MentionedJID = mentions
 	}
 
 	// disapering messages, not implemented yet
@@ -275,6 +328,51 @@ func (source *WhatsmeowConnection) GetContextInfo(msg whatsapp.WhatsappMessage)
 	return contextInfo
 }
 
+// GetMentionedJIDs joins explicit mentions, @number tokens of group messages and all group participants when requested
+func (source *WhatsmeowConnection) GetMentionedJIDs(msg whatsapp.WhatsappMessage) (mentions []string) {
+	candidates := slices.Clone(msg.Mentions)
+
+	if msg.FromGroup() {
+		candidates = append(candidates, GetMentions(msg.GetText())...)
+
+		if msg.MentionAll {
+			participants, err := source.getGroupParticipants(msg.Chat.Id)
+			if err != nil {
+				logentry := source.GetLogger()
+				logentry.Warnf("failed to get participants to mention all, on group: %s, error: %s", msg.Chat.Id, err.Error())
+			}
+			candidates = append(candidates, participants...)
+		}
+	}
+
+	own, _ := source.getOwnJID()
+	for _, candidate := range candidates {
+		if len(candidate) > 0 && candidate != own.String() && !slices.Contains(mentions, candidate) {
+			mentions = append(mentions, candidate)
+		}
+	}
+
+	return
+}
+
+// getGroupParticipants returns the participants jids of a group
+func (source *WhatsmeowConnection) getGroupParticipants(groupId string) (participants []string, err error) {
+	jid, err := types.ParseJID(groupId)
+	if err != nil {
+		return
+	}
+
+	info, err := source.Client.GetGroupInfo(jid)
+	if err != nil {
+		return
+	}
+
+	for _, participant := range info.Participants {
+		participants = append(participants, participant.JID.ToNonAD().String())
+	}
+	return
+}
+
 func (source *WhatsmeowConnection) GetInReplyContextInfo(msg whatsapp.WhatsappMessage) *waE2E.ContextInfo {
 	logentry := source.GetLogger()
 
@@ -303,7 +401,7 @@ func (source *WhatsmeowConnection) GetInReplyContextInfo(msg whatsapp.WhatsappMe
 		} else {
 			logentry.Warnf("message content not cached, on reply to msg id: %s", msg.InReply)
 		}
-	} else {
+	} else if msg.Quoted == nil {
 		logentry.Warnf("message not cached, on reply to msg id: %s", msg.InReply)
 	}
 
@@ -316,6 +414,18 @@ func (source *WhatsmeowConnection) GetInReplyContextInfo(msg whatsapp.WhatsappMe
 			sender = fmt.Sprint(info.Chat.User, "@", info.Chat.Server)
 		}
 		participant = proto.String(sender)
+	} else if msg.Quoted != nil {
+
+		// using snapshot passed on request, message not cached anymore
+		if quoted == nil && len(msg.Quoted.Text) > 0 {
+			quoted = &waE2E.Message{Conversation: proto.String(msg.Quoted.Text)}
+		}
+
+		if len(msg.Quoted.Participant) > 0 {
+			participant = proto.String(msg.Quoted.Participant)
+		} else if !msg.FromGroup() {
+			participant = proto.String(msg.Chat.Id)
+		}
 	}
 
 	return &waE2E.ContextInfo{
@@ -327,6 +437,25 @@ func (source *WhatsmeowConnection) GetInReplyContextInfo(msg whatsapp.WhatsappMe
 
 // Default SEND method using WhatsappMessage Interface
 func (source *WhatsmeowConnection) Send(msg *whatsapp.WhatsappMessage) (whatsapp.IWhatsappSendResponse, error) {
+	var wid string
+	if jid, err := source.getOwnJID(); err == nil {
+		wid = jid.User
+	}
+
+	ctx, span := telemetry.StartMessageSpan("whatsmeow.send", msg, telemetry.SpanKindProducer, telemetry.Wid(wid))
+	msg.TraceContext = ctx
+
+	response, err := source.send(msg)
+	if err == nil && response != nil {
+		span.SetAttributes(telemetry.AttributeMessageId.String(response.GetId()))
+	}
+
+	telemetry.EndSpan(span, err)
+	return response, err
+}
+
+// send without tracing, called from Send
+func (source *WhatsmeowConnection) send(msg *whatsapp.WhatsappMessage) (whatsapp.IWhatsappSendResponse, error) {
 	logentry := source.GetLogger()
 	loglevel := logentry.Level
 	logentry = logentry.WithField(LogFields.MessageId, msg.Id)
@@ -385,14 +514,41 @@ func (source *WhatsmeowConnection) Send(msg *whatsapp.WhatsappMessage) (whatsapp
 				DegreesLongitude: proto.Float64(attach.Longitude),
 			},
 		}
-		// Add optional fields if available
-		if len(messageText) > 0 {
-			newMessage.LocationMessage.Name = proto.String(messageText)
+		// Add optional fields if available, venue name and address, text is the name fallback
+		name := messageText
+		if msg.Location != nil {
+			if len(msg.Location.Name) > 0 {
+				name = msg.Location.Name
+			}
+			if len(msg.Location.Address) > 0 {
+				newMessage.LocationMessage.Address = proto.String(msg.Location.Address)
+			}
+			if len(msg.Location.Url) > 0 {
+				newMessage.LocationMessage.URL = proto.String(msg.Location.Url)
+			}
+		}
+		if len(name) > 0 {
+			newMessage.LocationMessage.Name = proto.String(name)
 		}
 		// Add context info for replies if needed
 		if len(msg.InReply) > 0 {
 			newMessage.LocationMessage.ContextInfo = source.GetContextInfo(*msg)
 		}
+	} else if msg.Interactive != nil {
+		// Structured buttons or lists, attachment is used as header media
+		var media *waE2E.Message
+		if msg.HasAttachment() {
+			media, err = source.UploadAttachment(*msg)
+			if err != nil {
+				return msg, err
+			}
+		}
+
+		newMessage, err = GenerateInteractiveMessage(msg, media)
+		if err != nil {
+			return msg, err
+		}
+		newMessage.InteractiveMessage.ContextInfo = source.GetContextInfo(*msg)
 	} else if !msg.HasAttachment() {
 		// Text messages, buttons, polls
 		if IsValidForButtons(messageText) {
@@ -430,6 +586,10 @@ func (source *WhatsmeowConnection) Send(msg *whatsapp.WhatsappMessage) (whatsapp
 		ID: msg.Id,
 	}
 
+	if newMessage.InteractiveMessage != nil {
+		extra.AdditionalNodes = GetInteractiveAdditionalNodes()
+	}
+
 	// saving cached content for instance of future reply
 	if msg.Content == nil {
 		msg.Content = newMessage
@@ -475,6 +635,48 @@ func (source *WhatsmeowConnection) HasChat(chat string) bool {
 	return info.Found
 }
 
+// getOwnJID returns the logged account jid, without device
+func (source *WhatsmeowConnection) getOwnJID() (types.JID, error) {
+	if source == nil || source.Client == nil {
+		return types.EmptyJID, fmt.Errorf("client not defined")
+	}
+
+	if source.Client.Store == nil || source.Client.Store.ID == nil {
+		return types.EmptyJID, fmt.Errorf("device not logged in")
+	}
+
+	return source.Client.Store.ID.ToNonAD(), nil
+}
+
+// local chat settings (archived, pinned, muted), synchronized from app state
+func (source *WhatsmeowConnection) GetChatSettings(chat string) (*whatsapp.WhatsappChatSettings, error) {
+	if source == nil || source.Client == nil || source.Client.Store == nil || source.Client.Store.ChatSettings == nil {
+		return nil, fmt.Errorf("client not defined")
+	}
+
+	jid, err := types.ParseJID(chat)
+	if err != nil {
+		return nil, err
+	}
+
+	info, err := source.Client.Store.ChatSettings.GetChatSettings(context.TODO(), jid)
+	if err != nil {
+		return nil, err
+	}
+
+	settings := &whatsapp.WhatsappChatSettings{
+		Found:    info.Found,
+		Archived: info.Archived,
+		Pinned:   info.Pinned,
+	}
+
+	if !info.MutedUntil.IsZero() {
+		settings.MutedUntil = &info.MutedUntil
+	}
+
+	return settings, nil
+}
+
 // func (cli *Client) Upload(ctx context.Context, plaintext []byte, appInfo MediaType) (resp UploadResponse, err error)
 func (source *WhatsmeowConnection) UploadAttachment(msg whatsapp.WhatsappMessage) (result *waE2E.Message, err error) {
 
@@ -490,8 +692,8 @@ func (source *WhatsmeowConnection) UploadAttachment(msg whatsapp.WhatsappMessage
 		return
 	}
 
-	inreplycontext := source.GetInReplyContextInfo(msg)
-	result = NewWhatsmeowMessageAttachment(response, msg, mediaType, inreplycontext)
+	contextInfo := source.GetContextInfo(msg)
+	result = NewWhatsmeowMessageAttachment(response, msg, mediaType, contextInfo)
 	return
 }
 
@@ -849,6 +1051,30 @@ func (conn *WhatsmeowConnection) GetContactManager() whatsapp.WhatsappContactMan
 	return conn.ContactManager
 }
 
+// GetProfileManager returns the profile manager instance with lazy initialization
+func (conn *WhatsmeowConnection) GetProfileManager() whatsapp.WhatsappProfileManagerInterface {
+	if conn.ProfileManager == nil {
+		conn.ProfileManager = NewWhatsmeowProfileManager(conn)
+	}
+	return conn.ProfileManager
+}
+
+// GetBusinessManager returns the business manager instance with lazy initialization
+func (conn *WhatsmeowConnection) GetBusinessManager() whatsapp.WhatsappBusinessManagerInterface {
+	if conn.BusinessManager == nil {
+		conn.BusinessManager = NewWhatsmeowBusinessManager(conn)
+	}
+	return conn.BusinessManager
+}
+
+// GetChatManager returns the chat manager instance with lazy initialization
+func (conn *WhatsmeowConnection) GetChatManager() whatsapp.WhatsappChatManagerInterface {
+	if conn.ChatManager == nil {
+		conn.ChatManager = NewWhatsmeowChatManager(conn)
+	}
+	return conn.ChatManager
+}
+
 // GetResume returns detailed connection status information
 // This method delegates to the StatusManager for comprehensive status snapshot
 func (conn *WhatsmeowConnection) GetResume() *whatsapp.WhatsappConnectionStatus {
@@ -875,6 +1101,18 @@ func (conn *WhatsmeowConnection) initializeHandlers(whatsappOptions *whatsapp.Wh
 	return conn.Handlers.Register()
 }
 
+// UpdatePresence changes the presence sent on connect, sending it now if connected
+func (conn *WhatsmeowConnection) UpdatePresence(presence string) {
+	if conn.Handlers == nil {
+		return
+	}
+
+	conn.Handlers.Presence = presence
+	if conn.Client != nil && conn.Client.IsConnected() {
+		conn.Handlers.SendPresence(conn.Handlers.GetPresence(), "presence update")
+	}
+}
+
 // SendChatPresence updates typing status in a chat
 func (conn *WhatsmeowConnection) SendChatPresence(chatId string, presenceType uint) error {
 	if conn.Client == nil {
@@ -0,0 +1,87 @@
+package whatsmeow
+
+import (
+	"fmt"
+	"reflect"
+
+	"go.mau.fi/util/random"
+	"go.mau.fi/whatsmeow/proto/waE2E"
+	"google.golang.org/protobuf/proto"
+)
+
+/**
+ * GenerateForwardMessage copies an original message as a forwarded one.
+ *
+ * Media keys and direct paths are kept, so attachments are not downloaded or uploaded again.
+ * The context info of the inner message (the one with content) is replaced, removing replies and mentions,
+ * marking as forwarded and incrementing the forwarding score.
+ *
+ * @param original Message content as received or sent
+ * @return New message ready to send and its forwarding score
+ */
+func GenerateForwardMessage(original *waE2E.Message) (*waE2E.Message, uint32, error) {
+
+	// unwrapping containers, whatsapp forwards only the inner content
+	for original != nil {
+		if inner := original.GetEphemeralMessage().GetMessage(); inner != nil {
+			original = inner
+		} else if inner := original.GetDeviceSentMessage().GetMessage(); inner != nil {
+			original = inner
+		} else if inner := original.GetDocumentWithCaptionMessage().GetMessage(); inner != nil {
+			original = inner
+		} else {
+			break
+		}
+	}
+
+	if original == nil {
+		return nil, 0, fmt.Errorf("message content not available for forwarding")
+	}
+
+	if original.ViewOnceMessage != nil || original.ViewOnceMessageV2 != nil || original.ViewOnceMessageV2Extension != nil ||
+		original.GetImageMessage().GetViewOnce() || original.GetVideoMessage().GetViewOnce() || original.GetAudioMessage().GetViewOnce() {
+		return nil, 0, fmt.Errorf("view once messages can not be forwarded")
+	}
+
+	message := proto.Clone(original).(*waE2E.Message)
+	message.MessageContextInfo = nil
+	message.SenderKeyDistributionMessage = nil
+
+	// plain text has no context info, upgrading to extended text
+	if message.Conversation != nil {
+		message.ExtendedTextMessage = &waE2E.ExtendedTextMessage{Text: message.Conversation}
+		message.Conversation = nil
+	}
+
+	contextInfoType := reflect.TypeOf(&waE2E.ContextInfo{})
+	content := reflect.ValueOf(message).Elem()
+	for i := 0; i < content.NumField(); i++ {
+		field := content.Field(i)
+		if field.Kind() != reflect.Ptr || field.IsNil() || field.Elem().Kind() != reflect.Struct {
+			continue
+		}
+
+		contextField := field.Elem().FieldByName("ContextInfo")
+		if !contextField.IsValid() || !contextField.CanSet() || contextField.Type() != contextInfoType {
+			continue
+		}
+
+		previous, _ := contextField.Interface().(*waE2E.ContextInfo)
+		score := previous.GetForwardingScore() + 1
+		contextField.Set(reflect.ValueOf(&waE2E.ContextInfo{
+			IsForwarded:     proto.Bool(true),
+			ForwardingScore: proto.Uint32(score),
+		}))
+
+		// polls need a new secret, votes are encrypted with it
+		if message.PollCreationMessage != nil || message.PollCreationMessageV2 != nil || message.PollCreationMessageV3 != nil {
+			message.MessageContextInfo = &waE2E.MessageContextInfo{
+				MessageSecret: random.Bytes(32),
+			}
+		}
+
+		return message, score, nil
+	}
+
+	return nil, 0, fmt.Errorf("message type can not be forwarded: %s", GetMessageEventType(original))
+}
@@ -2,10 +2,13 @@ package whatsmeow
 
 import (
 	"encoding/json"
+	"fmt"
 	"regexp"
 	"strings"
 
 	_ "github.com/mattn/go-sqlite3"
+	whatsapp "github.com/nocodeleaks/quepasa/whatsapp"
+	waBinary "go.mau.fi/whatsmeow/binary"
 	"go.mau.fi/whatsmeow/proto/waE2E"
 	"google.golang.org/protobuf/proto"
 )
@@ -80,183 +83,146 @@ func IsValidForButtons(text string) bool {
 	return false
 }
 
-func GenerateButtonsMessageNew(messageText string) *waE2E.ButtonsMessage {
-	msg := &waE2E.ButtonsMessage{
-		ContentText: proto.String("لدي إستفسار بخصوص:"),
-		HeaderType:  waE2E.ButtonsMessage_EMPTY.Enum(),
-		Buttons: []*waE2E.ButtonsMessage_Button{
-			{
-				ButtonID:       proto.String("bt1"),
-				ButtonText:     &waE2E.ButtonsMessage_Button_ButtonText{DisplayText: proto.String("نعم")},
-				Type:           waE2E.ButtonsMessage_Button_RESPONSE.Enum(),
-				NativeFlowInfo: &waE2E.ButtonsMessage_Button_NativeFlowInfo{},
-			},
-			{
-				ButtonID:       proto.String("bt2"),
-				ButtonText:     &waE2E.ButtonsMessage_Button_ButtonText{DisplayText: proto.String("لا")},
-				Type:           waE2E.ButtonsMessage_Button_RESPONSE.Enum(), //proto.ButtonsMessage_Button_Type.Enum,
-				NativeFlowInfo: &waE2E.ButtonsMessage_Button_NativeFlowInfo{},
-			},
-		},
+// native flow button names, as used by whatsapp clients
+const (
+	NativeFlowQuickReply   = "quick_reply"
+	NativeFlowCtaUrl       = "cta_url"
+	NativeFlowCtaCall      = "cta_call"
+	NativeFlowCtaCopy      = "cta_copy"
+	NativeFlowSingleSelect = "single_select"
+)
+
+/**
+ * GenerateInteractiveMessage builds a native flow message from structured interactive data.
+ *
+ * Reply, url, call and copy buttons or a single select list are converted to native flow buttons,
+ * message text is used as body.
+ *
+ * @param msg WhatsappMessage containing interactive data and text
+ * @param media Optional uploaded attachment (image, video or document) used as header
+ * @return waE2E.Message pointer with the interactive message
+ */
+func GenerateInteractiveMessage(msg *whatsapp.WhatsappMessage, media *waE2E.Message) (*waE2E.Message, error) {
+	interactive := msg.Interactive
+	if interactive == nil {
+		return nil, fmt.Errorf("interactive data is nil")
 	}
-	return msg
-}
 
-func GenerateListResponseMessage(messageText string) *waE2E.ListResponseMessage {
+	if err := interactive.Validate(); err != nil {
+		return nil, err
+	}
 
-	ListResponseMessage := &waE2E.ListResponseMessage{
-		Title:       proto.String("title"),
-		Description: proto.String("Description"),
-		SingleSelectReply: &waE2E.ListResponseMessage_SingleSelectReply{
-			SelectedRowID: proto.String("SelectedRowId1"),
-		},
-		//	ContextInfo: ,
-		ListType: waE2E.ListResponseMessage_SINGLE_SELECT.Enum(),
+	var buttons []*waE2E.InteractiveMessage_NativeFlowMessage_NativeFlowButton
+	for _, button := range interactive.Buttons {
+		var name string
+		params := map[string]string{"display_text": button.Text}
+
+		switch button.GetType() {
+		case whatsapp.InteractiveButtonUrl:
+			name = NativeFlowCtaUrl
+			params["url"] = button.Url
+			params["merchant_url"] = button.Url
+		case whatsapp.InteractiveButtonCall:
+			name = NativeFlowCtaCall
+			params["phone_number"] = button.Phone
+		case whatsapp.InteractiveButtonCopy:
+			name = NativeFlowCtaCopy
+			params["copy_code"] = button.Code
+		default:
+			name = NativeFlowQuickReply
+			params["id"] = button.Id
+		}
+
+		paramsJson, err := json.Marshal(params)
+		if err != nil {
+			return nil, err
+		}
+
+		buttons = append(buttons, &waE2E.InteractiveMessage_NativeFlowMessage_NativeFlowButton{
+			Name:             proto.String(name),
+			ButtonParamsJSON: proto.String(string(paramsJson)),
+		})
 	}
 
-	return ListResponseMessage
-}
+	if interactive.List != nil {
+		paramsJson, err := json.Marshal(map[string]any{
+			"title":    interactive.List.ButtonText,
+			"sections": interactive.List.Sections,
+		})
+		if err != nil {
+			return nil, err
+		}
 
-func GenerateListMessage(messageText string) *waE2E.ListMessage {
-
-	ListMessage := &waE2E.ListMessage{
-		Title:       proto.String("ListMessage title"),
-		Description: proto.String("ListMessage Description"),
-		FooterText:  proto.String("ListMessage footer"),
-		ButtonText:  proto.String("ListMessage ButtonText"),
-		ListType:    waE2E.ListMessage_SINGLE_SELECT.Enum(),
-		Sections: []*waE2E.ListMessage_Section{
-			{
-				Title: proto.String("Section1 title"),
-				Rows: []*waE2E.ListMessage_Row{
-					{
-						RowID:       proto.String("id1"),
-						Title:       proto.String("ListMessage section row title"),
-						Description: proto.String("ListMessage section row desc"),
-					},
-					{
-						RowID:       proto.String("id2"),
-						Title:       proto.String("title 2"),
-						Description: proto.String("desc 2"),
-					},
-				},
-			},
-			{
-				Title: proto.String("Section2 title"),
-				Rows: []*waE2E.ListMessage_Row{
-					{
-						RowID:       proto.String("id1"),
-						Title:       proto.String("ListMessage section row title"),
-						Description: proto.String("ListMessage section row desc"),
-					},
-					{
-						RowID:       proto.String("id2"),
-						Title:       proto.String("title 2"),
-						Description: proto.String("desc 2"),
-					},
-				},
-			},
-		},
+		buttons = append(buttons, &waE2E.InteractiveMessage_NativeFlowMessage_NativeFlowButton{
+			Name:             proto.String(NativeFlowSingleSelect),
+			ButtonParamsJSON: proto.String(string(paramsJson)),
+		})
 	}
 
-	return ListMessage
-}
+	header := &waE2E.InteractiveMessage_Header{
+		HasMediaAttachment: proto.Bool(media != nil),
+	}
 
-func GenerateTemplateMessage(messageText string) *waE2E.TemplateMessage {
-	TemplateMessage := &waE2E.TemplateMessage{
-		HydratedTemplate: &waE2E.TemplateMessage_HydratedFourRowTemplate{
-			Title: &waE2E.TemplateMessage_HydratedFourRowTemplate_HydratedTitleText{
-				HydratedTitleText: "The Title",
-			},
-			TemplateID:          proto.String("template-id"),
-			HydratedContentText: proto.String("The Content"),
-			HydratedFooterText:  proto.String("The Footer"),
-
-			HydratedButtons: []*waE2E.HydratedTemplateButton{
-
-				// This for URL button
-				{
-					Index: proto.Uint32(1),
-					HydratedButton: &waE2E.HydratedTemplateButton_UrlButton{
-						UrlButton: &waE2E.HydratedTemplateButton_HydratedURLButton{
-							DisplayText: proto.String("The Link"),
-							URL:         proto.String("https://fb.me/this"),
-						},
-					},
-				},
-
-				// This for call button
-				{
-					Index: proto.Uint32(2),
-					HydratedButton: &waE2E.HydratedTemplateButton_CallButton{
-						CallButton: &waE2E.HydratedTemplateButton_HydratedCallButton{
-							DisplayText: proto.String("Call us"),
-							PhoneNumber: proto.String("1234567890"),
-						},
-					},
-				},
-
-				// This is just a quick reply
-				{
-					Index: proto.Uint32(3),
-					HydratedButton: &waE2E.HydratedTemplateButton_QuickReplyButton{
-						QuickReplyButton: &waE2E.HydratedTemplateButton_HydratedQuickReplyButton{
-							DisplayText: proto.String("Quick reply"),
-							ID:          proto.String("quick-id"),
-						},
-					},
-				},
-			},
-		},
+	if len(interactive.Header) > 0 {
+		header.Title = proto.String(interactive.Header)
 	}
-	return TemplateMessage
-}
 
-type ButtonParams struct {
-	DisplayText string `json:"displayText"`
-	ID          string `json:"buttonID"`
-}
+	if len(interactive.Subtitle) > 0 {
+		header.Subtitle = proto.String(interactive.Subtitle)
+	}
 
-func GenerateInteractiveMessage(messageText string) *waE2E.InteractiveMessage {
-	jsonDataFirst, _ := json.Marshal(ButtonParams{
-		DisplayText: "button - 01",
-		ID:          ".bt1",
-	})
-
-	jsonDataSecond, _ := json.Marshal(ButtonParams{
-		DisplayText: "button - 02",
-		ID:          ".bt2",
-	})
-
-	msgVersion := int32(1)
-	InteractiveMessage := &waE2E.InteractiveMessage{
-		Header: &waE2E.InteractiveMessage_Header{
-			Title:              proto.String("title"),
-			Subtitle:           proto.String("subtitle"),
-			HasMediaAttachment: proto.Bool(false),
-		},
-		Footer: &waE2E.InteractiveMessage_Footer{
-			Text: proto.String("footer"),
-		},
-		Body: &waE2E.InteractiveMessage_Body{
-			Text: proto.String("body"),
-		},
+	if media != nil {
+		switch {
+		case media.ImageMessage != nil:
+			media.ImageMessage.Caption = nil
+			media.ImageMessage.ContextInfo = nil
+			header.Media = &waE2E.InteractiveMessage_Header_ImageMessage{ImageMessage: media.ImageMessage}
+		case media.VideoMessage != nil:
+			media.VideoMessage.Caption = nil
+			media.VideoMessage.ContextInfo = nil
+			header.Media = &waE2E.InteractiveMessage_Header_VideoMessage{VideoMessage: media.VideoMessage}
+		case media.DocumentMessage != nil:
+			media.DocumentMessage.Caption = nil
+			media.DocumentMessage.ContextInfo = nil
+			header.Media = &waE2E.InteractiveMessage_Header_DocumentMessage{DocumentMessage: media.DocumentMessage}
+		default:
+			return nil, fmt.Errorf("interactive header accepts only image, video or document")
+		}
+	}
+
+	internal := &waE2E.InteractiveMessage{
+		Header: header,
+		Body:   &waE2E.InteractiveMessage_Body{Text: proto.String(msg.GetText())},
 		InteractiveMessage: &waE2E.InteractiveMessage_NativeFlowMessage_{
 			NativeFlowMessage: &waE2E.InteractiveMessage_NativeFlowMessage{
-				MessageVersion: &msgVersion,
-				Buttons: []*waE2E.InteractiveMessage_NativeFlowMessage_NativeFlowButton{
-					{
-						Name:             proto.String("Yes"),
-						ButtonParamsJSON: proto.String(string(jsonDataFirst)),
-					},
-					{
-						Name:             proto.String("No"),
-						ButtonParamsJSON: proto.String(string(jsonDataSecond)),
-					},
-				},
+				MessageVersion: proto.Int32(1),
+				Buttons:        buttons,
 			},
 		},
-		ContextInfo: &waE2E.ContextInfo{},
 	}
-	return InteractiveMessage
+
+	if len(interactive.Footer) > 0 {
+		internal.Footer = &waE2E.InteractiveMessage_Footer{Text: proto.String(interactive.Footer)}
+	}
+
+	return &waE2E.Message{InteractiveMessage: internal}, nil
+}
+
+/**
+ * GetInteractiveAdditionalNodes returns the biz node required by whatsapp clients to render native flow messages.
+ *
+ * Whatsmeow adds this node for legacy buttons and lists only.
+ */
+func GetInteractiveAdditionalNodes() *[]waBinary.Node {
+	return &[]waBinary.Node{{
+		Tag: "biz",
+		Content: []waBinary.Node{{
+			Tag:   "interactive",
+			Attrs: waBinary.Attrs{"type": "native_flow", "v": "1"},
+			Content: []waBinary.Node{{
+				Tag:   "native_flow",
+				Attrs: waBinary.Attrs{"v": "9", "name": "mixed"},
+			}},
+		}},
+	}}
 }
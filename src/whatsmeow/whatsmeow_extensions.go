@@ -75,7 +75,7 @@ func ToWhatsmeowMessage(source whatsapp.IWhatsappMessage) (msg *waE2E.Message, e
  * @param response UploadResponse containing media upload info
  * @param waMsg WhatsappMessage containing attachment and text
  * @param media MediaType to use (image, audio, video, document)
- * @param inreplycontext Optional context info for replies
+ * @param inreplycontext Optional context info for replies and mentions
  * @return waE2E.Message pointer with the correct media type
  */
 func NewWhatsmeowMessageAttachment(response whatsmeow.UploadResponse, waMsg whatsapp.WhatsappMessage, media whatsmeow.MediaType, inreplycontext *waE2E.ContextInfo) (msg *waE2E.Message) {
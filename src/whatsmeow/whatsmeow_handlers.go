@@ -10,6 +10,7 @@ import (
 	"time"
 
 	library "github.com/nocodeleaks/quepasa/library"
+	telemetry "github.com/nocodeleaks/quepasa/telemetry"
 	whatsapp "github.com/nocodeleaks/quepasa/whatsapp"
 	log "github.com/sirupsen/logrus"
 	"go.mau.fi/whatsmeow/appstate"
@@ -352,15 +353,16 @@ func (source *WhatsmeowHandlers) EventsHandler(rawEvt interface{}) {
 		go source.UndecryptableMessage(*evt)
 		return
 
+	case *events.Pin, *events.Mute, *events.Star, *events.DeleteForMe, *events.ClearChat:
+		go OnEventChatAction(source, evt)
+		return
+
 	case
 		*events.AppState,
 		*events.CallTerminate,
 		*events.DeleteChat,
-		*events.DeleteForMe,
 		*events.MarkChatAsRead,
-		*events.Mute,
 		*events.PairSuccess,
-		*events.Pin,
 		*events.PushName,
 		*events.GroupInfo,
 		*events.QR:
@@ -635,6 +637,16 @@ func (handler *WhatsmeowHandlers) Message(evt events.Message, from string) {
 		}
 	}
 
+	// tracing from receive, internal handlers and dispatching follow on message trace context
+	var wid string
+	if jid, err := handler.getOwnJID(); err == nil {
+		wid = jid.User
+	}
+
+	ctx, span := telemetry.StartMessageSpan("whatsmeow.receive", message, telemetry.SpanKindConsumer, telemetry.Wid(wid), telemetry.From(from))
+	message.TraceContext = ctx
+	defer span.End()
+
 	handler.Follow(message, from)
 }
 
@@ -0,0 +1,96 @@
+package whatsmeow
+
+import (
+	"time"
+
+	whatsapp "github.com/nocodeleaks/quepasa/whatsapp"
+	types "go.mau.fi/whatsmeow/types"
+	"go.mau.fi/whatsmeow/types/events"
+)
+
+// OnEventChatAction dispatches pin, mute, star, delete for me and clear chat app state events as system messages
+func OnEventChatAction(source *WhatsmeowHandlers, rawEvt interface{}) {
+	if source == nil || source.WAHandlers == nil || source.WAHandlers.IsInterfaceNil() {
+		return
+	}
+
+	logentry := source.GetLogger()
+
+	var jid types.JID
+	var timestamp time.Time
+	var fullSync bool
+	info := &whatsapp.WhatsappChatAction{}
+
+	switch evt := rawEvt.(type) {
+	case *events.Pin:
+		jid, timestamp, fullSync = evt.JID, evt.Timestamp, evt.FromFullSync
+		info.Action = whatsapp.ChatActionPin
+		info.Value = evt.Action.GetPinned()
+
+	case *events.Mute:
+		jid, timestamp, fullSync = evt.JID, evt.Timestamp, evt.FromFullSync
+		info.Action = whatsapp.ChatActionMute
+		info.Value = evt.Action.GetMuted()
+
+		// -1 or empty means muted forever
+		if until := evt.Action.GetMuteEndTimestamp(); info.Value && until > 0 {
+			mutedUntil := time.UnixMilli(until)
+			info.MutedUntil = &mutedUntil
+		}
+
+	case *events.Star:
+		jid, timestamp, fullSync = evt.ChatJID, evt.Timestamp, evt.FromFullSync
+		info.Action = whatsapp.ChatActionStar
+		info.Value = evt.Action.GetStarred()
+		info.MessageId = evt.MessageID
+		info.FromMe = evt.IsFromMe
+		if !evt.SenderJID.IsEmpty() {
+			info.Participant = evt.SenderJID.String()
+		}
+
+	case *events.DeleteForMe:
+		jid, timestamp, fullSync = evt.ChatJID, evt.Timestamp, evt.FromFullSync
+		info.Action = whatsapp.ChatActionDeleteForMe
+		info.Value = true
+		info.MessageId = evt.MessageID
+		info.FromMe = evt.IsFromMe
+		if !evt.SenderJID.IsEmpty() {
+			info.Participant = evt.SenderJID.String()
+		}
+
+	case *events.ClearChat:
+		jid, timestamp, fullSync = evt.JID, evt.Timestamp, evt.FromFullSync
+		info.Action = whatsapp.ChatActionClear
+		info.Value = true
+
+	default:
+		logentry.Warnf("unexpected chat action event: %T", rawEvt)
+		return
+	}
+
+	// full sync events are the current state of all chats, not actions made now
+	if fullSync {
+		logentry.Tracef("ignoring full sync chat action: %s, chat: %s", info.Action, jid.String())
+		return
+	}
+
+	logentry.Debugf("on event chat action: %s, chat: %s", info.Action, jid.String())
+
+	var id string
+	if source.Client != nil {
+		id = source.Client.GenerateMessageID()
+	}
+
+	message := &whatsapp.WhatsappMessage{
+		Id:        id,
+		Timestamp: timestamp,
+		Type:      whatsapp.SystemMessageType,
+		FromMe:    true,
+		Chat:      *NewWhatsappChat(source, jid),
+		Text:      info.Action,
+		Info:      info,
+	}
+
+	// following to internal handlers, not cached
+	source.WAHandlers.ChatAction(message)
+}
@@ -52,8 +52,22 @@ func HandleKnowingMessages(handler *WhatsmeowHandlers, out *whatsapp.WhatsappMes
 		HandleTemplateButtonReplyMessage(logentry, out, in.TemplateButtonReplyMessage)
 	case in.ContactsArrayMessage != nil:
 		HandleContactsArrayMessage(handler, logentry, out, in.ContactsArrayMessage)
+	case in.PollCreationMessage != nil:
+		HandlePollCreationMessage(logentry, out, in.PollCreationMessage)
+	case in.PollCreationMessageV2 != nil:
+		HandlePollCreationMessage(logentry, out, in.PollCreationMessageV2)
+	case in.PollCreationMessageV3 != nil:
+		HandlePollCreationMessage(logentry, out, in.PollCreationMessageV3)
+	case in.PollUpdateMessage != nil:
+		HandlePollUpdateMessage(handler, logentry, out, in.PollUpdateMessage)
 	case in.ListMessage != nil:
 		HandleListMessage(logentry, out, in.ListMessage)
+	case in.ListResponseMessage != nil:
+		HandleListResponseMessage(logentry, out, in.ListResponseMessage)
+	case in.InteractiveMessage != nil:
+		HandleInteractiveMessage(logentry, out, in.InteractiveMessage)
+	case in.InteractiveResponseMessage != nil:
+		HandleInteractiveResponseMessage(logentry, out, in.InteractiveResponseMessage)
 	case in.SenderKeyDistributionMessage != nil:
 
 		json := library.ToJson(in.SenderKeyDistributionMessage)
@@ -281,6 +295,12 @@ func HandleButtonsResponseMessage(log *log.Entry, out *whatsapp.WhatsappMessage,
 
 	out.Text = in.GetSelectedButtonID()
 
+	out.InteractiveReply = &whatsapp.WhatsappInteractiveReply{
+		Type: whatsapp.InteractiveReplyButton,
+		Id:   in.GetSelectedButtonID(),
+		Text: in.GetSelectedDisplayText(),
+	}
+
 	info := in.ContextInfo
 	if info != nil {
 		out.ForwardingScore = info.GetForwardingScore()
@@ -383,6 +403,9 @@ func HandleAudioMessage(log *log.Entry, out *whatsapp.WhatsappMessage, in *waE2E
 		Seconds:    in.GetSeconds(),
 	}
 
+	// voice note recorded on app
+	out.Attachment.SetPTTCompatible(in.GetPTT())
+
 	info := in.ContextInfo
 	if info != nil {
 		out.ForwardingScore = info.GetForwardingScore()
@@ -415,6 +438,18 @@ func HandleLocationMessage(logentry *log.Entry, out *whatsapp.WhatsappMessage, i
 		FileLength: length,
 	}
 
+	// venue information, if exists
+	if len(in.GetName()) > 0 || len(in.GetAddress()) > 0 || len(in.GetURL()) > 0 {
+		out.Text = in.GetName()
+		out.Location = &whatsapp.WhatsappLocation{
+			Latitude:  in.GetDegreesLatitude(),
+			Longitude: in.GetDegreesLongitude(),
+			Name:      in.GetName(),
+			Address:   in.GetAddress(),
+			Url:       in.GetURL(),
+		}
+	}
+
 	// handling thumbnail
 	out.Attachment.SetThumbnail(in.GetJPEGThumbnail())
 	out.Attachment.SetContent(&content)
@@ -452,6 +487,20 @@ func HandleLiveLocationMessage(logentry *log.Entry, out *whatsapp.WhatsappMessag
 		FileLength: length,
 	}
 
+	// origin and participant are defined by the live location tracker
+	out.LiveLocation = &whatsapp.WhatsappLiveLocation{
+		Origin:     out.Id,
+		Latitude:   in.GetDegreesLatitude(),
+		Longitude:  in.GetDegreesLongitude(),
+		Accuracy:   in.GetAccuracyInMeters(),
+		Speed:      in.GetSpeedInMps(),
+		Heading:    in.GetDegreesClockwiseFromMagneticNorth(),
+		Caption:    in.GetCaption(),
+		Sequence:   in.GetSequenceNumber(),
+		TimeOffset: in.GetTimeOffset(),
+		Timestamp:  out.Timestamp,
+	}
+
 	// handling thumbnail
 	out.Attachment.SetThumbnail(in.GetJPEGThumbnail())
 	out.Attachment.SetContent(&content)
@@ -0,0 +1,61 @@
+package whatsmeow
+
+import (
+	"encoding/json"
+
+	whatsapp "github.com/nocodeleaks/quepasa/whatsapp"
+	log "github.com/sirupsen/logrus"
+	"go.mau.fi/whatsmeow/proto/waE2E"
+)
+
+func HandleInteractiveMessage(logentry *log.Entry, out *whatsapp.WhatsappMessage, in *waE2E.InteractiveMessage) {
+	logentry.Trace("received an interactive message !")
+	out.Type = whatsapp.TextMessageType
+	out.Text = in.GetBody().GetText()
+
+	info := in.GetContextInfo()
+	if info != nil {
+		out.ForwardingScore = info.GetForwardingScore()
+		out.InReply = info.GetStanzaID()
+	}
+}
+
+// HandleInteractiveResponseMessage normalizes native flow responses (quick reply buttons and single select lists)
+func HandleInteractiveResponseMessage(logentry *log.Entry, out *whatsapp.WhatsappMessage, in *waE2E.InteractiveResponseMessage) {
+	logentry.Debug("received an interactive response message !")
+	out.Type = whatsapp.TextMessageType
+
+	reply := &whatsapp.WhatsappInteractiveReply{
+		Type: whatsapp.InteractiveReplyButton,
+		Text: in.GetBody().GetText(),
+	}
+
+	response := in.GetNativeFlowResponseMessage()
+	if response.GetName() == NativeFlowSingleSelect {
+		reply.Type = whatsapp.InteractiveReplyList
+	}
+
+	var params struct {
+		Id string `json:"id"`
+	}
+
+	err := json.Unmarshal([]byte(response.GetParamsJSON()), &params)
+	if err != nil {
+		logentry.Warnf("invalid native flow response params: %s", err.Error())
+	}
+
+	reply.Id = params.Id
+	out.InteractiveReply = reply
+
+	// keeping selected id as text, like buttons responses
+	out.Text = reply.Id
+	if len(out.Text) == 0 {
+		out.Text = reply.Text
+	}
+
+	info := in.GetContextInfo()
+	if info != nil {
+		out.ForwardingScore = info.GetForwardingScore()
+		out.InReply = info.GetStanzaID()
+	}
+}
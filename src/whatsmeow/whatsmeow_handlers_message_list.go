@@ -42,3 +42,23 @@ func HandleListMessage(logentry *log.Entry, out *whatsapp.WhatsappMessage, in *w
 		out.InReply = info.GetStanzaID()
 	}
 }
+
+func HandleListResponseMessage(logentry *log.Entry, out *whatsapp.WhatsappMessage, in *waE2E.ListResponseMessage) {
+	logentry.Debug("received a list response message !")
+	out.Type = whatsapp.TextMessageType
+
+	out.InteractiveReply = &whatsapp.WhatsappInteractiveReply{
+		Type: whatsapp.InteractiveReplyList,
+		Id:   in.GetSingleSelectReply().GetSelectedRowID(),
+		Text: in.GetTitle(),
+	}
+
+	// keeping selected id as text, like buttons responses
+	out.Text = out.InteractiveReply.Id
+
+	info := in.GetContextInfo()
+	if info != nil {
+		out.ForwardingScore = info.GetForwardingScore()
+		out.InReply = info.GetStanzaID()
+	}
+}
@@ -0,0 +1,72 @@
+package whatsmeow
+
+import (
+	"context"
+	"encoding/hex"
+
+	whatsapp "github.com/nocodeleaks/quepasa/whatsapp"
+	log "github.com/sirupsen/logrus"
+	"go.mau.fi/whatsmeow/proto/waE2E"
+	types "go.mau.fi/whatsmeow/types"
+	"go.mau.fi/whatsmeow/types/events"
+)
+
+func HandlePollCreationMessage(logentry *log.Entry, out *whatsapp.WhatsappMessage, in *waE2E.PollCreationMessage) {
+	logentry.Debug("received a poll creation message !")
+	out.Type = whatsapp.PollMessageType
+	out.Text = in.GetName()
+
+	poll := &whatsapp.WhatsappPoll{
+		Question:   in.GetName(),
+		Selections: uint(in.GetSelectableOptionsCount()),
+	}
+
+	for _, option := range in.GetOptions() {
+		poll.Options = append(poll.Options, option.GetOptionName())
+	}
+	out.Poll = poll
+
+	info := in.GetContextInfo()
+	if info != nil {
+		out.ForwardingScore = info.GetForwardingScore()
+		out.InReply = info.GetStanzaID()
+	}
+}
+
+// HandlePollUpdateMessage decrypts a poll vote with the poll secret, stored by whatsmeow when the poll was sent or received
+// Selected options are sha256 hashes of option names, resolved later with the persisted poll options
+func HandlePollUpdateMessage(handler *WhatsmeowHandlers, logentry *log.Entry, out *whatsapp.WhatsappMessage, in *waE2E.PollUpdateMessage) {
+	logentry.Debug("received a poll update message !")
+	out.Type = whatsapp.PollMessageType
+
+	pollId := in.GetPollCreationMessageKey().GetID()
+	out.InReply = pollId
+
+	info, ok := out.InfoForHistory.(types.MessageInfo)
+	if !ok || handler == nil || handler.Client == nil {
+		logentry.Warnf("poll vote can not be decrypted without message info and client, poll id: %s", pollId)
+		out.Type = whatsapp.UnhandledMessageType
+		out.Debug = &whatsapp.WhatsappMessageDebug{Event: "PollUpdateMessage", Info: in, Reason: "undecryptable"}
+		return
+	}
+
+	vote, err := handler.Client.DecryptPollVote(context.Background(), &events.Message{Info: info, Message: &waE2E.Message{PollUpdateMessage: in}})
+	if err != nil {
+		logentry.Warnf("failed to decrypt poll vote, poll id: %s, error: %s", pollId, err.Error())
+		out.Type = whatsapp.UnhandledMessageType
+		out.Debug = &whatsapp.WhatsappMessageDebug{Event: "PollUpdateMessage", Info: in, Reason: "undecryptable"}
+		return
+	}
+
+	result := &whatsapp.WhatsappPollVote{
+		PollId:  pollId,
+		Voter:   info.Sender.ToNonAD().String(),
+		Options: []string{},
+	}
+
+	for _, hash := range vote.GetSelectedOptions() {
+		result.Hashes = append(result.Hashes, hex.EncodeToString(hash))
+	}
+
+	out.PollVote = result
+}
@@ -34,6 +34,12 @@ func HandleTemplateButtonReplyMessage(logentry *log.Entry, out *whatsapp.Whatsap
 	text = fmt.Sprintf("%s%s", text, in.GetSelectedDisplayText())
 	out.Text = text
 
+	out.InteractiveReply = &whatsapp.WhatsappInteractiveReply{
+		Type: whatsapp.InteractiveReplyTemplate,
+		Id:   in.GetSelectedID(),
+		Text: in.GetSelectedDisplayText(),
+	}
+
 	info := in.GetContextInfo()
 	if info != nil {
 		out.ForwardingScore = info.GetForwardingScore()
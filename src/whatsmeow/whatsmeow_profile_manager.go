@@ -0,0 +1,125 @@
+package whatsmeow
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	whatsapp "github.com/nocodeleaks/quepasa/whatsapp"
+	whatsmeow "go.mau.fi/whatsmeow"
+	appstate "go.mau.fi/whatsmeow/appstate"
+	types "go.mau.fi/whatsmeow/types"
+)
+
+// Compile-time interface check
+var _ whatsapp.WhatsappProfileManagerInterface = (*WhatsmeowProfileManager)(nil)
+
+// WhatsmeowProfileManager handles own profile operations for WhatsmeowConnection
+type WhatsmeowProfileManager struct {
+	*WhatsmeowConnection // embedded connection for direct access
+}
+
+// NewWhatsmeowProfileManager creates a new WhatsmeowProfileManager instance
+func NewWhatsmeowProfileManager(conn *WhatsmeowConnection) *WhatsmeowProfileManager {
+	return &WhatsmeowProfileManager{
+		WhatsmeowConnection: conn,
+	}
+}
+
+// GetProfile returns own push name, about text and profile picture info
+func (pm *WhatsmeowProfileManager) GetProfile() (*whatsapp.WhatsappProfile, error) {
+	jid, err := pm.getOwnJID()
+	if err != nil {
+		return nil, err
+	}
+
+	profile := &whatsapp.WhatsappProfile{
+		Wid:      jid.String(),
+		PushName: pm.Client.Store.PushName,
+	}
+
+	infos, err := pm.Client.GetUserInfo([]types.JID{jid})
+	if err != nil {
+		return nil, fmt.Errorf("failed to get about text: %v", err)
+	}
+
+	if info, ok := infos[jid]; ok {
+		profile.About = info.Status
+	}
+
+	pictureInfo, err := pm.Client.GetProfilePictureInfo(jid, &whatsmeow.GetProfilePictureParams{})
+	if err != nil && !errors.Is(err, whatsmeow.ErrProfilePictureNotSet) {
+		return nil, fmt.Errorf("failed to get profile picture: %v", err)
+	}
+
+	if pictureInfo != nil {
+		profile.Picture = &whatsapp.WhatsappProfilePicture{
+			Id:     pictureInfo.ID,
+			Type:   pictureInfo.Type,
+			Url:    pictureInfo.URL,
+			ChatId: profile.Wid,
+			Wid:    profile.Wid,
+		}
+	}
+
+	return profile, nil
+}
+
+// SetPushName updates own display name through app state sync
+func (pm *WhatsmeowProfileManager) SetPushName(name string) error {
+	if _, err := pm.getOwnJID(); err != nil {
+		return err
+	}
+
+	err := pm.Client.SendAppState(context.TODO(), appstate.BuildSettingPushName(name))
+	if err != nil {
+		return fmt.Errorf("failed to update push name: %v", err)
+	}
+
+	pm.Client.Store.PushName = name
+	return nil
+}
+
+// SetAbout updates own about/status text
+func (pm *WhatsmeowProfileManager) SetAbout(text string) error {
+	if _, err := pm.getOwnJID(); err != nil {
+		return err
+	}
+
+	err := pm.Client.SetStatusMessage(text)
+	if err != nil {
+		return fmt.Errorf("failed to update about text: %v", err)
+	}
+	return nil
+}
+
+// SetProfilePicture updates own profile picture, without target the picture query applies to the logged account
+func (pm *WhatsmeowProfileManager) SetProfilePicture(jpeg []byte) (string, error) {
+	if _, err := pm.getOwnJID(); err != nil {
+		return "", err
+	}
+
+	if len(jpeg) == 0 {
+		return "", fmt.Errorf("empty picture content")
+	}
+
+	pictureID, err := pm.Client.SetGroupPhoto(types.EmptyJID, jpeg)
+	if err != nil {
+		return "", fmt.Errorf("failed to update profile picture: %v", err)
+	}
+
+	return pictureID, nil
+}
+
+// RemoveProfilePicture removes own profile picture
+func (pm *WhatsmeowProfileManager) RemoveProfilePicture() error {
+	if _, err := pm.getOwnJID(); err != nil {
+		return err
+	}
+
+	_, err := pm.Client.SetGroupPhoto(types.EmptyJID, nil)
+	if err != nil {
+		return fmt.Errorf("failed to remove profile picture: %v", err)
+	}
+	return nil
+}
@@ -2,6 +2,7 @@ package whatsmeow
 
 import (
 	"context"
+	"database/sql"
 	"fmt"
 	"strings"
 
@@ -23,6 +24,8 @@ type WhatsmeowServiceModel struct {
 	Container *sqlstore.Container
 	Options   WhatsmeowOptions
 
+	db *sql.DB // underlying store database, for session export and import
+
 	library.LogStruct
 }
 
@@ -56,7 +59,14 @@ func Start(options WhatsmeowOptions, dbParameters library.DatabaseParameters, lo
 		dbParameters.DataBase = "whatsmeow"
 	}
 	connectionString := dbParameters.GetConnectionString()
-	container, err := sqlstore.New(context.TODO(), dbParameters.Driver, connectionString, dbLog)
+	db, err := sql.Open(dbParameters.Driver, connectionString)
+	if err != nil {
+		err = fmt.Errorf("error on opening db: %s", err.Error())
+		panic(err)
+	}
+
+	container := sqlstore.NewWithDB(db, dbParameters.Driver, dbLog)
+	err = container.Upgrade(context.TODO())
 	if err != nil {
 		err = fmt.Errorf("error on creating db container: %s", err.Error())
 		panic(err)
@@ -65,6 +75,7 @@ func Start(options WhatsmeowOptions, dbParameters library.DatabaseParameters, lo
 	WhatsmeowService = &WhatsmeowServiceModel{
 		Container: container,
 		Options:   options,
+		db:        db,
 	}
 
 	// logging
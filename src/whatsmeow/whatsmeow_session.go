@@ -0,0 +1,263 @@
+package whatsmeow
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"regexp"
+)
+
+// Store tables holding the state of a device, in insertion order (foreign keys), with the column referencing the device jid.
+// whatsmeow_lid_map is shared between devices and rebuilt on demand, so it is not exported
+var WhatsmeowSessionTables = []WhatsmeowSessionTable{
+	{Name: "whatsmeow_device", Key: "jid"},
+	{Name: "whatsmeow_identity_keys", Key: "our_jid"},
+	{Name: "whatsmeow_pre_keys", Key: "jid"},
+	{Name: "whatsmeow_sessions", Key: "our_jid"},
+	{Name: "whatsmeow_sender_keys", Key: "our_jid"},
+	{Name: "whatsmeow_app_state_sync_keys", Key: "jid"},
+	{Name: "whatsmeow_app_state_version", Key: "jid"},
+	{Name: "whatsmeow_app_state_mutation_macs", Key: "jid"},
+	{Name: "whatsmeow_contacts", Key: "our_jid"},
+	{Name: "whatsmeow_chat_settings", Key: "our_jid"},
+	{Name: "whatsmeow_message_secrets", Key: "our_jid"},
+	{Name: "whatsmeow_privacy_tokens", Key: "our_jid"},
+	{Name: "whatsmeow_event_buffer", Key: "our_jid"},
+}
+
+var whatsmeowSessionColumnRegex = regexp.MustCompile(`^[a-z_]+$`)
+
+// WhatsmeowSessionTable holds the rows of a single device on a store table
+type WhatsmeowSessionTable struct {
+	Name    string                    `json:"name"`
+	Key     string                    `json:"key"` // column referencing the device jid
+	Columns []string                  `json:"columns,omitempty"`
+	Rows    [][]WhatsmeowSessionValue `json:"rows,omitempty"`
+}
+
+// WhatsmeowSession is a copy of all store rows of a device, enough to connect without pairing again
+type WhatsmeowSession struct {
+	Jid    string                   `json:"jid"`
+	Tables []*WhatsmeowSessionTable `json:"tables"`
+}
+
+// ExportSession copies all store rows of the device
+func (source *WhatsmeowServiceModel) ExportSession(ctx context.Context, jid string) (*WhatsmeowSession, error) {
+	if source.db == nil {
+		return nil, fmt.Errorf("store database not available")
+	}
+
+	tx, err := source.db.BeginTx(ctx, &sql.TxOptions{ReadOnly: true})
+	if err != nil {
+		return nil, err
+	}
+	defer tx.Rollback()
+
+	session := &WhatsmeowSession{Jid: jid}
+	for _, known := range WhatsmeowSessionTables {
+		table := &WhatsmeowSessionTable{Name: known.Name, Key: known.Key}
+		err = table.read(ctx, tx, jid)
+		if err != nil {
+			return nil, fmt.Errorf("error on reading %s: %s", known.Name, err.Error())
+		}
+
+		session.Tables = append(session.Tables, table)
+	}
+
+	if len(session.Tables[0].Rows) == 0 {
+		return nil, fmt.Errorf("device not found on store: %s", jid)
+	}
+
+	return session, nil
+}
+
+func (source *WhatsmeowSessionTable) read(ctx context.Context, tx *sql.Tx, jid string) error {
+	query := fmt.Sprintf("SELECT * FROM %s WHERE %s = $1", source.Name, source.Key)
+	rows, err := tx.QueryContext(ctx, query, jid)
+	if err != nil {
+		return err
+	}
+	defer rows.Close()
+
+	source.Columns, err = rows.Columns()
+	if err != nil {
+		return err
+	}
+
+	for rows.Next() {
+		values := make([]interface{}, len(source.Columns))
+		pointers := make([]interface{}, len(values))
+		for i := range values {
+			pointers[i] = &values[i]
+		}
+
+		err = rows.Scan(pointers...)
+		if err != nil {
+			return err
+		}
+
+		row := make([]WhatsmeowSessionValue, len(values))
+		for i, value := range values {
+			row[i] = WhatsmeowSessionValue{Value: value}
+		}
+		source.Rows = append(source.Rows, row)
+	}
+
+	return rows.Err()
+}
+
+// HasDevice returns true if the device is present on store
+func (source *WhatsmeowServiceModel) HasDevice(ctx context.Context, jid string) (bool, error) {
+	if source.db == nil {
+		return false, fmt.Errorf("store database not available")
+	}
+
+	var count int
+	err := source.db.QueryRowContext(ctx, "SELECT COUNT(*) FROM whatsmeow_device WHERE jid = $1", jid).Scan(&count)
+	return count > 0, err
+}
+
+// ImportSession replaces all store rows of the device by the given ones, in a single transaction
+func (source *WhatsmeowServiceModel) ImportSession(ctx context.Context, session *WhatsmeowSession) error {
+	if source.db == nil {
+		return fmt.Errorf("store database not available")
+	}
+
+	err := session.Validate()
+	if err != nil {
+		return err
+	}
+
+	tx, err := source.db.BeginTx(ctx, nil)
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+
+	// removing previous state
+	err = deleteSession(ctx, tx, session.Jid)
+	if err != nil {
+		return err
+	}
+
+	for _, table := range session.Tables {
+		err = table.write(ctx, tx)
+		if err != nil {
+			return fmt.Errorf("error on writing %s: %s", table.Name, err.Error())
+		}
+	}
+
+	return tx.Commit()
+}
+
+// DeleteSession removes all store rows of the device, in a single transaction, it can not connect anymore
+func (source *WhatsmeowServiceModel) DeleteSession(ctx context.Context, jid string) error {
+	if source.db == nil {
+		return fmt.Errorf("store database not available")
+	}
+
+	tx, err := source.db.BeginTx(ctx, nil)
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+
+	err = deleteSession(ctx, tx, jid)
+	if err != nil {
+		return err
+	}
+
+	return tx.Commit()
+}
+
+// deleteSession removes the device rows, reverse order, do not rely on cascade
+func deleteSession(ctx context.Context, tx *sql.Tx, jid string) error {
+	for i := len(WhatsmeowSessionTables) - 1; i >= 0; i-- {
+		known := WhatsmeowSessionTables[i]
+		query := fmt.Sprintf("DELETE FROM %s WHERE %s = $1", known.Name, known.Key)
+		_, err := tx.ExecContext(ctx, query, jid)
+		if err != nil {
+			return fmt.Errorf("error on cleaning %s: %s", known.Name, err.Error())
+		}
+	}
+	return nil
+}
+
+func (source *WhatsmeowSessionTable) write(ctx context.Context, tx *sql.Tx) error {
+	if len(source.Rows) == 0 {
+		return nil
+	}
+
+	placeholders := ""
+	columns := ""
+	for i, column := range source.Columns {
+		if i > 0 {
+			placeholders += ", "
+			columns += ", "
+		}
+		placeholders += fmt.Sprintf("$%d", i+1)
+		columns += column
+	}
+
+	query := fmt.Sprintf("INSERT INTO %s (%s) VALUES (%s)", source.Name, columns, placeholders)
+	for _, row := range source.Rows {
+		values := make([]interface{}, len(row))
+		for i, value := range row {
+			values[i] = value.Value
+		}
+
+		_, err := tx.ExecContext(ctx, query, values...)
+		if err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// Validate ensures that only known tables and rows of this device are present, archives are untrusted input
+func (source *WhatsmeowSession) Validate() error {
+	if len(source.Jid) == 0 {
+		return fmt.Errorf("missing device jid")
+	}
+
+	if len(source.Tables) != len(WhatsmeowSessionTables) {
+		return fmt.Errorf("invalid session tables, expected %d, got %d", len(WhatsmeowSessionTables), len(source.Tables))
+	}
+
+	for i, table := range source.Tables {
+		known := WhatsmeowSessionTables[i]
+		if table == nil || table.Name != known.Name || table.Key != known.Key {
+			return fmt.Errorf("unexpected session table at position %d, expected %s", i, known.Name)
+		}
+
+		key := -1
+		for c, column := range table.Columns {
+			if !whatsmeowSessionColumnRegex.MatchString(column) {
+				return fmt.Errorf("invalid column name on %s: %s", table.Name, column)
+			}
+			if column == table.Key {
+				key = c
+			}
+		}
+
+		if len(table.Rows) > 0 && key < 0 {
+			return fmt.Errorf("missing key column on %s: %s", table.Name, table.Key)
+		}
+
+		for _, row := range table.Rows {
+			if len(row) != len(table.Columns) {
+				return fmt.Errorf("invalid row length on %s", table.Name)
+			}
+
+			if fmt.Sprintf("%s", row[key].Value) != source.Jid {
+				return fmt.Errorf("row of another device on %s", table.Name)
+			}
+		}
+	}
+
+	if len(source.Tables[0].Rows) != 1 {
+		return fmt.Errorf("device not found on session")
+	}
+
+	return nil
+}
@@ -0,0 +1,72 @@
+package whatsmeow
+
+import (
+	"encoding/json"
+	"fmt"
+	"time"
+)
+
+// WhatsmeowSessionValue is a single column value of a store row, keeps the sql type through json
+type WhatsmeowSessionValue struct {
+	Value interface{}
+}
+
+type whatsmeowSessionValueJson struct {
+	Bytes *[]byte    `json:"b,omitempty"`
+	Int   *int64     `json:"i,omitempty"`
+	Float *float64   `json:"f,omitempty"`
+	Bool  *bool      `json:"o,omitempty"`
+	Text  *string    `json:"s,omitempty"`
+	Time  *time.Time `json:"d,omitempty"`
+}
+
+func (source WhatsmeowSessionValue) MarshalJSON() ([]byte, error) {
+	typed := whatsmeowSessionValueJson{}
+	switch value := source.Value.(type) {
+	case nil:
+		return []byte("null"), nil
+	case []byte:
+		typed.Bytes = &value
+	case int64:
+		typed.Int = &value
+	case float64:
+		typed.Float = &value
+	case bool:
+		typed.Bool = &value
+	case string:
+		typed.Text = &value
+	case time.Time:
+		typed.Time = &value
+	default:
+		return nil, fmt.Errorf("unsupported store value type: %T", value)
+	}
+	return json.Marshal(typed)
+}
+
+func (source *WhatsmeowSessionValue) UnmarshalJSON(data []byte) error {
+	var typed *whatsmeowSessionValueJson
+	err := json.Unmarshal(data, &typed)
+	if err != nil {
+		return err
+	}
+
+	switch {
+	case typed == nil:
+		source.Value = nil
+	case typed.Bytes != nil:
+		source.Value = *typed.Bytes
+	case typed.Int != nil:
+		source.Value = *typed.Int
+	case typed.Float != nil:
+		source.Value = *typed.Float
+	case typed.Bool != nil:
+		source.Value = *typed.Bool
+	case typed.Text != nil:
+		source.Value = *typed.Text
+	case typed.Time != nil:
+		source.Value = *typed.Time
+	default:
+		return fmt.Errorf("invalid store value: %s", string(data))
+	}
+	return nil
+}